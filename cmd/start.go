@@ -16,19 +16,34 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 
 	"github.com/spf13/cobra"
 	"github.com/docker/docker/client"
+	copts "github.com/openconfig/containerz/containers"
 	"github.com/openconfig/containerz/containers/docker"
+	"github.com/openconfig/containerz/containers/podman"
 	"github.com/openconfig/containerz/server"
 )
 
 var (
-	dockerHost string
-	chunkSize  int
-	useALTS    bool
+	containerRuntime string
+	dockerHost       string
+	chunkSize        int
+	useALTS          bool
+	allowPrivileged  bool
+	allowDockerSock  bool
+	checkCapacity    bool
+	secretsDir       string
+	tenantLabel      string
+	namePrefix       string
+
+	requiredImageLabels map[string]string
+
+	privilegedPortThreshold uint32
+	imagePullPolicy         string
 )
 
 var startCmd = &cobra.Command{
@@ -39,22 +54,77 @@ var startCmd = &cobra.Command{
 		ctx, cancel := context.WithCancel(command.Context())
 		defer cancel()
 
-		cli, err := client.NewClientWithOpts(client.WithHost(dockerHost), client.WithAPIVersionNegotiation())
+		host := dockerHost
+		if containerRuntime == "podman" && !command.Flags().Changed("docker_host") {
+			host = podman.Socket()
+		}
+
+		cli, err := client.NewClientWithOpts(client.WithHost(host), client.WithAPIVersionNegotiation())
 		if err != nil {
 			return err
 		}
+		// Negotiate the API version up front; the client is then shared by every operation.
+		cli.NegotiateAPIVersion(ctx)
 
 		opts := []server.Option{
 			server.WithAddr(addr),
 			server.WithChunkSize(chunkSize),
+			server.WithPrivilegedPortThreshold(privilegedPortThreshold),
+		}
+
+		switch policy := copts.PullPolicy(imagePullPolicy); policy {
+		case "":
+		case copts.PullNever, copts.PullIfNotPresent, copts.PullAlways:
+			opts = append(opts, server.WithImagePullPolicy(policy))
+		default:
+			return fmt.Errorf("unsupported image pull policy %q", imagePullPolicy)
 		}
 
 		if useALTS {
 			opts = append(opts, server.UseALTS())
 		}
 
-		mgr := docker.New(cli)
-		s := server.New(docker.New(cli), opts...)
+		var mgrOpts []docker.Option
+		if allowPrivileged {
+			mgrOpts = append(mgrOpts, docker.WithAllowPrivileged())
+		}
+		if allowDockerSock {
+			mgrOpts = append(mgrOpts, docker.WithAllowDockerSocket())
+		}
+		if checkCapacity {
+			mgrOpts = append(mgrOpts, docker.WithCapacityCheck())
+		}
+		if secretsDir != "" {
+			mgrOpts = append(mgrOpts, docker.WithSecretsDir(secretsDir))
+		}
+		if tenantLabel != "" {
+			mgrOpts = append(mgrOpts, docker.WithTenantLabel(tenantLabel))
+		}
+		if len(requiredImageLabels) > 0 {
+			mgrOpts = append(mgrOpts, docker.WithRequiredImageLabels(requiredImageLabels))
+		}
+		if namePrefix != "" {
+			if err := docker.CheckNamePrefix(namePrefix); err != nil {
+				return err
+			}
+			mgrOpts = append(mgrOpts, docker.WithNamePrefix(namePrefix))
+		}
+
+		var mgr interface {
+			Start(context.Context) error
+			Stop(context.Context) error
+		}
+		var s *server.Server
+		switch containerRuntime {
+		case "docker":
+			dm := docker.New(cli, mgrOpts...)
+			mgr, s = dm, server.New(dm, opts...)
+		case "podman":
+			pm := podman.New(cli, mgrOpts...)
+			mgr, s = pm, server.New(pm, opts...)
+		default:
+			return fmt.Errorf("unsupported container runtime %q", containerRuntime)
+		}
 		mgr.Start(ctx)
 
 		// listen for ctrl-c
@@ -73,7 +143,17 @@ var startCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(startCmd)
-	startCmd.PersistentFlags().StringVar(&dockerHost, "docker_host", "unix:///var/run/docker.sock", "Docker host to connect to.")
+	startCmd.PersistentFlags().StringVar(&containerRuntime, "runtime", "docker", "Container runtime to manage, either docker or podman.")
+	startCmd.PersistentFlags().StringVar(&dockerHost, "docker_host", "unix:///var/run/docker.sock", "Docker host to connect to. Defaults to the podman socket when the runtime is podman.")
 	startCmd.PersistentFlags().IntVar(&chunkSize, "chunk_size", 3000000, "the size of the chunks supported by this server")
 	startCmd.PersistentFlags().BoolVar(&useALTS, "use_alts", false, "Use ALTS authentication.")
+	startCmd.PersistentFlags().BoolVar(&allowPrivileged, "allow_privileged", false, "Allow containers to be started in privileged mode.")
+	startCmd.PersistentFlags().BoolVar(&allowDockerSock, "allow_docker_socket", false, "Allow containers to bind mount the docker socket when the request acknowledges it.")
+	startCmd.PersistentFlags().BoolVar(&checkCapacity, "check_capacity", false, "Reject containers requesting more CPU or memory than the host has.")
+	startCmd.PersistentFlags().StringVar(&secretsDir, "secrets_dir", "", "Directory, on a tmpfs, holding the files of container secrets. Defaults to /run/containerz/secrets.")
+	startCmd.PersistentFlags().StringVar(&tenantLabel, "tenant_label", "", "Container label whose value scopes the uniqueness of instance names to a tenant. Unset, instance names are unique among all containers.")
+	startCmd.PersistentFlags().StringVar(&namePrefix, "container_name_prefix", "", "Prefix of the names of the containers created, e.g. cz_, hidden from instance names. Unset, containers are named after their instance.")
+	startCmd.PersistentFlags().StringToStringVar(&requiredImageLabels, "required_image_label", nil, "Label, as key=value, images must carry to start containers. An empty value only requires the label to be present. May be repeated.")
+	startCmd.PersistentFlags().Uint32Var(&privilegedPortThreshold, "privileged_port_threshold", 0, "Reject containers binding host ports below this value. 0 disables the check.")
+	startCmd.PersistentFlags().StringVar(&imagePullPolicy, "image_pull_policy", "", "Whether to pull images before starting containers: Always, IfNotPresent or Never. Defaults to Never.")
 }
@@ -0,0 +1,391 @@
+package containerd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/google/shlex"
+	options "github.com/openconfig/containerz/containers"
+	cpb "github.com/openconfig/gnoi/containerz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxPort is the largest port number.
+	maxPort = 65535
+	// maximumStopTimeout caps the time a forced stop waits before killing the container, as in the
+	// docker manager.
+	maximumStopTimeout = 10
+)
+
+// instanceNameRE matches the container names accepted by nerdctl. Names may not start with a
+// hyphen, so they are never mistaken for flags.
+var instanceNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// pullPolicies maps pull policies to the values of the nerdctl --pull flag.
+var pullPolicies = map[options.PullPolicy]string{
+	"":                       "never",
+	options.PullNever:        "never",
+	options.PullIfNotPresent: "missing",
+	options.PullAlways:       "always",
+}
+
+// psEntry is a container as listed by nerdctl ps.
+type psEntry struct {
+	ID     string
+	Image  string
+	Names  string
+	Status string
+}
+
+// running reports whether the container is running.
+func (e psEntry) running() bool {
+	return strings.HasPrefix(e.Status, "Up")
+}
+
+// containerStatus returns the status of a container listed with the provided nerdctl status.
+func containerStatus(state string) cpb.ListContainerResponse_Status {
+	switch {
+	case strings.HasPrefix(state, "Up"):
+		return cpb.ListContainerResponse_RUNNING
+	case strings.HasPrefix(state, "Exited"), strings.HasPrefix(state, "Created"):
+		return cpb.ListContainerResponse_STOPPED
+	default:
+		return cpb.ListContainerResponse_UNSPECIFIED
+	}
+}
+
+// ContainerStart starts a container from the image and tag, running cmd if set. The options
+// translated are the instance name, environment, ports, labels, network, volumes, the CPU and
+// memory limits and the pull policy; any other option is rejected with Unimplemented.
+func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string, opts ...options.Option) (string, error) {
+	optionz := options.ApplyOptions(opts...)
+	if err := checkOptions(optionz, "InstanceName", "EnvMapping", "PortMapping", "Ports", "Labels", "Network",
+		"Volumes", "CPU", "SoftMemory", "HardMemory", "PullPolicy", "PrivilegedPortThreshold", "AllowPrivilegedPorts"); err != nil {
+		return "", err
+	}
+
+	ref := imageName
+	if tag != "" {
+		ref = fmt.Sprintf("%s:%s", imageName, tag)
+	}
+	if _, err := reference.ParseNormalizedNamed(ref); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid image reference %q: %v", ref, err)
+	}
+	splitCmd, err := shlex.Split(cmd)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "unable to parse command %q: %v", cmd, err)
+	}
+	pull, ok := pullPolicies[optionz.PullPolicy]
+	if !ok {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported pull policy %q", optionz.PullPolicy)
+	}
+
+	args := []string{"run", "--detach", "--pull", pull, "--label", managedByLabel + "=" + managedByValue}
+	if instance := optionz.InstanceName; instance != "" {
+		if !instanceNameRE.MatchString(instance) {
+			return "", status.Errorf(codes.InvalidArgument, "invalid instance name %q", instance)
+		}
+		if _, ok, err := m.findContainer(ctx, instance); err != nil {
+			return "", err
+		} else if ok {
+			return "", status.Errorf(codes.AlreadyExists, "instance name %s already in use", instance)
+		}
+		args = append(args, "--name", instance)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(optionz.EnvMapping)) {
+		args = append(args, "--env", name+"="+optionz.EnvMapping[name])
+	}
+
+	ports, err := portFlags(optionz.PortMapping, optionz.Ports, optionz.PrivilegedPortThreshold, optionz.AllowPrivilegedPorts)
+	if err != nil {
+		return "", err
+	}
+	for _, port := range ports {
+		args = append(args, "--publish", port)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(optionz.Labels)) {
+		if key == managedByLabel {
+			continue
+		}
+		args = append(args, "--label", key+"="+optionz.Labels[key])
+	}
+
+	if optionz.Network != "" {
+		args = append(args, "--network", optionz.Network)
+	}
+
+	for _, vol := range optionz.Volumes {
+		if vol.GetName() == "" || vol.GetMountPoint() == "" {
+			return "", status.Errorf(codes.InvalidArgument, "volume %q must have a name and a mount point", vol.GetName())
+		}
+		volume := vol.GetName() + ":" + vol.GetMountPoint()
+		if vol.GetReadOnly() {
+			volume += ":ro"
+		}
+		args = append(args, "--volume", volume)
+	}
+
+	switch {
+	case optionz.CPU < 0:
+		return "", status.Errorf(codes.InvalidArgument, "cpu limit can not be negative, got %v", optionz.CPU)
+	case optionz.CPU > 0:
+		args = append(args, "--cpus", strconv.FormatFloat(optionz.CPU, 'f', -1, 64))
+	}
+	switch {
+	case optionz.HardMemory < 0, optionz.SoftMemory < 0:
+		return "", status.Errorf(codes.InvalidArgument, "memory limits can not be negative")
+	case optionz.HardMemory > 0 && optionz.SoftMemory > optionz.HardMemory:
+		return "", status.Errorf(codes.InvalidArgument, "soft memory limit %d exceeds hard memory limit %d", optionz.SoftMemory, optionz.HardMemory)
+	}
+	if optionz.HardMemory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(optionz.HardMemory, 10))
+	}
+	if optionz.SoftMemory > 0 {
+		args = append(args, "--memory-reservation", strconv.FormatInt(optionz.SoftMemory, 10))
+	}
+
+	// nerdctl stops parsing flags at the image, so the command is passed as is.
+	args = append(append(args, ref), splitCmd...)
+	out, err := m.client.Run(ctx, args...)
+	if err != nil {
+		return "", contextError(ctx, status.Errorf(codes.Internal, "unable to start container: %v", err))
+	}
+
+	if optionz.InstanceName != "" {
+		return optionz.InstanceName, nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// portFlags returns the values of the nerdctl --publish flags of the port mappings, checking them
+// as the docker manager does: ports must be valid, published once per host address and
+// protocol, and not below the privileged port threshold unless privileged ports are allowed.
+func portFlags(tcp map[uint32]uint32, ports []options.Port, threshold uint32, allowPrivileged bool) ([]string, error) {
+	mappings := make([]options.Port, 0, len(tcp)+len(ports))
+	for _, in := range slices.Sorted(maps.Keys(tcp)) {
+		mappings = append(mappings, options.Port{Internal: in, External: tcp[in]})
+	}
+	mappings = append(mappings, ports...)
+
+	published := map[string]bool{}
+	flags := make([]string, 0, len(mappings))
+	for _, port := range mappings {
+		proto := strings.ToLower(port.Protocol)
+		switch proto {
+		case "":
+			proto = "tcp"
+		case "tcp", "udp", "sctp":
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported protocol %q for port %d: expected tcp, udp or sctp", port.Protocol, port.Internal)
+		}
+		switch {
+		case port.Internal == 0 || port.Internal > maxPort:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid container port %d: must be between 1 and %d", port.Internal, maxPort)
+		case port.External == 0 || port.External > maxPort:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid host port %d: must be between 1 and %d", port.External, maxPort)
+		case port.External < threshold && !allowPrivileged:
+			return nil, status.Errorf(codes.PermissionDenied, "host port %d is privileged: ports below %d are not allowed", port.External, threshold)
+		case port.HostIP != "" && net.ParseIP(port.HostIP) == nil:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid host IP %q for port %d", port.HostIP, port.Internal)
+		}
+
+		host := strconv.FormatUint(uint64(port.External), 10)
+		if port.HostIP != "" {
+			host = net.JoinHostPort(port.HostIP, host)
+		}
+		name := host + "/" + proto
+		if published[name] {
+			return nil, status.Errorf(codes.InvalidArgument, "host port %s is published more than once", name)
+		}
+		published[name] = true
+		flag := fmt.Sprintf("%s:%d/%s", host, port.Internal, proto)
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// ContainerStop stops a container. The container is sent the stop signal, if set, and killed if
+// it has not stopped once the stop grace period elapses. Without a grace period, a forced stop
+// waits half of the time left before the context deadline, up to 10 seconds, and other stops
+// wait for the nerdctl default.
+func (m *Manager) ContainerStop(ctx context.Context, instance string, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+	if err := checkOptions(optionz, "Force", "StopGracePeriod", "StopSignal"); err != nil {
+		return err
+	}
+	if optionz.StopGracePeriod < 0 {
+		return status.Errorf(codes.InvalidArgument, "stop grace period can not be negative, got %d", optionz.StopGracePeriod)
+	}
+
+	cnt, ok, err := m.findContainer(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return status.Errorf(codes.NotFound, "container %s was not found", instance)
+	}
+
+	args := []string{"stop"}
+	switch {
+	case optionz.StopGracePeriod > 0:
+		args = append(args, "--time", strconv.Itoa(optionz.StopGracePeriod))
+	case optionz.Force:
+		if deadline, ok := ctx.Deadline(); ok {
+			// Half of the time left, so that the RPC has not expired once the stop returns.
+			timeout := min(int(time.Until(deadline)/time.Second)/2, maximumStopTimeout)
+			args = append(args, "--time", strconv.Itoa(timeout))
+		}
+	}
+	if optionz.StopSignal != "" {
+		args = append(args, "--signal", optionz.StopSignal)
+	}
+
+	if _, err := m.client.Run(ctx, append(args, cnt.ID)...); err != nil {
+		return contextError(ctx, status.Errorf(codes.Unknown, "failed to stop container %s with error %s", instance, err))
+	}
+	return nil
+}
+
+// ContainerRemove removes a container provided it is not running, unless the Force option is set.
+// The anonymous volumes of the container are removed with it if the RemoveVolumes option is set.
+func (m *Manager) ContainerRemove(ctx context.Context, instance string, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+	if err := checkOptions(optionz, "Force", "RemoveVolumes"); err != nil {
+		return err
+	}
+
+	cnt, ok, err := m.findContainer(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return status.Errorf(codes.NotFound, "container %s not found", instance)
+	}
+	if cnt.running() && !optionz.Force {
+		return status.Errorf(codes.FailedPrecondition, "container %s is running", instance)
+	}
+
+	args := []string{"rm"}
+	if optionz.Force {
+		args = append(args, "--force")
+	}
+	if optionz.RemoveVolumes {
+		args = append(args, "--volumes")
+	}
+	if _, err := m.client.Run(ctx, append(args, cnt.ID)...); err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to remove container: %v", err))
+	}
+	return nil
+}
+
+// ContainerList lists the containers present on the target, ordered by name. Containers may be
+// filtered by label, in key or key=value form, and by state.
+func (m *Manager) ContainerList(ctx context.Context, all bool, limit int32, srv options.ListContainerStreamer, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+	if err := checkOptions(optionz, "Filter"); err != nil {
+		return err
+	}
+
+	var args []string
+	for _, key := range slices.Sorted(maps.Keys(optionz.Filter)) {
+		for _, value := range optionz.Filter[key] {
+			switch key {
+			case options.Label:
+				if k, _, _ := strings.Cut(value, "="); k == "" {
+					return status.Errorf(codes.InvalidArgument, "malformed label filter %q: expected key or key=value", value)
+				}
+				args = append(args, "--filter", "label="+value)
+			case options.State:
+				args = append(args, "--filter", "status="+strings.ToLower(value))
+			default:
+				return status.Errorf(codes.Unimplemented, "filter %s is not supported by containerd", key)
+			}
+		}
+	}
+	if limit > 0 {
+		args = append(args, "--last", strconv.Itoa(int(limit)))
+	}
+
+	cnts, err := m.listContainers(ctx, all, args...)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(cnts, func(i, j int) bool { return cnts[i].Names < cnts[j].Names })
+
+	for _, cnt := range cnts {
+		if err := srv.Send(&cpb.ListContainerResponse{
+			Id:        cnt.ID,
+			Name:      cnt.Names,
+			ImageName: cnt.Image,
+			Status:    containerStatus(cnt.Status),
+		}); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// listContainers lists the containers with nerdctl ps, passing it args. Only running containers
+// are listed unless all is set.
+func (m *Manager) listContainers(ctx context.Context, all bool, args ...string) ([]psEntry, error) {
+	args = append([]string{"ps", "--no-trunc", "--format", "{{json .}}"}, args...)
+	if all {
+		args = append(args, "--all")
+	}
+	out, err := m.client.Run(ctx, args...)
+	if err != nil {
+		return nil, contextError(ctx, status.Errorf(codes.Internal, "unable to list containers: %v", err))
+	}
+
+	var cnts []psEntry
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var cnt psEntry
+		if err := json.Unmarshal(line, &cnt); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to parse container list: %v", err)
+		}
+		cnts = append(cnts, cnt)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to read container list: %v", err)
+	}
+	return cnts, nil
+}
+
+// findContainer returns the container named instance, whatever its state, and whether it exists.
+func (m *Manager) findContainer(ctx context.Context, instance string) (psEntry, bool, error) {
+	cnts, err := m.listContainers(ctx, true)
+	if err != nil {
+		return psEntry{}, false, err
+	}
+	for _, cnt := range cnts {
+		if cnt.Names == instance {
+			return cnt, true, nil
+		}
+	}
+	return psEntry{}, false, nil
+}
@@ -0,0 +1,287 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	cpb "github.com/openconfig/gnoi/containerz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+const psOutput = `{"ID":"web-id","Image":"docker.io/library/nginx:latest","Names":"web","Status":"Up"}
+{"ID":"job-id","Image":"docker.io/library/busybox:latest","Names":"job","Status":"Exited (0) 5 seconds ago"}
+`
+
+type fakeListStreamer struct {
+	msgs []*cpb.ListContainerResponse
+}
+
+func (f *fakeListStreamer) Send(msg *cpb.ListContainerResponse) error {
+	f.msgs = append(f.msgs, msg)
+	return nil
+}
+
+func TestContainerStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		inImage  string
+		inTag    string
+		inCmd    string
+		inOpts   []options.Option
+		inOutput string
+		wantName string
+		wantRun  []string
+		wantErr  error
+	}{
+		{
+			name:     "no-options",
+			inImage:  "nginx",
+			inTag:    "latest",
+			inOutput: "0123456789abcdef\n",
+			wantName: "0123456789abcdef",
+			wantRun:  []string{"run --detach --pull never --label managed-by=containerz nginx:latest"},
+		},
+		{
+			name:    "translated-options",
+			inImage: "nginx",
+			inTag:   "latest",
+			inCmd:   `sh -c "echo hello"`,
+			inOpts: []options.Option{
+				options.WithInstanceName("my-instance"),
+				options.WithEnv(map[string]string{"B": "2", "A": "1"}),
+				options.WithPorts(map[uint32]uint32{80: 8080}),
+				options.WithPortMappings([]options.Port{{Internal: 53, External: 5353, Protocol: "udp", HostIP: "10.0.0.1"}}),
+				options.WithLabels(map[string]string{"team": "edge", managedByLabel: "someone-else"}),
+				options.WithNetwork("my-network"),
+				options.WithVolumes([]*cpb.Volume{{Name: "my-volume", MountPoint: "/data", ReadOnly: true}}),
+				options.WithCPUs(1.5),
+				options.WithHardLimit(1 << 30),
+				options.WithSoftLimit(1 << 29),
+				options.WithPullPolicy(options.PullIfNotPresent),
+			},
+			wantName: "my-instance",
+			wantRun: []string{"run --detach --pull missing --label managed-by=containerz --name my-instance --env A=1 --env B=2 " +
+				"--publish 8080:80/tcp --publish 10.0.0.1:5353:53/udp --label team=edge --network my-network --volume my-volume:/data:ro " +
+				"--cpus 1.5 --memory 1073741824 --memory-reservation 536870912 nginx:latest sh -c echo hello"},
+		},
+		{
+			name:    "unsupported-option",
+			inImage: "nginx",
+			inOpts:  []options.Option{options.WithPrivileged(true)},
+			wantErr: status.Error(codes.Unimplemented, "option Privileged is not supported by containerd"),
+		},
+		{
+			name:    "instance-in-use",
+			inImage: "nginx",
+			inOpts:  []options.Option{options.WithInstanceName("job")},
+			wantErr: status.Error(codes.AlreadyExists, "instance name job already in use"),
+		},
+		{
+			name:    "flag-like-instance",
+			inImage: "nginx",
+			inOpts:  []options.Option{options.WithInstanceName("--privileged")},
+			wantErr: status.Error(codes.InvalidArgument, `invalid instance name "--privileged"`),
+		},
+		{
+			name:    "privileged-port",
+			inImage: "nginx",
+			inOpts: []options.Option{
+				options.WithPorts(map[uint32]uint32{80: 80}),
+				options.WithPrivilegedPortThreshold(1024),
+			},
+			wantErr: status.Error(codes.PermissionDenied, "host port 80 is privileged: ports below 1024 are not allowed"),
+		},
+		{
+			name:    "port-published-twice",
+			inImage: "nginx",
+			inOpts: []options.Option{
+				options.WithPorts(map[uint32]uint32{80: 8080}),
+				options.WithPortMappings([]options.Port{{Internal: 81, External: 8080}}),
+			},
+			wantErr: status.Error(codes.InvalidArgument, "host port 8080/tcp is published more than once"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := &fakeNerdctl{outputs: map[string]string{"ps": psOutput, "run": tc.inOutput}}
+			mgr := New(fn)
+
+			name, err := mgr.ContainerStart(context.Background(), tc.inImage, tc.inTag, tc.inCmd, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if name != tc.wantName {
+				t.Errorf("ContainerStart() returned name %q, want %q", name, tc.wantName)
+			}
+			if diff := cmp.Diff(tc.wantRun, fn.commands("run")); diff != "" {
+				t.Errorf("ContainerStart() ran diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContainerStop(t *testing.T) {
+	tests := []struct {
+		name       string
+		inInstance string
+		inOpts     []options.Option
+		inErr      error
+		wantStop   []string
+		wantErr    error
+	}{
+		{
+			name:       "stop",
+			inInstance: "web",
+			wantStop:   []string{"stop web-id"},
+		},
+		{
+			name:       "grace-period-and-signal",
+			inInstance: "web",
+			inOpts:     []options.Option{options.WithStopGracePeriod(30), options.WithStopSignal("SIGINT")},
+			wantStop:   []string{"stop --time 30 --signal SIGINT web-id"},
+		},
+		{
+			name:       "negative-grace-period",
+			inInstance: "web",
+			inOpts:     []options.Option{options.WithStopGracePeriod(-1)},
+			wantErr:    status.Error(codes.InvalidArgument, "stop grace period can not be negative, got -1"),
+		},
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Error(codes.NotFound, "container no-such-instance was not found"),
+		},
+		{
+			name:       "stop-failure",
+			inInstance: "web",
+			inErr:      fmt.Errorf("nerdctl stop: exit status 1: timed out"),
+			wantStop:   []string{"stop web-id"},
+			wantErr:    status.Error(codes.Unknown, "failed to stop container web with error nerdctl stop: exit status 1: timed out"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := &fakeNerdctl{outputs: map[string]string{"ps": psOutput}, errs: map[string]error{"stop": tc.inErr}}
+			mgr := New(fn)
+
+			err := mgr.ContainerStop(context.Background(), tc.inInstance, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerStop() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantStop, fn.commands("stop")); diff != "" {
+				t.Errorf("ContainerStop() ran diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContainerRemove(t *testing.T) {
+	tests := []struct {
+		name       string
+		inInstance string
+		inOpts     []options.Option
+		wantRm     []string
+		wantErr    error
+	}{
+		{
+			name:       "stopped",
+			inInstance: "job",
+			wantRm:     []string{"rm job-id"},
+		},
+		{
+			name:       "running",
+			inInstance: "web",
+			wantErr:    status.Error(codes.FailedPrecondition, "container web is running"),
+		},
+		{
+			name:       "forced-with-volumes",
+			inInstance: "web",
+			inOpts:     []options.Option{options.Force(), options.WithRemoveVolumes()},
+			wantRm:     []string{"rm --force --volumes web-id"},
+		},
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Error(codes.NotFound, "container no-such-instance not found"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := &fakeNerdctl{outputs: map[string]string{"ps": psOutput}}
+			mgr := New(fn)
+
+			err := mgr.ContainerRemove(context.Background(), tc.inInstance, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerRemove() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRm, fn.commands("rm")); diff != "" {
+				t.Errorf("ContainerRemove() ran diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContainerList(t *testing.T) {
+	tests := []struct {
+		name     string
+		inAll    bool
+		inLimit  int32
+		inOpts   []options.Option
+		wantPs   []string
+		wantMsgs []*cpb.ListContainerResponse
+		wantErr  error
+	}{
+		{
+			name:   "all-with-filters",
+			inAll:  true,
+			inOpts: []options.Option{options.WithFilter(map[options.FilterKey][]string{options.Label: {"team=edge"}, options.State: {"Running"}})},
+			wantPs: []string{"ps --no-trunc --format {{json .}} --filter label=team=edge --filter status=running --all"},
+			wantMsgs: []*cpb.ListContainerResponse{
+				{Id: "job-id", Name: "job", ImageName: "docker.io/library/busybox:latest", Status: cpb.ListContainerResponse_STOPPED},
+				{Id: "web-id", Name: "web", ImageName: "docker.io/library/nginx:latest", Status: cpb.ListContainerResponse_RUNNING},
+			},
+		},
+		{
+			name:    "limit",
+			inLimit: 1,
+			wantPs:  []string{"ps --no-trunc --format {{json .}} --last 1"},
+			wantMsgs: []*cpb.ListContainerResponse{
+				{Id: "job-id", Name: "job", ImageName: "docker.io/library/busybox:latest", Status: cpb.ListContainerResponse_STOPPED},
+				{Id: "web-id", Name: "web", ImageName: "docker.io/library/nginx:latest", Status: cpb.ListContainerResponse_RUNNING},
+			},
+		},
+		{
+			name:    "malformed-label-filter",
+			inOpts:  []options.Option{options.WithFilter(map[options.FilterKey][]string{options.Label: {"=edge"}})},
+			wantErr: status.Error(codes.InvalidArgument, `malformed label filter "=edge": expected key or key=value`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fn := &fakeNerdctl{outputs: map[string]string{"ps": psOutput}}
+			mgr := New(fn)
+
+			srv := &fakeListStreamer{}
+			err := mgr.ContainerList(context.Background(), tc.inAll, tc.inLimit, srv, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerList() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantPs, fn.commands("ps")); diff != "" {
+				t.Errorf("ContainerList() ran diff(-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantMsgs, srv.msgs, protocmp.Transform()); diff != "" {
+				t.Errorf("ContainerList() returned diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
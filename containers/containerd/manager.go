@@ -0,0 +1,110 @@
+// Package containerd implements a container manager for containerd orchestration. Containerd is
+// driven through nerdctl, its docker-compatible command line, so that the manager needs no
+// containerd client. Only the lifecycle operations of options.ContainerManager are provided, and
+// options are translated into nerdctl flags here since they differ from those of the docker API.
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultNamespace is the containerd namespace of the containers started by nerdctl unless
+	// configured otherwise.
+	DefaultNamespace = "default"
+
+	// managedByLabel and managedByValue mark the containers started by containerz, as the docker
+	// manager does.
+	managedByLabel = "managed-by"
+	managedByValue = "containerz"
+)
+
+// Client runs nerdctl commands.
+type Client interface {
+	// Run runs nerdctl with args and returns its standard output. A command that fails returns
+	// an error holding its standard error.
+	Run(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewCLI returns a Client running the nerdctl binary at path against the containerd namespace.
+func NewCLI(path, namespace string) Client {
+	return &cli{path: path, namespace: namespace}
+}
+
+// cli runs the nerdctl binary.
+type cli struct {
+	path      string
+	namespace string
+}
+
+// Run runs nerdctl in the namespace of the client.
+func (c *cli) Run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, c.path, append([]string{"--namespace", c.namespace}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("nerdctl %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// Manager is a containerd container orchestration manager.
+type Manager struct {
+	client Client
+}
+
+var _ options.ContainerManager = (*Manager)(nil)
+
+// New builds a new containerd manager given a nerdctl client.
+func New(cli Client) *Manager {
+	return &Manager{client: cli}
+}
+
+// Start ensures that containerd can be reached through nerdctl.
+func (m *Manager) Start(ctx context.Context) error {
+	if _, err := m.client.Run(ctx, "version"); err != nil {
+		return status.Errorf(codes.Unavailable, "unable to reach containerd: %v", err)
+	}
+	return nil
+}
+
+// Stop is a no-op: nerdctl holds no connection to containerd between commands.
+func (m *Manager) Stop(context.Context) error {
+	return nil
+}
+
+// contextError returns the RPC error matching ctx if it is done, and err otherwise.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
+}
+
+// checkOptions returns an Unimplemented error naming the first option set in optionz that is not
+// one of supported, the options the operation translates. Options are rejected rather than
+// silently ignored so that a container is never started differently than requested.
+func checkOptions(optionz any, supported ...string) error {
+	v := reflect.Indirect(reflect.ValueOf(optionz))
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || v.Field(i).IsZero() {
+			continue
+		}
+		if !slices.Contains(supported, field.Name) {
+			return status.Errorf(codes.Unimplemented, "option %s is not supported by containerd", field.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeNerdctl answers nerdctl commands with the output, or error, set for their subcommand and
+// records the commands it runs.
+type fakeNerdctl struct {
+	outputs map[string]string
+	errs    map[string]error
+
+	Cmds [][]string
+}
+
+func (f *fakeNerdctl) Run(_ context.Context, args ...string) ([]byte, error) {
+	f.Cmds = append(f.Cmds, args)
+	if err := f.errs[args[0]]; err != nil {
+		return nil, err
+	}
+	return []byte(f.outputs[args[0]]), nil
+}
+
+// commands returns the commands run with the subcommand cmd, joined by spaces.
+func (f *fakeNerdctl) commands(cmd string) []string {
+	var cmds []string
+	for _, args := range f.Cmds {
+		if args[0] == cmd {
+			cmds = append(cmds, strings.Join(args, " "))
+		}
+	}
+	return cmds
+}
+
+func TestStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		inErr   error
+		wantErr error
+	}{
+		{
+			name: "reachable",
+		},
+		{
+			name:    "unreachable",
+			inErr:   fmt.Errorf("cannot access containerd socket"),
+			wantErr: status.Error(codes.Unavailable, "unable to reach containerd: cannot access containerd socket"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := New(&fakeNerdctl{errs: map[string]error{"version": tc.inErr}})
+			if diff := cmp.Diff(tc.wantErr, mgr.Start(context.Background()), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("Start() returned unexpected error(-want, got):\n %s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		inOpts  []options.Option
+		wantErr error
+	}{
+		{
+			name: "no-options",
+		},
+		{
+			name:   "supported",
+			inOpts: []options.Option{options.WithInstanceName("web"), options.WithEnv(map[string]string{"A": "1"})},
+		},
+		{
+			name:    "unsupported",
+			inOpts:  []options.Option{options.WithInstanceName("web"), options.WithPrivileged(true)},
+			wantErr: status.Error(codes.Unimplemented, "option Privileged is not supported by containerd"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkOptions(options.ApplyOptions(tc.inOpts...), "InstanceName", "EnvMapping")
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("checkOptions() returned unexpected error(-want, got):\n %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// apiFeature is a container setting only understood by daemons speaking at least minVersion of
+// the docker API. Older daemons either reject it with an obscure error or silently ignore it.
+type apiFeature struct {
+	option     string
+	minVersion string
+	used       func(*container.Config, *container.HostConfig) bool
+}
+
+var apiFeatures = []apiFeature{
+	{
+		option:     "pids limit",
+		minVersion: "1.23",
+		used: func(_ *container.Config, hostConfig *container.HostConfig) bool {
+			return hostConfig.PidsLimit != nil
+		},
+	},
+	{
+		option:     "sysctls",
+		minVersion: "1.24",
+		used: func(_ *container.Config, hostConfig *container.HostConfig) bool {
+			return len(hostConfig.Sysctls) > 0
+		},
+	},
+	{
+		option:     "init",
+		minVersion: "1.25",
+		used: func(_ *container.Config, hostConfig *container.HostConfig) bool {
+			return hostConfig.Init != nil
+		},
+	},
+	{
+		option:     "stop timeout",
+		minVersion: "1.25",
+		used: func(config *container.Config, _ *container.HostConfig) bool {
+			return config.StopTimeout != nil
+		},
+	},
+	{
+		option:     "cpu limit",
+		minVersion: "1.25",
+		used: func(_ *container.Config, hostConfig *container.HostConfig) bool {
+			return hostConfig.NanoCPUs != 0
+		},
+	},
+	{
+		option:     "GPUs",
+		minVersion: "1.40",
+		used: func(_ *container.Config, hostConfig *container.HostConfig) bool {
+			return len(hostConfig.DeviceRequests) > 0
+		},
+	},
+}
+
+// checkAPIVersion ensures that the daemon's negotiated API version supports every setting used
+// by the container config. A client that has not negotiated a version yet reports none, in which
+// case the daemon is left to reject what it does not support.
+func (m *Manager) checkAPIVersion(config *container.Config, hostConfig *container.HostConfig) error {
+	version := m.client.ClientVersion()
+	if version == "" {
+		return nil
+	}
+	for _, f := range apiFeatures {
+		if f.used(config, hostConfig) && versions.LessThan(version, f.minVersion) {
+			return status.Errorf(codes.Unimplemented, "the %s option requires docker API version %s, the daemon supports %s", f.option, f.minVersion, version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeVersionedDocker starts containers against a daemon speaking the provided API version.
+type fakeVersionedDocker struct {
+	fakeStartingDocker
+	version string
+}
+
+func (f *fakeVersionedDocker) ClientVersion() string {
+	return f.version
+}
+
+func TestContainerStartAPIVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		inVersion string
+		inOpts    []options.Option
+		wantErr   error
+	}{
+		{
+			name:      "supported-options",
+			inVersion: "1.24",
+			inOpts:    []options.Option{options.WithPidsLimit(100), options.WithSysctls(map[string]string{"net.core.somaxconn": "1024"})},
+		},
+		{
+			name:      "init-too-old",
+			inVersion: "1.24",
+			inOpts:    []options.Option{options.WithPidsLimit(100), options.WithInit(true)},
+			wantErr:   status.Error(codes.Unimplemented, "the init option requires docker API version 1.25, the daemon supports 1.24"),
+		},
+		{
+			name:      "pids-limit-too-old",
+			inVersion: "1.22",
+			inOpts:    []options.Option{options.WithPidsLimit(100)},
+			wantErr:   status.Error(codes.Unimplemented, "the pids limit option requires docker API version 1.23, the daemon supports 1.22"),
+		},
+		{
+			name:      "gpus-too-old",
+			inVersion: "1.39",
+			inOpts:    []options.Option{options.WithGPUs("all", nil)},
+			wantErr:   status.Error(codes.Unimplemented, "the GPUs option requires docker API version 1.40, the daemon supports 1.39"),
+		},
+		{
+			name:      "gpus-supported",
+			inVersion: "1.41",
+			inOpts:    []options.Option{options.WithGPUs("all", nil)},
+		},
+		{
+			name:   "unknown-version",
+			inOpts: []options.Option{options.WithGPUs("all", nil), options.WithInit(true)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeVersionedDocker{
+				fakeStartingDocker: fakeStartingDocker{summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}}},
+				version:            tc.inVersion,
+			}
+			mgr := New(fsd)
+
+			opts := append([]options.Option{options.WithInstanceName("my-instance")}, tc.inOpts...)
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if created := fsd.ContainerID != ""; created != (tc.wantErr == nil) {
+				t.Errorf("ContainerStart() created a container: %t, want %t", created, tc.wantErr == nil)
+			}
+		})
+	}
+}
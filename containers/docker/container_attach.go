@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// ContainerAttach attaches to the streams of a running container until its output ends or ctx is
+// done. Input read from stdin, if not nil, is written to the container, which must have been
+// started with stdin open. The output of the container is written to stdout and stderr, or to
+// stdout only if stderr is nil. Containers with a TTY have a single raw output stream, which is
+// written to stdout.
+//
+// The end of stdin and the end of ctx only detach from the container, which keeps running.
+func (m *Manager) ContainerAttach(ctx context.Context, instance string, stdin io.Reader, stdout, stderr io.Writer, opts ...options.Option) error {
+	if stdout == nil {
+		return status.Error(codes.InvalidArgument, "an output stream must be supplied")
+	}
+	if stderr == nil {
+		stderr = stdout
+	}
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+	cntJSON, err := m.jsonState(ctx, instance, cnts)
+	if err != nil {
+		return err
+	}
+	if cntJSON.ContainerJSONBase == nil || cntJSON.State == nil || cntJSON.Config == nil {
+		return status.Errorf(codes.Unknown, "unable to determine the state of container %s", instance)
+	}
+	if !cntJSON.State.Running || cntJSON.State.Paused {
+		return status.Errorf(codes.FailedPrecondition, "container %s is not running", instance)
+	}
+	if stdin != nil && !cntJSON.Config.OpenStdin {
+		return status.Errorf(codes.FailedPrecondition, "container %s was not started with stdin open", instance)
+	}
+
+	resp, err := m.client.ContainerAttach(ctx, m.containerName(instance), container.AttachOptions{
+		Stream: true,
+		Stdin:  stdin != nil,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to attach to container %s: %v", instance, err))
+	}
+	// Closing the connection detaches from the container without stopping it.
+	defer resp.Close()
+	defer closeOnCancel(ctx, resp.Conn)()
+
+	if stdin != nil {
+		go func() {
+			if _, err := io.Copy(resp.Conn, stdin); err != nil {
+				klog.Warningf("unable to forward input to container %s: %v", instance, err)
+			}
+			// Only signals the end of this input: containers keep their stdin open across
+			// attachments unless started with stdin once.
+			if err := resp.CloseWrite(); err != nil {
+				klog.Warningf("unable to close input of container %s: %v", instance, err)
+			}
+		}()
+	}
+
+	multiplexed := !cntJSON.Config.Tty
+	if mediaType, ok := resp.MediaType(); ok {
+		multiplexed = mediaType == types.MediaTypeMultiplexedStream
+	}
+	if multiplexed {
+		_, err = stdcopy.StdCopy(stdout, stderr, resp.Reader)
+	} else {
+		_, err = io.Copy(stdout, resp.Reader)
+	}
+	if err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to read output of container %s: %v", instance, err))
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAttachingDocker struct {
+	fakeDocker
+	cnts   []types.Container
+	state  *types.ContainerState
+	config *container.Config
+	// script plays the container side of the attached connection.
+	script func(conn net.Conn, f *fakeAttachingDocker)
+	done   chan struct{}
+
+	Options container.AttachOptions
+	// Stdin is the input received by the container.
+	Stdin string
+	// Detached records that the connection was closed while the container was still attached.
+	Detached bool
+}
+
+func (f *fakeAttachingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeAttachingDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: f.state,
+		},
+		Config: f.config,
+	}, nil
+}
+
+func (f *fakeAttachingDocker) ContainerAttach(ctx context.Context, id string, options container.AttachOptions) (types.HijackedResponse, error) {
+	f.Options = options
+	client, server := net.Pipe()
+	go func() {
+		defer close(f.done)
+		defer server.Close()
+		f.script(server, f)
+	}()
+	return types.HijackedResponse{Conn: client, Reader: bufio.NewReader(client)}, nil
+}
+
+// cancelWriter cancels an attachment once the output is received, as a disconnecting client
+// would.
+type cancelWriter struct {
+	buf    bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancelWriter) Write(p []byte) (int, error) {
+	defer w.cancel()
+	return w.buf.Write(p)
+}
+
+func TestContainerAttach(t *testing.T) {
+	cnts := []types.Container{{ID: "my-instance", Names: []string{"/my-instance"}}}
+	running := &types.ContainerState{Running: true}
+
+	// echo reads the expected input and writes it back on stdout, followed by a line on stderr,
+	// multiplexed unless the container has a TTY.
+	echo := func(in string, tty bool) func(net.Conn, *fakeAttachingDocker) {
+		return func(conn net.Conn, f *fakeAttachingDocker) {
+			buf := make([]byte, len(in))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+			f.Stdin = string(buf)
+			if tty {
+				conn.Write([]byte("echo: " + f.Stdin))
+				return
+			}
+			stdcopy.NewStdWriter(conn, stdcopy.Stdout).Write([]byte("echo: " + f.Stdin))
+			stdcopy.NewStdWriter(conn, stdcopy.Stderr).Write([]byte("done\n"))
+		}
+	}
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inState    *types.ContainerState
+		inConfig   *container.Config
+		inStdin    string
+		inScript   func(net.Conn, *fakeAttachingDocker)
+		inCancel   bool
+		wantStdout string
+		wantStderr string
+		wantState  *fakeAttachingDocker
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Error(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "not-running",
+			inInstance: "my-instance",
+			inState:    &types.ContainerState{},
+			inConfig:   &container.Config{},
+			wantErr:    status.Error(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:       "stdin-not-open",
+			inInstance: "my-instance",
+			inState:    running,
+			inConfig:   &container.Config{},
+			inStdin:    "hello\n",
+			wantErr:    status.Error(codes.FailedPrecondition, "container my-instance was not started with stdin open"),
+		},
+		{
+			name:       "multiplexed",
+			inInstance: "my-instance",
+			inState:    running,
+			inConfig:   &container.Config{OpenStdin: true},
+			inStdin:    "hello\n",
+			inScript:   echo("hello\n", false),
+			wantStdout: "echo: hello\n",
+			wantStderr: "done\n",
+			wantState: &fakeAttachingDocker{
+				Options: container.AttachOptions{Stream: true, Stdin: true, Stdout: true, Stderr: true},
+				Stdin:   "hello\n",
+			},
+		},
+		{
+			name:       "tty",
+			inInstance: "my-instance",
+			inState:    running,
+			inConfig:   &container.Config{OpenStdin: true, Tty: true},
+			inStdin:    "hello\n",
+			inScript:   echo("hello\n", true),
+			wantStdout: "echo: hello\n",
+			wantState: &fakeAttachingDocker{
+				Options: container.AttachOptions{Stream: true, Stdin: true, Stdout: true, Stderr: true},
+				Stdin:   "hello\n",
+			},
+		},
+		{
+			name:       "output-only",
+			inInstance: "my-instance",
+			inState:    running,
+			inConfig:   &container.Config{},
+			inScript:   echo("", false),
+			wantStdout: "echo: ",
+			wantStderr: "done\n",
+			wantState: &fakeAttachingDocker{
+				Options: container.AttachOptions{Stream: true, Stdout: true, Stderr: true},
+			},
+		},
+		{
+			name:       "client-disconnect",
+			inInstance: "my-instance",
+			inState:    running,
+			inConfig:   &container.Config{Tty: true},
+			inScript: func(conn net.Conn, f *fakeAttachingDocker) {
+				conn.Write([]byte("still running\n"))
+				// The container keeps running: its side is only closed by the client detaching.
+				if _, err := conn.Read(make([]byte, 1)); err == io.EOF {
+					f.Detached = true
+				}
+			},
+			inCancel:   true,
+			wantStdout: "still running\n",
+			wantState: &fakeAttachingDocker{
+				Options:  container.AttachOptions{Stream: true, Stdout: true, Stderr: true},
+				Detached: true,
+			},
+			wantErr: status.Error(codes.Canceled, "context canceled"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fad := &fakeAttachingDocker{
+				cnts:   cnts,
+				state:  tc.inState,
+				config: tc.inConfig,
+				script: tc.inScript,
+				done:   make(chan struct{}),
+			}
+			mgr := New(fad)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stdout := &cancelWriter{cancel: func() {}}
+			if tc.inCancel {
+				stdout.cancel = cancel
+			}
+			stderr := &bytes.Buffer{}
+			var stdin io.Reader
+			if tc.inStdin != "" {
+				stdin = strings.NewReader(tc.inStdin)
+			}
+
+			err := mgr.ContainerAttach(ctx, tc.inInstance, stdin, stdout, stderr)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerAttach(%q) returned unexpected error(-want, got):\n %s", tc.inInstance, diff)
+			}
+			if tc.inScript != nil {
+				<-fad.done
+			}
+
+			if got := stdout.buf.String(); got != tc.wantStdout {
+				t.Errorf("ContainerAttach(%q) wrote %q to stdout, want %q", tc.inInstance, got, tc.wantStdout)
+			}
+			if got := stderr.String(); got != tc.wantStderr {
+				t.Errorf("ContainerAttach(%q) wrote %q to stderr, want %q", tc.inInstance, got, tc.wantStderr)
+			}
+			if tc.wantState == nil {
+				tc.wantState = &fakeAttachingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, fad, cmpopts.IgnoreUnexported(fakeAttachingDocker{})); diff != "" {
+				t.Errorf("ContainerAttach(%q) returned diff(-want, +got):\n%s", tc.inInstance, diff)
+			}
+		})
+	}
+}
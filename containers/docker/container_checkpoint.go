@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/api/types/container"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContainerCheckpoint saves the state of the processes of a running container as checkpointID,
+// which must not already exist. The container keeps running. Checkpointing relies on CRIU and on
+// the runtime having experimental features enabled.
+func (m *Manager) ContainerCheckpoint(ctx context.Context, instance, checkpointID string, opts ...options.Option) error {
+	if checkpointID == "" {
+		return status.Error(codes.InvalidArgument, "a checkpoint ID must be supplied")
+	}
+
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if !state.Running || state.Paused {
+		return status.Errorf(codes.FailedPrecondition, "container %s is not running", instance)
+	}
+
+	ids, err := m.checkpoints(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(ids, checkpointID) {
+		return status.Errorf(codes.AlreadyExists, "checkpoint %s of container %s already exists", checkpointID, instance)
+	}
+
+	if err := m.client.CheckpointCreate(ctx, m.containerName(instance), checkpoint.CreateOptions{CheckpointID: checkpointID}); err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to checkpoint container %s: %v", instance, err))
+	}
+	return nil
+}
+
+// ContainerRestore starts a stopped container from its checkpoint checkpointID, restoring the
+// state of its processes at the time of the checkpoint.
+func (m *Manager) ContainerRestore(ctx context.Context, instance, checkpointID string, opts ...options.Option) (err error) {
+	if checkpointID == "" {
+		return status.Error(codes.InvalidArgument, "a checkpoint ID must be supplied")
+	}
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationContainerStart, start, instance, "", err) }()
+
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if state.Running {
+		return status.Errorf(codes.FailedPrecondition, "container %s is running", instance)
+	}
+
+	ids, err := m.checkpoints(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(ids, checkpointID) {
+		return status.Errorf(codes.NotFound, "checkpoint %s of container %s not found", checkpointID, instance)
+	}
+
+	if err := m.client.ContainerStart(ctx, m.containerName(instance), container.StartOptions{CheckpointID: checkpointID}); err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to restore container %s from checkpoint %s: %v", instance, checkpointID, err))
+	}
+	return nil
+}
+
+// checkpoints returns the IDs of the checkpoints of the provided instance.
+func (m *Manager) checkpoints(ctx context.Context, instance string) ([]string, error) {
+	summaries, err := m.client.CheckpointList(ctx, m.containerName(instance), checkpoint.ListOptions{})
+	if err != nil {
+		return nil, contextError(ctx, status.Errorf(codes.Internal, "unable to list checkpoints of container %s: %v", instance, err))
+	}
+	ids := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		ids = append(ids, summary.Name)
+	}
+	return ids, nil
+}
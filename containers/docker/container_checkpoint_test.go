@@ -0,0 +1,230 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeCheckpointingDocker struct {
+	fakeDocker
+	cnts        []types.Container
+	state       *types.ContainerState
+	checkpoints []checkpoint.Summary
+
+	Listed     string
+	Created    string
+	Checkpoint string
+	Started    string
+	StartedAt  string
+}
+
+func (f *fakeCheckpointingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeCheckpointingDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: f.state,
+		},
+	}, nil
+}
+
+func (f *fakeCheckpointingDocker) CheckpointList(ctx context.Context, container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error) {
+	f.Listed = container
+	return f.checkpoints, nil
+}
+
+func (f *fakeCheckpointingDocker) CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error {
+	f.Created = container
+	f.Checkpoint = options.CheckpointID
+	return nil
+}
+
+func (f *fakeCheckpointingDocker) ContainerStart(ctx context.Context, container string, options container.StartOptions) error {
+	f.Started = container
+	f.StartedAt = options.CheckpointID
+	return nil
+}
+
+var checkpointCnts = []types.Container{
+	{
+		ID:    "my-instance",
+		Names: []string{"/my-instance"},
+	},
+}
+
+func TestContainerCheckpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		inInstance    string
+		inCheckpoint  string
+		inCnts        []types.Container
+		inState       *types.ContainerState
+		inCheckpoints []checkpoint.Summary
+		wantState     *fakeCheckpointingDocker
+		wantErr       error
+	}{
+		{
+			name:       "no-checkpoint-id",
+			inInstance: "my-instance",
+			inCnts:     checkpointCnts,
+			inState:    &types.ContainerState{Running: true},
+			wantErr:    status.Error(codes.InvalidArgument, "a checkpoint ID must be supplied"),
+		},
+		{
+			name:         "no-such-instance",
+			inInstance:   "no-such-instance",
+			inCheckpoint: "cp1",
+			wantErr:      status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:         "not-running",
+			inInstance:   "my-instance",
+			inCheckpoint: "cp1",
+			inCnts:       checkpointCnts,
+			inState:      &types.ContainerState{},
+			wantErr:      status.Errorf(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:         "paused",
+			inInstance:   "my-instance",
+			inCheckpoint: "cp1",
+			inCnts:       checkpointCnts,
+			inState:      &types.ContainerState{Running: true, Paused: true},
+			wantErr:      status.Errorf(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:          "already-exists",
+			inInstance:    "my-instance",
+			inCheckpoint:  "cp1",
+			inCnts:        checkpointCnts,
+			inState:       &types.ContainerState{Running: true},
+			inCheckpoints: []checkpoint.Summary{{Name: "cp1"}},
+			wantState: &fakeCheckpointingDocker{
+				Listed: "my-instance",
+			},
+			wantErr: status.Errorf(codes.AlreadyExists, "checkpoint cp1 of container my-instance already exists"),
+		},
+		{
+			name:          "checkpoint",
+			inInstance:    "my-instance",
+			inCheckpoint:  "cp2",
+			inCnts:        checkpointCnts,
+			inState:       &types.ContainerState{Running: true},
+			inCheckpoints: []checkpoint.Summary{{Name: "cp1"}},
+			wantState: &fakeCheckpointingDocker{
+				Listed:     "my-instance",
+				Created:    "my-instance",
+				Checkpoint: "cp2",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fcd := &fakeCheckpointingDocker{
+				cnts:        tc.inCnts,
+				state:       tc.inState,
+				checkpoints: tc.inCheckpoints,
+			}
+			mgr := New(fcd)
+
+			err := mgr.ContainerCheckpoint(context.Background(), tc.inInstance, tc.inCheckpoint)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerCheckpoint(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inCheckpoint, diff)
+			}
+
+			if tc.wantState == nil {
+				tc.wantState = &fakeCheckpointingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, fcd, cmpopts.IgnoreUnexported(fakeCheckpointingDocker{})); diff != "" {
+				t.Errorf("ContainerCheckpoint(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inCheckpoint, diff)
+			}
+		})
+	}
+}
+
+func TestContainerRestore(t *testing.T) {
+	tests := []struct {
+		name          string
+		inInstance    string
+		inCheckpoint  string
+		inCnts        []types.Container
+		inState       *types.ContainerState
+		inCheckpoints []checkpoint.Summary
+		wantState     *fakeCheckpointingDocker
+		wantErr       error
+	}{
+		{
+			name:       "no-checkpoint-id",
+			inInstance: "my-instance",
+			wantErr:    status.Error(codes.InvalidArgument, "a checkpoint ID must be supplied"),
+		},
+		{
+			name:         "running",
+			inInstance:   "my-instance",
+			inCheckpoint: "cp1",
+			inCnts:       checkpointCnts,
+			inState:      &types.ContainerState{Running: true},
+			wantErr:      status.Errorf(codes.FailedPrecondition, "container my-instance is running"),
+		},
+		{
+			name:          "missing-checkpoint",
+			inInstance:    "my-instance",
+			inCheckpoint:  "cp2",
+			inCnts:        checkpointCnts,
+			inState:       &types.ContainerState{},
+			inCheckpoints: []checkpoint.Summary{{Name: "cp1"}},
+			wantState: &fakeCheckpointingDocker{
+				Listed: "my-instance",
+			},
+			wantErr: status.Errorf(codes.NotFound, "checkpoint cp2 of container my-instance not found"),
+		},
+		{
+			name:          "restore",
+			inInstance:    "my-instance",
+			inCheckpoint:  "cp1",
+			inCnts:        checkpointCnts,
+			inState:       &types.ContainerState{},
+			inCheckpoints: []checkpoint.Summary{{Name: "cp1"}},
+			wantState: &fakeCheckpointingDocker{
+				Listed:    "my-instance",
+				Started:   "my-instance",
+				StartedAt: "cp1",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fcd := &fakeCheckpointingDocker{
+				cnts:        tc.inCnts,
+				state:       tc.inState,
+				checkpoints: tc.inCheckpoints,
+			}
+			mgr := New(fcd)
+
+			err := mgr.ContainerRestore(context.Background(), tc.inInstance, tc.inCheckpoint)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerRestore(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inCheckpoint, diff)
+			}
+
+			if tc.wantState == nil {
+				tc.wantState = &fakeCheckpointingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, fcd, cmpopts.IgnoreUnexported(fakeCheckpointingDocker{})); diff != "" {
+				t.Errorf("ContainerRestore(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inCheckpoint, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CopyToContainer extracts the tar archive content into the directory destPath of the
+// container, which must exist. The archive is passed to the runtime as is, so the modes of its
+// entries are preserved.
+func (m *Manager) CopyToContainer(ctx context.Context, instance, destPath string, content io.Reader, opts ...options.Option) error {
+	if !path.IsAbs(destPath) {
+		return status.Errorf(codes.InvalidArgument, "destination path %q must be an absolute path", destPath)
+	}
+	if err := m.checkCopyInstance(ctx, instance); err != nil {
+		return err
+	}
+
+	if err := m.client.CopyToContainer(ctx, m.containerName(instance), destPath, content, container.CopyToContainerOptions{}); err != nil {
+		return contextError(ctx, copyError(instance, destPath, err))
+	}
+	return nil
+}
+
+// CopyFromContainer returns a tar archive of the file or directory srcPath of the container. The
+// caller must close the archive.
+func (m *Manager) CopyFromContainer(ctx context.Context, instance, srcPath string, opts ...options.Option) (io.ReadCloser, error) {
+	if !path.IsAbs(srcPath) {
+		return nil, status.Errorf(codes.InvalidArgument, "source path %q must be an absolute path", srcPath)
+	}
+	if err := m.checkCopyInstance(ctx, instance); err != nil {
+		return nil, err
+	}
+
+	rc, _, err := m.client.CopyFromContainer(ctx, m.containerName(instance), srcPath)
+	if err != nil {
+		return nil, contextError(ctx, copyError(instance, srcPath, err))
+	}
+	return rc, nil
+}
+
+// checkCopyInstance ensures that instance exists. Files can be copied to and from containers
+// whether they are running or not.
+func (m *Manager) checkCopyInstance(ctx context.Context, instance string) error {
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+	return checkInstanceExists(instance, cnts)
+}
+
+// copyError converts the error of a failed copy of p to a status error.
+func copyError(instance, p string, err error) error {
+	switch {
+	case errdefs.IsNotFound(err):
+		return status.Errorf(codes.NotFound, "path %s not found in container %s: %v", p, instance, err)
+	case errdefs.IsInvalidParameter(err):
+		return status.Errorf(codes.InvalidArgument, "unable to copy %s of container %s: %v", p, instance, err)
+	case errdefs.IsForbidden(err), errdefs.IsConflict(err):
+		return status.Errorf(codes.FailedPrecondition, "unable to copy %s of container %s: %v", p, instance, err)
+	default:
+		return status.Errorf(codes.Internal, "unable to copy %s of container %s: %v", p, instance, err)
+	}
+}
@@ -0,0 +1,246 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// copyEntry is an entry of a copied tar archive.
+type copyEntry struct {
+	Name string
+	Mode int64
+	Data string
+}
+
+// copyArchive returns a tar archive of entries.
+func copyArchive(t *testing.T, entries ...copyEntry) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, e := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: e.Name, Mode: e.Mode, Size: int64(len(e.Data))}); err != nil {
+			t.Fatalf("unable to write tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte(e.Data)); err != nil {
+			t.Fatalf("unable to write tar entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("unable to close tar archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// copyEntries returns the entries of the tar archive r.
+func copyEntries(r io.Reader) ([]copyEntry, error) {
+	var entries []copyEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, copyEntry{Name: hdr.Name, Mode: hdr.Mode, Data: string(data)})
+	}
+}
+
+type fakeCopyingDocker struct {
+	fakeDocker
+	cnts    []types.Container
+	archive []byte
+	copyErr error
+
+	Instance string
+	Path     string
+	Options  container.CopyToContainerOptions
+	Entries  []copyEntry
+}
+
+func (f *fakeCopyingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeCopyingDocker) CopyToContainer(ctx context.Context, id, path string, content io.Reader, options container.CopyToContainerOptions) error {
+	f.Instance = id
+	f.Path = path
+	f.Options = options
+	entries, err := copyEntries(content)
+	if err != nil {
+		return err
+	}
+	f.Entries = entries
+	return f.copyErr
+}
+
+func (f *fakeCopyingDocker) CopyFromContainer(ctx context.Context, id, srcPath string) (io.ReadCloser, container.PathStat, error) {
+	f.Instance = id
+	f.Path = srcPath
+	if f.copyErr != nil {
+		return nil, container.PathStat{}, f.copyErr
+	}
+	return io.NopCloser(bytes.NewReader(f.archive)), container.PathStat{}, nil
+}
+
+var copyCnts = []types.Container{{ID: "some-id", Names: []string{"/my-instance"}}}
+
+func TestCopyToContainer(t *testing.T) {
+	entries := []copyEntry{
+		{Name: "app.conf", Mode: 0o640, Data: "listen 8080\n"},
+		{Name: "run.sh", Mode: 0o755, Data: "#!/bin/sh\n"},
+	}
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inPath     string
+		inCopyErr  error
+		wantState  *fakeCopyingDocker
+		wantErr    error
+	}{
+		{
+			name:       "copy",
+			inInstance: "my-instance",
+			inPath:     "/etc/app",
+			wantState: &fakeCopyingDocker{
+				Instance: "my-instance",
+				Path:     "/etc/app",
+				Entries:  entries,
+			},
+		},
+		{
+			name:       "relative-path",
+			inInstance: "my-instance",
+			inPath:     "etc/app",
+			wantErr:    status.Error(codes.InvalidArgument, `destination path "etc/app" must be an absolute path`),
+		},
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inPath:     "/etc/app",
+			wantErr:    status.Error(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "missing-parent",
+			inInstance: "my-instance",
+			inPath:     "/no/such/dir",
+			inCopyErr:  errdefs.NotFound(errors.New("Could not find the file /no/such/dir in container my-instance")),
+			wantState: &fakeCopyingDocker{
+				Instance: "my-instance",
+				Path:     "/no/such/dir",
+				Entries:  entries,
+			},
+			wantErr: status.Error(codes.NotFound, "path /no/such/dir not found in container my-instance: Could not find the file /no/such/dir in container my-instance"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fcd := &fakeCopyingDocker{
+				cnts:    copyCnts,
+				copyErr: tc.inCopyErr,
+			}
+			mgr := New(fcd)
+
+			err := mgr.CopyToContainer(context.Background(), tc.inInstance, tc.inPath, bytes.NewReader(copyArchive(t, entries...)))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("CopyToContainer(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inPath, diff)
+			}
+
+			if tc.wantState == nil {
+				tc.wantState = &fakeCopyingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, fcd, cmpopts.IgnoreUnexported(fakeCopyingDocker{})); diff != "" {
+				t.Errorf("CopyToContainer(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inPath, diff)
+			}
+		})
+	}
+}
+
+func TestCopyFromContainer(t *testing.T) {
+	core := []copyEntry{{Name: "core.1234", Mode: 0o600, Data: "\x7fELF"}}
+
+	tests := []struct {
+		name        string
+		inInstance  string
+		inPath      string
+		inCopyErr   error
+		wantEntries []copyEntry
+		wantErr     error
+	}{
+		{
+			name:        "copy",
+			inInstance:  "my-instance",
+			inPath:      "/var/crash/core.1234",
+			wantEntries: core,
+		},
+		{
+			name:       "relative-path",
+			inInstance: "my-instance",
+			inPath:     "core.1234",
+			wantErr:    status.Error(codes.InvalidArgument, `source path "core.1234" must be an absolute path`),
+		},
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inPath:     "/var/crash/core.1234",
+			wantErr:    status.Error(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "no-such-path",
+			inInstance: "my-instance",
+			inPath:     "/var/crash/core.1",
+			inCopyErr:  errdefs.NotFound(errors.New("Could not find the file /var/crash/core.1 in container my-instance")),
+			wantErr:    status.Error(codes.NotFound, "path /var/crash/core.1 not found in container my-instance: Could not find the file /var/crash/core.1 in container my-instance"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fcd := &fakeCopyingDocker{
+				cnts:    copyCnts,
+				archive: copyArchive(t, core...),
+				copyErr: tc.inCopyErr,
+			}
+			mgr := New(fcd)
+
+			rc, err := mgr.CopyFromContainer(context.Background(), tc.inInstance, tc.inPath)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("CopyFromContainer(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inPath, diff)
+			}
+			if err != nil {
+				return
+			}
+			defer rc.Close()
+
+			if fcd.Path != tc.inPath {
+				t.Errorf("CopyFromContainer(%q, %q) copied path %q", tc.inInstance, tc.inPath, fcd.Path)
+			}
+			entries, err := copyEntries(rc)
+			if err != nil {
+				t.Fatalf("CopyFromContainer(%q, %q) returned an invalid archive: %v", tc.inInstance, tc.inPath, err)
+			}
+			if diff := cmp.Diff(tc.wantEntries, entries); diff != "" {
+				t.Errorf("CopyFromContainer(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inPath, diff)
+			}
+		})
+	}
+}
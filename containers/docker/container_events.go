@@ -0,0 +1,115 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// eventsReconnectInterval is how long to wait before resubscribing to the runtime's events after
+// the subscription is interrupted.
+var eventsReconnectInterval = time.Second
+
+// ContainerEvents streams the die, oom and health status events of the containers managed by
+// containerz to srv until ctx is done or srv fails. If the subscription to the runtime is
+// interrupted it is re-established, resuming after the last event forwarded.
+func (m *Manager) ContainerEvents(ctx context.Context, srv options.ContainerEventStreamer) error {
+	args := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("label", fmt.Sprintf("%s=%s", managedByLabel, managedByValue)),
+		filters.Arg("event", string(events.ActionDie)),
+		filters.Arg("event", string(events.ActionOOM)),
+		filters.Arg("event", string(events.ActionHealthStatus)),
+	)
+
+	var last int64
+	for {
+		opts := events.ListOptions{Filters: args}
+		if last != 0 {
+			opts.Since = fmt.Sprintf("%d.%09d", last/int64(time.Second), last%int64(time.Second))
+		}
+
+		// Cancelling the subscription once it is abandoned stops the runtime client sending to it.
+		subCtx, cancel := context.WithCancel(ctx)
+		msgs, errs := m.client.Events(subCtx, opts)
+		err := m.forwardEvents(subCtx, msgs, errs, srv, &last)
+		cancel()
+		if err != nil {
+			return contextError(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(eventsReconnectInterval):
+		}
+	}
+}
+
+// forwardEvents sends the events of a single subscription to srv, recording the time of the last
+// event sent in last. It returns nil once the subscription is interrupted, and an error if ctx is
+// done or srv fails.
+func (m *Manager) forwardEvents(ctx context.Context, msgs <-chan events.Message, errs <-chan error, srv options.ContainerEventStreamer, last *int64) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			klog.Warningf("container event subscription interrupted, reconnecting: %v", err)
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				klog.Warning("container event subscription closed, reconnecting")
+				return nil
+			}
+			// A resumed subscription replays the events at the time it resumes from, and the
+			// runtime is not relied upon to have filtered out unmanaged containers.
+			if msg.TimeNano <= *last || msg.Actor.Attributes[managedByLabel] != managedByValue {
+				continue
+			}
+			event, ok := containerEvent(msg)
+			if !ok {
+				continue
+			}
+			event.Instance = m.instanceOf(event.Instance)
+			if err := srv.Send(event); err != nil {
+				return err
+			}
+			*last = msg.TimeNano
+		}
+	}
+}
+
+// containerEvent normalizes a runtime event, reporting whether it is one that is forwarded.
+func containerEvent(msg events.Message) (*options.ContainerEvent, bool) {
+	if msg.Type != events.ContainerEventType {
+		return nil, false
+	}
+
+	event := &options.ContainerEvent{
+		Time:     time.Unix(0, msg.TimeNano),
+		ID:       msg.Actor.ID,
+		Instance: msg.Actor.Attributes["name"],
+		Action:   string(msg.Action),
+	}
+	switch {
+	case msg.Action == events.ActionDie:
+		// The exit code is informational; a malformed one is reported as 0.
+		event.ExitCode, _ = strconv.Atoi(msg.Actor.Attributes["exitCode"])
+	case msg.Action == events.ActionOOM:
+	case strings.HasPrefix(string(msg.Action), string(events.ActionHealthStatus)+":"):
+		event.Action = string(events.ActionHealthStatus)
+		event.Health = strings.TrimSpace(strings.TrimPrefix(string(msg.Action), string(events.ActionHealthStatus)+":"))
+	default:
+		return nil, false
+	}
+	return event, true
+}
@@ -0,0 +1,153 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeEventsDocker struct {
+	fakeDocker
+	// subscriptions are the events delivered by successive subscriptions, each of which is then
+	// interrupted. Once they are exhausted, subscriptions deliver nothing.
+	subscriptions [][]events.Message
+
+	mu    sync.Mutex
+	Since []string
+}
+
+func (f *fakeEventsDocker) Events(ctx context.Context, opts events.ListOptions) (<-chan events.Message, <-chan error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Since = append(f.Since, opts.Since)
+	msgs := make(chan events.Message)
+	errs := make(chan error, 1)
+	if len(f.subscriptions) == 0 {
+		return msgs, errs
+	}
+	sub := f.subscriptions[0]
+	f.subscriptions = f.subscriptions[1:]
+
+	go func() {
+		for _, msg := range sub {
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+		errs <- fmt.Errorf("unexpected EOF")
+	}()
+	return msgs, errs
+}
+
+type fakeEventStreamer struct {
+	want   int
+	cancel context.CancelFunc
+	err    error
+
+	Events []*options.ContainerEvent
+}
+
+func (f *fakeEventStreamer) Send(event *options.ContainerEvent) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.Events = append(f.Events, event)
+	if len(f.Events) == f.want {
+		f.cancel()
+	}
+	return nil
+}
+
+func managedEvent(nanos int64, name string, action events.Action, attrs map[string]string) events.Message {
+	attributes := map[string]string{"name": name, managedByLabel: managedByValue}
+	for k, v := range attrs {
+		attributes[k] = v
+	}
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   action,
+		Actor:    events.Actor{ID: name + "-id", Attributes: attributes},
+		TimeNano: nanos,
+	}
+}
+
+func TestContainerEvents(t *testing.T) {
+	defer func(interval time.Duration) { eventsReconnectInterval = interval }(eventsReconnectInterval)
+	eventsReconnectInterval = 0
+
+	unmanaged := events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionDie,
+		Actor:    events.Actor{ID: "other-id", Attributes: map[string]string{"name": "other"}},
+		TimeNano: 2,
+	}
+
+	fed := &fakeEventsDocker{
+		subscriptions: [][]events.Message{
+			{
+				managedEvent(1, "one", events.ActionDie, map[string]string{"exitCode": "137"}),
+				unmanaged,
+				managedEvent(3, "two", events.ActionOOM, nil),
+			},
+			{
+				// The resumed subscription replays the last event forwarded.
+				managedEvent(3, "two", events.ActionOOM, nil),
+				managedEvent(4, "one", "health_status: unhealthy", nil),
+				managedEvent(5, "one", events.ActionStart, nil),
+				managedEvent(int64(6*time.Second+7), "two", events.ActionDie, map[string]string{"exitCode": "0"}),
+			},
+			{},
+		},
+	}
+	mgr := New(fed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := &fakeEventStreamer{want: 4, cancel: cancel}
+
+	err := mgr.ContainerEvents(ctx, srv)
+	if diff := cmp.Diff(status.Error(codes.Canceled, "context canceled"), err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("ContainerEvents() returned unexpected error(-want, got):\n %s", diff)
+	}
+
+	want := []*options.ContainerEvent{
+		{Time: time.Unix(0, 1), ID: "one-id", Instance: "one", Action: "die", ExitCode: 137},
+		{Time: time.Unix(0, 3), ID: "two-id", Instance: "two", Action: "oom"},
+		{Time: time.Unix(0, 4), ID: "one-id", Instance: "one", Action: "health_status", Health: "unhealthy"},
+		{Time: time.Unix(0, int64(6*time.Second+7)), ID: "two-id", Instance: "two", Action: "die"},
+	}
+	if diff := cmp.Diff(want, srv.Events); diff != "" {
+		t.Errorf("ContainerEvents() forwarded diff(-want, +got):\n%s", diff)
+	}
+
+	fed.mu.Lock()
+	defer fed.mu.Unlock()
+	if diff := cmp.Diff([]string{"", "0.000000003"}, fed.Since[:2]); diff != "" {
+		t.Errorf("ContainerEvents() subscribed since diff(-want, +got):\n%s", diff)
+	}
+}
+
+func TestContainerEventsSendError(t *testing.T) {
+	fed := &fakeEventsDocker{
+		subscriptions: [][]events.Message{{managedEvent(1, "one", events.ActionOOM, nil)}},
+	}
+	mgr := New(fed)
+
+	wantErr := status.Error(codes.Unavailable, "client went away")
+	err := mgr.ContainerEvents(context.Background(), &fakeEventStreamer{err: wantErr})
+	if diff := cmp.Diff(wantErr, err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("ContainerEvents() returned unexpected error(-want, got):\n %s", diff)
+	}
+}
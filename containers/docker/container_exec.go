@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/shlex"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// execPollInterval is how often the exec state is polled while waiting for its exit code.
+var execPollInterval = 50 * time.Millisecond
+
+// ContainerExec runs cmd inside a running container and returns its combined stdout and stderr
+// along with its exit code. A command that exits with a non-zero code is not an error; errors
+// are only returned if the command could not be run.
+func (m *Manager) ContainerExec(ctx context.Context, instance, cmd string, opts ...options.Option) (string, int, error) {
+	optionz := options.ApplyOptions(opts...)
+
+	splitCmd, err := shlex.Split(cmd)
+	if err != nil {
+		return "", 0, status.Errorf(codes.InvalidArgument, "failed to split command %q, got error %s", cmd, err)
+	}
+	if len(splitCmd) == 0 {
+		return "", 0, status.Error(codes.InvalidArgument, "a command must be supplied")
+	}
+
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return "", 0, err
+	}
+	if !state.Running || state.Paused {
+		return "", 0, status.Errorf(codes.FailedPrecondition, "container %s is not running", instance)
+	}
+
+	env := make([]string, 0, len(optionz.EnvMapping))
+	for name, val := range optionz.EnvMapping {
+		env = append(env, fmt.Sprintf("%s=%s", name, val))
+	}
+	sort.Strings(env)
+
+	exec, err := m.client.ContainerExecCreate(ctx, m.containerName(instance), container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Env:          env,
+		WorkingDir:   optionz.WorkingDir,
+		Cmd:          splitCmd,
+	})
+	if err != nil {
+		return "", 0, status.Errorf(codes.Internal, "unable to create exec in container %s: %v", instance, err)
+	}
+
+	resp, err := m.client.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return "", 0, status.Errorf(codes.Internal, "unable to attach to exec in container %s: %v", instance, err)
+	}
+	defer resp.Close()
+	defer closeOnCancel(ctx, resp.Conn)()
+
+	out := &bytes.Buffer{}
+	if _, err := stdcopy.StdCopy(out, out, resp.Reader); err != nil {
+		return "", 0, contextError(ctx, status.Errorf(codes.Internal, "unable to read exec output from container %s: %v", instance, err))
+	}
+
+	// The output stream closes when the command exits, but the runtime may take a moment to
+	// record the exit code.
+	for {
+		inspect, err := m.client.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return "", 0, status.Errorf(codes.Internal, "unable to inspect exec in container %s: %v", instance, err)
+		}
+		if !inspect.Running {
+			return out.String(), inspect.ExitCode, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", 0, status.FromContextError(ctx.Err()).Err()
+		case <-time.After(execPollInterval):
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeExecingDocker struct {
+	fakeDocker
+	cnts     []types.Container
+	state    *types.ContainerState
+	stdout   string
+	stderr   string
+	exitCode int
+	// hang keeps the output stream open, as for a command that never exits.
+	hang bool
+	// running is the number of inspections that report the exec as still running.
+	running int
+
+	Instance string
+	Opts     container.ExecOptions
+}
+
+func (f *fakeExecingDocker) ContainerList(context.Context, container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeExecingDocker) ContainerInspect(_ context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: f.state,
+		},
+	}, nil
+}
+
+func (f *fakeExecingDocker) ContainerExecCreate(_ context.Context, instance string, opts container.ExecOptions) (container.ExecCreateResponse, error) {
+	f.Instance = instance
+	f.Opts = opts
+	return container.ExecCreateResponse{ID: "some-exec"}, nil
+}
+
+func (f *fakeExecingDocker) ContainerExecAttach(context.Context, string, container.ExecAttachOptions) (types.HijackedResponse, error) {
+	server, client := net.Pipe()
+	go func() {
+		if !f.hang {
+			defer server.Close()
+		}
+		if f.stdout != "" {
+			stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte(f.stdout))
+		}
+		if f.stderr != "" {
+			stdcopy.NewStdWriter(server, stdcopy.Stderr).Write([]byte(f.stderr))
+		}
+	}()
+	return types.HijackedResponse{Conn: client, Reader: bufio.NewReader(client)}, nil
+}
+
+func (f *fakeExecingDocker) ContainerExecInspect(_ context.Context, id string) (container.ExecInspect, error) {
+	if f.running > 0 {
+		f.running--
+		return container.ExecInspect{ExecID: id, Running: true}, nil
+	}
+	return container.ExecInspect{ExecID: id, ExitCode: f.exitCode}, nil
+}
+
+func TestContainerExec(t *testing.T) {
+	execPollInterval = 0
+
+	cnts := []types.Container{
+		{
+			ID:    "my-instance",
+			Names: []string{"/my-instance"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		inInstance   string
+		inCmd        string
+		inOpts       []options.Option
+		inCnts       []types.Container
+		inState      *types.ContainerState
+		inStdout     string
+		inStderr     string
+		inExitCode   int
+		inHang       bool
+		inRunning    int
+		wantState    *fakeExecingDocker
+		wantOutput   string
+		wantExitCode int
+		wantErr      error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inCmd:      "true",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "not-running",
+			inInstance: "my-instance",
+			inCmd:      "true",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{},
+			wantErr:    status.Errorf(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:       "paused",
+			inInstance: "my-instance",
+			inCmd:      "true",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true, Paused: true},
+			wantErr:    status.Errorf(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:       "no-cmd",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true},
+			wantErr:    status.Error(codes.InvalidArgument, "a command must be supplied"),
+		},
+		{
+			name:       "exec",
+			inInstance: "my-instance",
+			inCmd:      `sh -c "echo hello"`,
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true},
+			inStdout:   "hello\n",
+			wantState: &fakeExecingDocker{
+				Instance: "my-instance",
+				Opts: container.ExecOptions{
+					AttachStdout: true,
+					AttachStderr: true,
+					Cmd:          []string{"sh", "-c", "echo hello"},
+				},
+			},
+			wantOutput: "hello\n",
+		},
+		{
+			name:       "exec-with-env-and-working-dir",
+			inInstance: "my-instance",
+			inCmd:      "env",
+			inOpts: []options.Option{
+				options.WithEnv(map[string]string{"B": "2", "A": "1"}),
+				options.WithWorkingDir("/tmp"),
+			},
+			inCnts:  cnts,
+			inState: &types.ContainerState{Running: true},
+			wantState: &fakeExecingDocker{
+				Instance: "my-instance",
+				Opts: container.ExecOptions{
+					AttachStdout: true,
+					AttachStderr: true,
+					Env:          []string{"A=1", "B=2"},
+					WorkingDir:   "/tmp",
+					Cmd:          []string{"env"},
+				},
+			},
+		},
+		{
+			name:         "non-zero-exit",
+			inInstance:   "my-instance",
+			inCmd:        "false",
+			inCnts:       cnts,
+			inState:      &types.ContainerState{Running: true},
+			inStdout:     "some output\n",
+			inStderr:     "some error\n",
+			inExitCode:   3,
+			inRunning:    2,
+			wantOutput:   "some output\nsome error\n",
+			wantExitCode: 3,
+		},
+		{
+			name:       "cancelled",
+			inInstance: "my-instance",
+			inCmd:      "sleep infinity",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true},
+			inStdout:   "some output\n",
+			inHang:     true,
+			wantErr:    status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fed := &fakeExecingDocker{
+				cnts:     tc.inCnts,
+				state:    tc.inState,
+				stdout:   tc.inStdout,
+				stderr:   tc.inStderr,
+				exitCode: tc.inExitCode,
+				hang:     tc.inHang,
+				running:  tc.inRunning,
+			}
+			mgr := New(fed)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			output, exitCode, err := mgr.ContainerExec(ctx, tc.inInstance, tc.inCmd, tc.inOpts...)
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerExec(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inCmd, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerExec(%q, %q) returned error: %v", tc.inInstance, tc.inCmd, err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerExec(%q, %q) did not return an error, want %v", tc.inInstance, tc.inCmd, tc.wantErr)
+			}
+
+			if output != tc.wantOutput {
+				t.Errorf("ContainerExec(%q, %q) returned output %q, want %q", tc.inInstance, tc.inCmd, output, tc.wantOutput)
+			}
+			if exitCode != tc.wantExitCode {
+				t.Errorf("ContainerExec(%q, %q) returned exit code %d, want %d", tc.inInstance, tc.inCmd, exitCode, tc.wantExitCode)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fed, cmpopts.IgnoreUnexported(fakeExecingDocker{}), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("ContainerExec(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inCmd, diff)
+				}
+			}
+		})
+	}
+}
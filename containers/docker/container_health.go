@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// healthPollInterval is how often the health of a container is polled while waiting for it to
+// become healthy.
+var healthPollInterval = 500 * time.Millisecond
+
+// waitHealthy polls the container identified by id until its health check reports it healthy or
+// timeout elapses. The container is left running if it does not become healthy.
+func (m *Manager) waitHealthy(ctx context.Context, id, instance string, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		info, err := m.client.ContainerInspect(waitCtx, id)
+		if err != nil && waitCtx.Err() == nil {
+			return status.Errorf(codes.Internal, "unable to inspect container %s: %v", instance, err)
+		}
+
+		if err == nil {
+			if info.ContainerJSONBase == nil || info.State == nil || info.State.Health == nil {
+				return status.Errorf(codes.FailedPrecondition, "container %s has no health check configured", instance)
+			}
+			switch info.State.Health.Status {
+			case types.Healthy:
+				return nil
+			case types.Unhealthy:
+				return status.Errorf(codes.Unavailable, "container %s is unhealthy", instance)
+			}
+			if !info.State.Running {
+				return status.Errorf(codes.FailedPrecondition, "container %s exited before becoming healthy", instance)
+			}
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return contextError(ctx, status.Errorf(codes.DeadlineExceeded, "container %s did not become healthy within %s", instance, timeout))
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
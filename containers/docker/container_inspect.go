@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContainerInspect returns the normalized configuration and state of the provided instance.
+func (m *Manager) ContainerInspect(ctx context.Context, instance string) (*options.ContainerInfo, error) {
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+
+	cntJSON, err := m.jsonState(ctx, instance, cnts)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &options.ContainerInfo{
+		Mounts:   []*options.ContainerMount{},
+		Networks: []string{},
+	}
+	if cntJSON.ContainerJSONBase != nil {
+		info.ID = cntJSON.ID
+		info.Name = m.instanceOf(cntJSON.Name)
+		if cntJSON.State != nil {
+			info.Status = cntJSON.State.Status
+		}
+		if hc := cntJSON.HostConfig; hc != nil {
+			info.RestartPolicy = string(hc.RestartPolicy.Name)
+			info.RestartAttempts = hc.RestartPolicy.MaximumRetryCount
+			info.NetworkMode = string(hc.NetworkMode)
+			info.CPU = float64(hc.NanoCPUs) / 1e9
+			info.SoftMemory = hc.MemoryReservation
+			info.HardMemory = hc.Memory
+		}
+	}
+	if cfg := cntJSON.Config; cfg != nil {
+		info.Image = cfg.Image
+		info.Cmd = cfg.Cmd
+		info.Entrypoint = cfg.Entrypoint
+		info.Env = sortByKey(cfg.Env)
+
+		labels := make([]string, 0, len(cfg.Labels))
+		for k, v := range cfg.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+		info.Labels = sortByKey(labels)
+	}
+
+	for _, mnt := range cntJSON.Mounts {
+		src := mnt.Source
+		if mnt.Type == mount.TypeVolume {
+			src = mnt.Name
+		}
+		info.Mounts = append(info.Mounts, &options.ContainerMount{
+			Type:        string(mnt.Type),
+			Source:      src,
+			Destination: mnt.Destination,
+			ReadOnly:    !mnt.RW,
+		})
+	}
+	sort.Slice(info.Mounts, func(i, j int) bool {
+		return info.Mounts[i].Destination < info.Mounts[j].Destination
+	})
+
+	if cntJSON.NetworkSettings != nil {
+		for name := range cntJSON.NetworkSettings.Networks {
+			info.Networks = append(info.Networks, name)
+		}
+		sort.Strings(info.Networks)
+	}
+
+	return info, nil
+}
+
+// sortByKey sorts key=value pairs by their key. Sorting the raw strings is not sufficient as
+// keys which are prefixes of other keys would then be ordered by the characters following them.
+func sortByKey(kvs []string) []string {
+	sorted := append([]string{}, kvs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ki, _, _ := strings.Cut(sorted[i], "=")
+		kj, _, _ := strings.Cut(sorted[j], "=")
+		return ki < kj
+	})
+	return sorted
+}
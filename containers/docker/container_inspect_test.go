@@ -0,0 +1,182 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeInspectingDocker struct {
+	fakeDocker
+	cnts    []types.Container
+	cntJSON types.ContainerJSON
+}
+
+func (f *fakeInspectingDocker) ContainerList(context.Context, container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeInspectingDocker) ContainerInspect(context.Context, string) (types.ContainerJSON, error) {
+	return f.cntJSON, nil
+}
+
+func TestContainerInspect(t *testing.T) {
+	cnts := []types.Container{
+		{
+			ID:    "some-id",
+			Names: []string{"/my-instance"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inCnts     []types.Container
+		inJSON     types.ContainerJSON
+		wantInfo   *options.ContainerInfo
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inCnts:     cnts,
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "minimal",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inJSON: types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:   "some-id",
+					Name: "/my-instance",
+				},
+			},
+			wantInfo: &options.ContainerInfo{
+				ID:   "some-id",
+				Name: "my-instance",
+			},
+		},
+		{
+			name:       "full",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inJSON: types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:    "some-id",
+					Name:  "/my-instance",
+					State: &types.ContainerState{Status: "running"},
+					HostConfig: &container.HostConfig{
+						RestartPolicy: container.RestartPolicy{
+							Name:              container.RestartPolicyOnFailure,
+							MaximumRetryCount: 3,
+						},
+						NetworkMode: "some-network",
+						Resources: container.Resources{
+							NanoCPUs:          1500000000,
+							Memory:            2048,
+							MemoryReservation: 1024,
+						},
+					},
+				},
+				Config: &container.Config{
+					Image:      "my-image:my-tag",
+					Cmd:        []string{"my-cmd", "--flag"},
+					Entrypoint: []string{"/bin/sh", "-c"},
+					Env:        []string{"B=2", "A1=3", "A=1"},
+					Labels: map[string]string{
+						"some-label":  "some-value",
+						"other-label": "other-value",
+					},
+				},
+				Mounts: []types.MountPoint{
+					{
+						Type:        mount.TypeVolume,
+						Name:        "some-volume",
+						Source:      "/var/lib/docker/volumes/some-volume/_data",
+						Destination: "/data",
+						RW:          true,
+					},
+					{
+						Type:        mount.TypeBind,
+						Source:      "/etc/hosts",
+						Destination: "/config",
+					},
+				},
+				NetworkSettings: &types.NetworkSettings{
+					Networks: map[string]*network.EndpointSettings{
+						"some-network":  {},
+						"other-network": {},
+					},
+				},
+			},
+			wantInfo: &options.ContainerInfo{
+				ID:         "some-id",
+				Name:       "my-instance",
+				Image:      "my-image:my-tag",
+				Status:     "running",
+				Cmd:        []string{"my-cmd", "--flag"},
+				Entrypoint: []string{"/bin/sh", "-c"},
+				Env:        []string{"A=1", "A1=3", "B=2"},
+				Labels:     []string{"other-label=other-value", "some-label=some-value"},
+				Mounts: []*options.ContainerMount{
+					{
+						Type:        "bind",
+						Source:      "/etc/hosts",
+						Destination: "/config",
+						ReadOnly:    true,
+					},
+					{
+						Type:        "volume",
+						Source:      "some-volume",
+						Destination: "/data",
+					},
+				},
+				RestartPolicy:   "on-failure",
+				RestartAttempts: 3,
+				NetworkMode:     "some-network",
+				Networks:        []string{"other-network", "some-network"},
+				CPU:             1.5,
+				SoftMemory:      1024,
+				HardMemory:      2048,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fid := &fakeInspectingDocker{
+				cnts:    tc.inCnts,
+				cntJSON: tc.inJSON,
+			}
+			mgr := New(fid)
+
+			info, err := mgr.ContainerInspect(context.Background(), tc.inInstance)
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerInspect(%q) returned unexpected error(-want, got):\n %s", tc.inInstance, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerInspect(%q) returned error: %v", tc.inInstance, err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerInspect(%q) did not return an error, want %v", tc.inInstance, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantInfo, info, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("ContainerInspect(%q) returned diff(-want, +got):\n%s", tc.inInstance, diff)
+			}
+		})
+	}
+}
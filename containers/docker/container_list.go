@@ -2,16 +2,45 @@ package docker
 
 import (
 	"context"
+	"encoding/hex"
 	"io"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
+	tpb "github.com/openconfig/gnoi/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
 )
 
-// ContainerList lists the containers present on the target.
+// defaultListConcurrency is the number of containers inspected in parallel while listing, unless
+// overridden with options.WithConcurrency.
+const defaultListConcurrency = 8
+
+// containerStates maps the accepted state filter values to docker container states. The gNOI
+// status names are accepted alongside docker's own states.
+var containerStates = map[string]string{
+	"created":    "created",
+	"restarting": "restarting",
+	"running":    "running",
+	"removing":   "removing",
+	"paused":     "paused",
+	"exited":     "exited",
+	"dead":       "dead",
+	"stopped":    "exited",
+}
+
+// ContainerList lists the containers present on the target. Containers may be filtered by
+// label, in key or key=value form, and by state. Multiple label filters must all match. A
+// container that cannot be inspected is listed with only its ID, name and image, and an
+// UNSPECIFIED status.
 func (m *Manager) ContainerList(ctx context.Context, all bool, limit int32, srv options.ListContainerStreamer, opts ...options.Option) error {
 	optionz := options.ApplyOptions(opts...)
 
@@ -20,25 +49,56 @@ func (m *Manager) ContainerList(ctx context.Context, all bool, limit int32, srv
 	kvPairs := []filters.KeyValuePair{}
 	for key, values := range optionz.Filter {
 		for _, value := range values {
+			switch key {
+			case options.Label:
+				if err := checkLabelFilter(value); err != nil {
+					return err
+				}
+			case options.State:
+				state, ok := containerStates[strings.ToLower(value)]
+				if !ok {
+					return status.Errorf(codes.InvalidArgument, "invalid state filter %q", value)
+				}
+				value = state
+			}
 			kvPairs = append(kvPairs, filters.KeyValuePair{Key: string(key), Value: value})
 		}
 	}
 
 	cntOpts.Filters = filters.NewArgs(kvPairs...)
 
-	cnts, err := m.client.ContainerList(ctx, cntOpts)
+	cnts, err := m.listContainers(ctx, cntOpts)
 	if err != nil {
 		return err
 	}
+	if m.namePrefix != "" {
+		// Containers left without a name are those outside of the prefix.
+		cnts = slices.DeleteFunc(cnts, func(cnt types.Container) bool { return len(cnt.Names) == 0 })
+	}
+
+	concurrency := optionz.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultListConcurrency
+	}
 
-	for _, cnt := range cnts {
-		if err := srv.Send(&cpb.ListContainerResponse{
-			Id: cnt.ID,
-			// TODO(alshabib): make Name a repeated field.
-			Name:      strings.Join(cnt.Names, ","),
-			ImageName: cnt.Image,
-			Status:    stringToStatus(cnt.Status),
-		}); err != nil {
+	resps := make([]*cpb.ListContainerResponse, len(cnts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, cnt := range cnts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			resps[i] = m.enrich(ctx, cnt)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(resps, func(i, j int) bool { return resps[i].GetName() < resps[j].GetName() })
+
+	for _, resp := range resps {
+		if err := srv.Send(resp); err != nil {
 			if err == io.EOF {
 				return nil
 			}
@@ -49,6 +109,48 @@ func (m *Manager) ContainerList(ctx context.Context, all bool, limit int32, srv
 	return nil
 }
 
+// enrich builds the list entry for a container, completing the summary returned by docker with
+// the details of its inspection. If the container cannot be inspected, a partial entry built from
+// the summary alone is returned so that a single failure does not fail the whole listing. Partial
+// entries are flagged by an UNSPECIFIED status, which inspected containers never have.
+func (m *Manager) enrich(ctx context.Context, cnt types.Container) *cpb.ListContainerResponse {
+	resp := &cpb.ListContainerResponse{
+		Id: cnt.ID,
+		// TODO(alshabib): make Name a repeated field.
+		Name:      strings.Join(cnt.Names, ","),
+		ImageName: cnt.Image,
+		Status:    stringToStatus(cnt.Status),
+	}
+
+	info, err := m.inspectContainer(ctx, cnt.ID)
+	if err != nil || info.ContainerJSONBase == nil {
+		klog.Warningf("unable to inspect container %s, listing partial details: %v", resp.GetName(), err)
+		resp.Status = cpb.ListContainerResponse_UNSPECIFIED
+		return resp
+	}
+
+	if info.State != nil {
+		resp.Status = stateToStatus(info.State)
+	}
+	if digest, ok := strings.CutPrefix(info.Image, "sha256:"); ok {
+		if hash, err := hex.DecodeString(digest); err == nil {
+			resp.Hash = &tpb.HashType{Method: tpb.HashType_SHA256, Hash: hash}
+		}
+	}
+	return resp
+}
+
+func stateToStatus(state *types.ContainerState) cpb.ListContainerResponse_Status {
+	switch {
+	case state.Running, state.Paused, state.Restarting:
+		return cpb.ListContainerResponse_RUNNING
+	case state.Status == "created":
+		return cpb.ListContainerResponse_PRESENT
+	default:
+		return cpb.ListContainerResponse_STOPPED
+	}
+}
+
 func stringToStatus(state string) cpb.ListContainerResponse_Status {
 	switch {
 	case strings.Contains(state, "Up"):
@@ -59,3 +161,11 @@ func stringToStatus(state string) cpb.ListContainerResponse_Status {
 		return cpb.ListContainerResponse_UNSPECIFIED
 	}
 }
+
+// checkLabelFilter ensures a label filter takes the form key or key=value.
+func checkLabelFilter(value string) error {
+	if k, _, _ := strings.Cut(value, "="); k == "" {
+		return status.Errorf(codes.InvalidArgument, "malformed label filter %q: expected key or key=value", value)
+	}
+	return nil
+}
@@ -1,8 +1,13 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -11,7 +16,10 @@ import (
 	"github.com/docker/docker/api/types"
 	"google.golang.org/protobuf/testing/protocmp"
 	"github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	cpb "github.com/openconfig/gnoi/containerz"
+	tpb "github.com/openconfig/gnoi/types"
 )
 
 type fakeListContainerStreamer struct {
@@ -33,7 +41,32 @@ type fakeListingDocker struct {
 func (f *fakeListingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
 	f.Opts = options
 
-	return f.cnts, nil
+	cnts := []types.Container{}
+	for _, cnt := range f.cnts {
+		if options.Filters.Contains("label") && !options.Filters.MatchKVList("label", cnt.Labels) {
+			continue
+		}
+		if options.Filters.Contains("state") && !options.Filters.ExactMatch("state", cnt.State) {
+			continue
+		}
+		cnts = append(cnts, cnt)
+	}
+	return cnts, nil
+}
+
+// ContainerInspect reports the state of the listed container with the provided ID.
+func (f *fakeListingDocker) ContainerInspect(_ context.Context, id string) (types.ContainerJSON, error) {
+	for _, cnt := range f.cnts {
+		if cnt.ID == id {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:    id,
+					State: &types.ContainerState{Status: cnt.State, Running: cnt.State == "running"},
+				},
+			}, nil
+		}
+	}
+	return types.ContainerJSON{}, fmt.Errorf("no such container %s", id)
 }
 
 func TestContainerList(t *testing.T) {
@@ -45,6 +78,7 @@ func TestContainerList(t *testing.T) {
 		inLimit   int32
 		wantState *fakeListingDocker
 		wantMsgs  []*cpb.ListContainerResponse
+		wantErr   error
 	}{
 		{
 			name:    "no-containers",
@@ -84,11 +118,13 @@ func TestContainerList(t *testing.T) {
 					Id:        "some-id",
 					Name:      "some-name",
 					ImageName: "some-image",
+					Status:    cpb.ListContainerResponse_STOPPED,
 				},
 				&cpb.ListContainerResponse{
 					Id:        "some-other-id",
 					Name:      "some-other-name",
 					ImageName: "some-other-image",
+					Status:    cpb.ListContainerResponse_STOPPED,
 				},
 			},
 		},
@@ -104,10 +140,101 @@ func TestContainerList(t *testing.T) {
 				Opts: container.ListOptions{
 					Limit:   10,
 					All:     true,
-					Filters: filters.NewArgs(filters.Arg("image", "some-image"), filters.Arg("state", "RUNNING")),
+					Filters: filters.NewArgs(filters.Arg("image", "some-image"), filters.Arg("state", "running")),
+				},
+			},
+		},
+		{
+			name:    "filter-labels-and-state",
+			inAll:   true,
+			inLimit: 10,
+			inOpts: []options.Option{options.WithFilter(map[options.FilterKey][]string{
+				options.Label: []string{"managed-by=containerz", "tier"},
+				options.State: []string{"running"},
+			})},
+			inCnts: []types.Container{
+				types.Container{
+					ID:     "some-id",
+					Image:  "some-image",
+					Names:  []string{"some-name"},
+					Labels: map[string]string{"managed-by": "containerz", "tier": "edge"},
+					State:  "running",
+					Status: "Up 5 minutes",
+				},
+				types.Container{
+					ID:     "unmanaged-id",
+					Image:  "some-image",
+					Names:  []string{"unmanaged-name"},
+					Labels: map[string]string{"tier": "edge"},
+					State:  "running",
+					Status: "Up 5 minutes",
+				},
+				types.Container{
+					ID:     "untiered-id",
+					Image:  "some-image",
+					Names:  []string{"untiered-name"},
+					Labels: map[string]string{"managed-by": "containerz"},
+					State:  "running",
+					Status: "Up 5 minutes",
+				},
+				types.Container{
+					ID:     "stopped-id",
+					Image:  "some-image",
+					Names:  []string{"stopped-name"},
+					Labels: map[string]string{"managed-by": "containerz", "tier": "edge"},
+					State:  "exited",
+					Status: "Exited (0) 5 minutes ago",
+				},
+			},
+			wantState: &fakeListingDocker{
+				Opts: container.ListOptions{
+					Limit: 10,
+					All:   true,
+					Filters: filters.NewArgs(
+						filters.Arg("label", "managed-by=containerz"),
+						filters.Arg("label", "tier"),
+						filters.Arg("state", "running"),
+					),
+				},
+			},
+			wantMsgs: []*cpb.ListContainerResponse{
+				&cpb.ListContainerResponse{
+					Id:        "some-id",
+					Name:      "some-name",
+					ImageName: "some-image",
+					Status:    cpb.ListContainerResponse_RUNNING,
+				},
+			},
+		},
+		{
+			name:  "filter-stopped-state",
+			inAll: true,
+			inOpts: []options.Option{options.WithFilter(map[options.FilterKey][]string{
+				options.State: []string{"STOPPED"},
+			})},
+			wantState: &fakeListingDocker{
+				Opts: container.ListOptions{
+					All:     true,
+					Filters: filters.NewArgs(filters.Arg("state", "exited")),
 				},
 			},
 		},
+		{
+			name:  "invalid-state",
+			inAll: true,
+			inOpts: []options.Option{options.WithFilter(map[options.FilterKey][]string{
+				options.State: []string{"sleeping"},
+			})},
+			wantErr: status.Error(codes.InvalidArgument, `invalid state filter "sleeping"`),
+		},
+		{
+			name:  "malformed-label",
+			inAll: true,
+			inOpts: []options.Option{options.WithFilter(map[options.FilterKey][]string{
+				options.Label: []string{"=containerz"},
+			})},
+			wantErr: status.Error(codes.InvalidArgument, `malformed label filter "=containerz": expected key or key=value`),
+		},
 	}
 
 	for _, tc := range tests {
@@ -121,11 +248,20 @@ func TestContainerList(t *testing.T) {
 			stream := &fakeListContainerStreamer{}
 
 			if err := mgr.ContainerList(ctx, tc.inAll, tc.inLimit, stream, tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerList(%t, %q, %+v) returned unexpected error(-want, got):\n %s", tc.inAll, tc.inLimit, tc.inOpts, diff)
+					}
+					return
+				}
 				t.Errorf("ContainerList(%t, %q, %+v) returned error: %v", tc.inAll, tc.inLimit, tc.inOpts, err)
 			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerList(%t, %q, %+v) did not return an error, want %v", tc.inAll, tc.inLimit, tc.inOpts, tc.wantErr)
+			}
 
 			if tc.wantState != nil {
-				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeListingDocker{}, filters.Args{})); diff != "" {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeListingDocker{}), cmp.Transformer("filters", filterArgsToJSON)); diff != "" {
 					t.Errorf("ContainerList(%t, %q, %+v) returned diff(-want, +got):\n%s", tc.inAll, tc.inLimit, tc.inOpts, diff)
 				}
 			}
@@ -136,3 +272,81 @@ func TestContainerList(t *testing.T) {
 		})
 	}
 }
+
+type fakeEnrichingDocker struct {
+	fakeListingDocker
+	failID string
+
+	mu          sync.Mutex
+	inFlight    int
+	MaxInFlight int
+}
+
+func (f *fakeEnrichingDocker) ContainerInspect(_ context.Context, id string) (types.ContainerJSON, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.MaxInFlight = max(f.MaxInFlight, f.inFlight)
+	f.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+
+	if id == f.failID {
+		return types.ContainerJSON{}, fmt.Errorf("inspect failed")
+	}
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			Image: "sha256:" + strings.Repeat("ab", 32),
+			State: &types.ContainerState{Status: "created"},
+		},
+	}, nil
+}
+
+func TestContainerListEnrichment(t *testing.T) {
+	const count = 50
+	fsd := &fakeEnrichingDocker{failID: "id-17"}
+	// Containers are returned by docker in reverse name order.
+	for i := count - 1; i >= 0; i-- {
+		fsd.cnts = append(fsd.cnts, types.Container{
+			ID:     fmt.Sprintf("id-%02d", i),
+			Image:  "some-image",
+			Names:  []string{fmt.Sprintf("name-%02d", i)},
+			Status: "Exited (0) 5 minutes ago",
+		})
+	}
+
+	wantHash := &tpb.HashType{Method: tpb.HashType_SHA256, Hash: bytes.Repeat([]byte{0xab}, 32)}
+	var wantMsgs []*cpb.ListContainerResponse
+	for i := 0; i < count; i++ {
+		msg := &cpb.ListContainerResponse{
+			Id:        fmt.Sprintf("id-%02d", i),
+			Name:      fmt.Sprintf("name-%02d", i),
+			ImageName: "some-image",
+			Status:    cpb.ListContainerResponse_PRESENT,
+			Hash:      wantHash,
+		}
+		if msg.GetId() == fsd.failID {
+			// The container could not be inspected, it is flagged as a partial entry.
+			msg.Status = cpb.ListContainerResponse_UNSPECIFIED
+			msg.Hash = nil
+		}
+		wantMsgs = append(wantMsgs, msg)
+	}
+
+	mgr := New(fsd)
+	stream := &fakeListContainerStreamer{}
+	if err := mgr.ContainerList(context.Background(), true, 0, stream, options.WithConcurrency(4)); err != nil {
+		t.Fatalf("ContainerList() returned error: %v", err)
+	}
+
+	if diff := cmp.Diff(wantMsgs, stream.msgs, protocmp.Transform()); diff != "" {
+		t.Errorf("ContainerList() returned diff(-want, +got):\n%s", diff)
+	}
+	if fsd.MaxInFlight > 4 {
+		t.Errorf("ContainerList() inspected %d containers concurrently, want at most 4", fsd.MaxInFlight)
+	}
+}
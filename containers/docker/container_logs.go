@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"github.com/openconfig/containerz/containers"
@@ -13,11 +16,17 @@ import (
 )
 
 // ContainerLogs fetches the logs from a container. It can optionally follow the logs
-// and send them back to the client.
+// and send them back to the client. Logs are forwarded to the client as they are read from the
+// container runtime. Containers started without a TTY have their stdout and stderr streams
+// multiplexed by docker; these are demultiplexed before being sent. Containers with a TTY
+// produce a single raw stream which is forwarded as is.
 func (m *Manager) ContainerLogs(ctx context.Context, instance string, srv options.LogStreamer, opts ...options.Option) error {
 	optionz := options.ApplyOptions(opts...)
+	if optionz.NoStdout && optionz.NoStderr {
+		return status.Error(codes.InvalidArgument, "at least one of stdout and stderr must be streamed")
+	}
 
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{
+	cnts, err := m.listContainers(ctx, container.ListOptions{
 		// list all the containers - even ones which have exited.
 		All: true,
 		// TODO(alshabib): consider filtering for the image we care about
@@ -26,13 +35,20 @@ func (m *Manager) ContainerLogs(ctx context.Context, instance string, srv option
 		return err
 	}
 
-	if len(cnts) == 0 {
+	found := false
+	for _, cnt := range cnts {
+		if containerMatchesInstance(cnt, instance) {
+			found = true
+			break
+		}
+	}
+	if !found {
 		return status.Errorf(codes.NotFound, "container %s not found", instance)
 	}
 
 	logOpts := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
+		ShowStdout: !optionz.NoStdout,
+		ShowStderr: !optionz.NoStderr,
 	}
 
 	if optionz.Follow {
@@ -46,17 +62,42 @@ func (m *Manager) ContainerLogs(ctx context.Context, instance string, srv option
 
 	// TODO(alshabib): add this option to proto
 	if optionz.Until != 0 {
-		logOpts.Until = fmt.Sprintf("%s", optionz.Since)
+		logOpts.Until = fmt.Sprintf("%s", optionz.Until)
 	}
 
-	resp, err := m.client.ContainerLogs(ctx, instance, logOpts)
+	if optionz.Tail > 0 {
+		logOpts.Tail = strconv.Itoa(optionz.Tail)
+	}
+
+	cntJSON, err := m.inspectContainer(ctx, m.containerName(instance))
 	if err != nil {
-		return err
+		// The container may have been removed since it was listed.
+		if errdefs.IsNotFound(err) {
+			return status.Errorf(codes.NotFound, "container %s not found", instance)
+		}
+		return contextError(ctx, status.Errorf(codes.Unknown, "failed to inspect container %s: %v", instance, err))
+	}
+
+	resp, err := m.client.ContainerLogs(ctx, m.containerName(instance), logOpts)
+	if err != nil {
+		return contextError(ctx, err)
 	}
 	defer resp.Close()
+	// Followed logs only end with the container, so stop reading once the caller goes away.
+	defer closeOnCancel(ctx, resp)()
 
-	_, err = io.Copy(&logStreamer{srv: srv}, resp)
-	return err
+	streamer := &logStreamer{srv: srv}
+	if cntJSON.Config != nil && cntJSON.Config.Tty {
+		// A TTY merges stdout and stderr into a single raw stream, so there is nothing to
+		// demultiplex and the stream selection cannot be applied.
+		_, err = io.Copy(streamer, resp)
+	} else {
+		_, err = stdcopy.StdCopy(streamer, streamer, resp)
+	}
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	return nil
 }
 
 type logStreamer struct {
@@ -3,6 +3,7 @@ package docker
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -11,6 +12,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"github.com/openconfig/containerz/containers"
@@ -30,9 +33,30 @@ type fakeLoggingDocker struct {
 	fakeDocker
 	cnts  []types.Container
 	inMsg string
+	noTTY bool
+	// inspectErr, if set, is returned by ContainerInspect, after cancelling the call with cancel
+	// when that is set too.
+	inspectErr error
+	cancel     context.CancelFunc
 
 	Instance string
 	Follow   bool
+	Stdout   bool
+	Stderr   bool
+	Since    string
+	Tail     string
+}
+
+func (f fakeLoggingDocker) ContainerInspect(ctx context.Context, _ string) (types.ContainerJSON, error) {
+	if f.inspectErr != nil {
+		if f.cancel != nil {
+			f.cancel()
+		}
+		return types.ContainerJSON{}, f.inspectErr
+	}
+	return types.ContainerJSON{
+		Config: &container.Config{Tty: !f.noTTY},
+	}, nil
 }
 
 func (f fakeLoggingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
@@ -42,6 +66,10 @@ func (f fakeLoggingDocker) ContainerList(ctx context.Context, options container.
 func (f *fakeLoggingDocker) ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error) {
 	f.Instance = container
 	f.Follow = options.Follow
+	f.Stdout = options.ShowStdout
+	f.Stderr = options.ShowStderr
+	f.Since = options.Since
+	f.Tail = options.Tail
 
 	return io.NopCloser(bytes.NewBuffer([]byte(f.inMsg))), nil
 }
@@ -53,6 +81,9 @@ func TestContainerLogs(t *testing.T) {
 		inOpts     []options.Option
 		inInstance string
 		inMsg      string
+		inNoTTY    bool
+		inInspErr  error
+		inCancel   bool
 		inCnts     []types.Container
 		wantState  *fakeLoggingDocker
 		wantMsgs   []string
@@ -75,6 +106,8 @@ func TestContainerLogs(t *testing.T) {
 			wantState: &fakeLoggingDocker{
 				Instance: "instance-with-logs",
 				Follow:   false,
+				Stdout:   true,
+				Stderr:   true,
 			},
 			wantMsgs: []string{"we have the logs"},
 		},
@@ -91,17 +124,110 @@ func TestContainerLogs(t *testing.T) {
 			wantState: &fakeLoggingDocker{
 				Instance: "instance-with-logs",
 				Follow:   true,
+				Stdout:   true,
+				Stderr:   true,
 			},
 			wantMsgs: []string{"we have the logs"},
 		},
+		{
+			name:       "instance-not-in-list",
+			inInstance: "no-such-instance",
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			wantErr: status.Errorf(codes.NotFound, "container no-such-instance not found"),
+		},
+		{
+			name:       "instance-with-since-tail-and-stderr-only",
+			inInstance: "instance-with-logs",
+			inOpts: []options.Option{
+				options.Follow(),
+				options.WithSince(time.Minute),
+				options.WithTail(10),
+				options.WithoutStdout(),
+			},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			inMsg: "we have the logs",
+			wantState: &fakeLoggingDocker{
+				Instance: "instance-with-logs",
+				Follow:   true,
+				Stderr:   true,
+				Since:    "1m0s",
+				Tail:     "10",
+			},
+			wantMsgs: []string{"we have the logs"},
+		},
+		{
+			name:       "instance-with-multiplexed-logs",
+			inInstance: "instance-with-logs",
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			inMsg:   multiplex(t, []string{"stdout line\n", "stderr line\n"}),
+			inNoTTY: true,
+			wantState: &fakeLoggingDocker{
+				Instance: "instance-with-logs",
+				Stdout:   true,
+				Stderr:   true,
+			},
+			wantMsgs: []string{"stdout line\n", "stderr line\n"},
+		},
+		{
+			name:       "instance-without-stdout-and-stderr",
+			inInstance: "instance-with-logs",
+			inOpts:     []options.Option{options.WithoutStdout(), options.WithoutStderr()},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			wantErr: status.Error(codes.InvalidArgument, "at least one of stdout and stderr must be streamed"),
+		},
+		{
+			name:       "instance-removed-before-inspect",
+			inInstance: "instance-with-logs",
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			inInspErr: errdefs.NotFound(errors.New("no such container")),
+			wantErr:   status.Errorf(codes.NotFound, "container instance-with-logs not found"),
+		},
+		{
+			name:       "cancelled-during-inspect",
+			inInstance: "instance-with-logs",
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"/instance-with-logs"},
+				},
+			},
+			inInspErr: context.Canceled,
+			inCancel:  true,
+			wantErr:   status.Error(codes.Canceled, "context canceled"),
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := context.Background()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
 			fsd := &fakeLoggingDocker{
-				cnts:  tc.inCnts,
-				inMsg: tc.inMsg,
+				cnts:       tc.inCnts,
+				inMsg:      tc.inMsg,
+				noTTY:      tc.inNoTTY,
+				inspectErr: tc.inInspErr,
+			}
+			if tc.inCancel {
+				fsd.cancel = cancel
 			}
 			mgr := New(fsd)
 
@@ -116,6 +242,9 @@ func TestContainerLogs(t *testing.T) {
 				}
 				t.Errorf("ContainerLogs(%q, %+v) returned error: %v", tc.inInstance, tc.inOpts, err)
 			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerLogs(%q, %+v) did not return an error, want %v", tc.inInstance, tc.inOpts, tc.wantErr)
+			}
 
 			if tc.wantState != nil {
 				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeLoggingDocker{})); diff != "" {
@@ -129,3 +258,20 @@ func TestContainerLogs(t *testing.T) {
 		})
 	}
 }
+
+// multiplex builds a docker multiplexed log stream where messages alternate between stdout and
+// stderr.
+func multiplex(t *testing.T, msgs []string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	for i, msg := range msgs {
+		stream := stdcopy.Stdout
+		if i%2 == 1 {
+			stream = stdcopy.Stderr
+		}
+		if _, err := stdcopy.NewStdWriter(&buf, stream).Write([]byte(msg)); err != nil {
+			t.Fatalf("failed to write multiplexed message: %v", err)
+		}
+	}
+	return buf.String()
+}
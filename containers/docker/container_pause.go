@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContainerPause freezes all the processes of a running container.
+func (m *Manager) ContainerPause(ctx context.Context, instance string, opts ...options.Option) error {
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case state.Paused:
+		return status.Errorf(codes.FailedPrecondition, "container %s is already paused", instance)
+	case !state.Running:
+		return status.Errorf(codes.FailedPrecondition, "container %s is not running", instance)
+	}
+
+	if err := m.client.ContainerPause(ctx, m.containerName(instance)); err != nil {
+		return status.Errorf(codes.Internal, "unable to pause container %s: %v", instance, err)
+	}
+	return nil
+}
+
+// ContainerUnpause resumes the processes of a paused container.
+func (m *Manager) ContainerUnpause(ctx context.Context, instance string, opts ...options.Option) error {
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return err
+	}
+
+	if !state.Paused {
+		return status.Errorf(codes.FailedPrecondition, "container %s is not paused", instance)
+	}
+
+	if err := m.client.ContainerUnpause(ctx, m.containerName(instance)); err != nil {
+		return status.Errorf(codes.Internal, "unable to unpause container %s: %v", instance, err)
+	}
+	return nil
+}
+
+// containerState returns the runtime state of the provided instance.
+func (m *Manager) containerState(ctx context.Context, instance string) (*types.ContainerState, error) {
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+
+	cntJSON, err := m.jsonState(ctx, instance, cnts)
+	if err != nil {
+		return nil, err
+	}
+	if cntJSON.ContainerJSONBase == nil || cntJSON.State == nil {
+		return nil, status.Errorf(codes.Unknown, "unable to determine the state of container %s", instance)
+	}
+	return cntJSON.State, nil
+}
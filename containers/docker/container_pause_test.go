@@ -0,0 +1,184 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePausingDocker struct {
+	fakeDocker
+	cnts  []types.Container
+	state *types.ContainerState
+
+	Paused   string
+	Unpaused string
+}
+
+func (f fakePausingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f fakePausingDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: f.state,
+		},
+	}, nil
+}
+
+func (f *fakePausingDocker) ContainerPause(ctx context.Context, container string) error {
+	f.Paused = container
+	return nil
+}
+
+func (f *fakePausingDocker) ContainerUnpause(ctx context.Context, container string) error {
+	f.Unpaused = container
+	return nil
+}
+
+func TestContainerPause(t *testing.T) {
+	cnts := []types.Container{
+		{
+			ID:    "my-instance",
+			Names: []string{"/my-instance"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inCnts     []types.Container
+		inState    *types.ContainerState
+		wantState  *fakePausingDocker
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "already-paused",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true, Paused: true},
+			wantErr:    status.Errorf(codes.FailedPrecondition, "container my-instance is already paused"),
+		},
+		{
+			name:       "not-running",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{},
+			wantErr:    status.Errorf(codes.FailedPrecondition, "container my-instance is not running"),
+		},
+		{
+			name:       "pause",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true},
+			wantState: &fakePausingDocker{
+				Paused: "my-instance",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakePausingDocker{
+				cnts:  tc.inCnts,
+				state: tc.inState,
+			}
+			mgr := New(fsd)
+
+			if err := mgr.ContainerPause(context.Background(), tc.inInstance); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerPause(%q) returned unexpected error(-want, got):\n %s", tc.inInstance, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerPause(%q) returned error: %v", tc.inInstance, err)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakePausingDocker{})); diff != "" {
+					t.Errorf("ContainerPause(%q) returned diff(-want, +got):\n%s", tc.inInstance, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerUnpause(t *testing.T) {
+	cnts := []types.Container{
+		{
+			ID:    "my-instance",
+			Names: []string{"/my-instance"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inCnts     []types.Container
+		inState    *types.ContainerState
+		wantState  *fakePausingDocker
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "not-paused",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true},
+			wantErr:    status.Errorf(codes.FailedPrecondition, "container my-instance is not paused"),
+		},
+		{
+			name:       "unpause",
+			inInstance: "my-instance",
+			inCnts:     cnts,
+			inState:    &types.ContainerState{Running: true, Paused: true},
+			wantState: &fakePausingDocker{
+				Unpaused: "my-instance",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakePausingDocker{
+				cnts:  tc.inCnts,
+				state: tc.inState,
+			}
+			mgr := New(fsd)
+
+			if err := mgr.ContainerUnpause(context.Background(), tc.inInstance); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerUnpause(%q) returned unexpected error(-want, got):\n %s", tc.inInstance, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerUnpause(%q) returned error: %v", tc.inInstance, err)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakePausingDocker{})); diff != "" {
+					t.Errorf("ContainerUnpause(%q) returned diff(-want, +got):\n%s", tc.inInstance, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// Reconcile removes the containers managed by containerz whose instance name is not in desired,
+// and returns the instance names of the containers it removed, in order. Containers that are not
+// managed by containerz are never removed. Running containers are only removed if the Force option
+// is set; otherwise they are left in place.
+//
+// Reconcile stops at the first container that fails to be removed, returning those removed so far
+// along with the error.
+func (m *Manager) Reconcile(ctx context.Context, desired []string, opts ...options.Option) ([]string, error) {
+	optionz := options.ApplyOptions(opts...)
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", managedByLabel, managedByValue))),
+	})
+	if err != nil {
+		return nil, contextError(ctx, status.Errorf(codes.Internal, "unable to list containers: %v", err))
+	}
+	if m.namePrefix != "" {
+		// Containers left without a name are those outside of the prefix.
+		cnts = slices.DeleteFunc(cnts, func(cnt types.Container) bool { return len(cnt.Names) == 0 })
+	}
+	sort.Slice(cnts, func(i, j int) bool { return instanceName(cnts[i]) < instanceName(cnts[j]) })
+
+	removed := []string{}
+	for _, cnt := range cnts {
+		instance := instanceName(cnt)
+		if cnt.Labels[managedByLabel] != managedByValue || slices.Contains(desired, instance) {
+			continue
+		}
+		if running := cnt.State == "running" || cnt.State == "paused" || cnt.State == "restarting"; running && !optionz.Force {
+			klog.Infof("not removing orphaned container %s: it is %s", instance, cnt.State)
+			continue
+		}
+
+		if err := m.reap(ctx, cnt, instance, optionz.Force); err != nil {
+			return removed, err
+		}
+		removed = append(removed, instance)
+	}
+	return removed, nil
+}
+
+// instanceName returns the instance name of cnt, or its ID if it has no name.
+func instanceName(cnt types.Container) string {
+	if len(cnt.Names) == 0 {
+		return cnt.ID
+	}
+	return strings.TrimPrefix(cnt.Names[0], "/")
+}
+
+// reap removes the orphaned container cnt, along with its secrets. A container that no longer
+// exists is considered removed.
+func (m *Manager) reap(ctx context.Context, cnt types.Container, instance string, force bool) (err error) {
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationContainerRemove, start, instance, "", err) }()
+
+	if err := m.client.ContainerRemove(ctx, cnt.ID, container.RemoveOptions{Force: force}); err != nil && !errdefs.IsNotFound(err) {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to remove container %s: %v", instance, err))
+	}
+	m.removeSecrets(cnt.Labels[secretsLabel])
+	return nil
+}
@@ -0,0 +1,118 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeReconcilingDocker struct {
+	fakeDocker
+	cnts       []types.Container
+	removeErrs map[string]error
+
+	Label   string
+	Removed []string
+	Forced  []bool
+}
+
+func (f *fakeReconcilingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	f.Label = options.Filters.Get("label")[0]
+	return f.cnts, nil
+}
+
+func (f *fakeReconcilingDocker) ContainerRemove(ctx context.Context, id string, options container.RemoveOptions) error {
+	if err := f.removeErrs[id]; err != nil {
+		return err
+	}
+	f.Removed = append(f.Removed, id)
+	f.Forced = append(f.Forced, options.Force)
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	managed := map[string]string{managedByLabel: managedByValue}
+	cnts := []types.Container{
+		{ID: "wanted-id", Names: []string{"/wanted"}, State: "running", Labels: managed},
+		{ID: "orphan-id", Names: []string{"/orphan"}, State: "exited", Labels: managed},
+		{ID: "running-id", Names: []string{"/running-orphan"}, State: "running", Labels: managed},
+		{ID: "unmanaged-id", Names: []string{"/unmanaged"}, State: "exited"},
+		{ID: "created-id", Names: []string{"/created-orphan"}, State: "created", Labels: managed},
+	}
+
+	tests := []struct {
+		name        string
+		inRemoveErr map[string]error
+		inOpts      []options.Option
+		wantRemoved []string
+		wantIDs     []string
+		wantForced  []bool
+		wantErr     error
+	}{
+		{
+			name:        "stopped-orphans",
+			wantRemoved: []string{"created-orphan", "orphan"},
+			wantIDs:     []string{"created-id", "orphan-id"},
+			wantForced:  []bool{false, false},
+		},
+		{
+			name:        "force-running-orphans",
+			inOpts:      []options.Option{options.Force()},
+			wantRemoved: []string{"created-orphan", "orphan", "running-orphan"},
+			wantIDs:     []string{"created-id", "orphan-id", "running-id"},
+			wantForced:  []bool{true, true, true},
+		},
+		{
+			name:        "already-removed",
+			inRemoveErr: map[string]error{"created-id": errdefs.NotFound(fmt.Errorf("no such container"))},
+			wantRemoved: []string{"created-orphan", "orphan"},
+			wantIDs:     []string{"orphan-id"},
+			wantForced:  []bool{false},
+		},
+		{
+			name:        "remove-failure",
+			inRemoveErr: map[string]error{"orphan-id": fmt.Errorf("device busy")},
+			wantRemoved: []string{"created-orphan"},
+			wantIDs:     []string{"created-id"},
+			wantForced:  []bool{false},
+			wantErr:     status.Errorf(codes.Internal, "unable to remove container orphan: device busy"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			frd := &fakeReconcilingDocker{cnts: cnts, removeErrs: tc.inRemoveErr}
+			mgr := New(frd)
+
+			removed, err := mgr.Reconcile(context.Background(), []string{"wanted", "not-running"}, tc.inOpts...)
+			if tc.wantErr != nil {
+				if err == nil || err.Error() != tc.wantErr.Error() {
+					t.Errorf("Reconcile() returned error %v, want %v", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Reconcile() returned unexpected error: %v", err)
+			}
+
+			if diff := cmp.Diff(tc.wantRemoved, removed); diff != "" {
+				t.Errorf("Reconcile() returned diff (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantIDs, frd.Removed); diff != "" {
+				t.Errorf("Reconcile() removed containers diff (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantForced, frd.Forced); diff != "" {
+				t.Errorf("Reconcile() forced removal diff (-want, +got):\n%s", diff)
+			}
+			if want := managedByLabel + "=" + managedByValue; frd.Label != want {
+				t.Errorf("Reconcile() listed containers with label %q, want %q", frd.Label, want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"strings"
+	"time"
 
 	options "github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
@@ -12,12 +13,15 @@ import (
 	"github.com/docker/docker/api/types/container"
 )
 
-// ContainerRemove removes an image provided it is not related to a running container. Otherwise,
-// it returns an error.
-func (m *Manager) ContainerRemove(ctx context.Context, cnt string, opts ...options.Option) error {
+// ContainerRemove removes a container provided it is not running, unless the Force option is set.
+// The anonymous volumes of the container are removed with it if the RemoveVolumes option is set.
+func (m *Manager) ContainerRemove(ctx context.Context, cnt string, opts ...options.Option) (err error) {
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationContainerRemove, start, cnt, "", err) }()
+
 	optionz := options.ApplyOptions(opts...)
 
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{All: true})
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
 	if err != nil {
 		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
 	}
@@ -27,17 +31,19 @@ func (m *Manager) ContainerRemove(ctx context.Context, cnt string, opts ...optio
 			strippedname := strings.Replace(name, "/", "", 1)
 			if strippedname == cnt {
 				if stringToStatus(c.Status) == cpb.ListContainerResponse_RUNNING && !optionz.Force {
-					return status.Errorf(codes.FailedPrecondition, "container %s is running", cnt)
+					return detailedError(codes.FailedPrecondition, reasonContainerRunning, map[string]string{"instance": cnt}, "container %s is running", cnt)
 				}
-				if err := m.client.ContainerRemove(ctx, cnt, container.RemoveOptions{
-					Force: optionz.Force,
+				if err := m.client.ContainerRemove(ctx, m.containerName(cnt), container.RemoveOptions{
+					Force:         optionz.Force,
+					RemoveVolumes: optionz.RemoveVolumes,
 				}); err != nil {
 					return status.Errorf(codes.Internal, "unable to remove container: %v", err)
 				}
+				m.removeSecrets(c.Labels[secretsLabel])
 				return nil
 			}
 		}
 	}
 
-	return status.Errorf(codes.NotFound, "container %s not found", cnt)
+	return detailedError(codes.NotFound, reasonContainerNotFound, map[string]string{"instance": cnt}, "container %s not found", cnt)
 }
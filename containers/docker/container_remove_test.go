@@ -12,11 +12,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (f *fakeRemovingDocker) ContainerRemove(ctx context.Context, cnt string, options container.RemoveOptions) error {
 	f.Name = cnt
+	f.Opts = options
 	return nil
 }
 
@@ -33,7 +33,7 @@ func TestContainerRemove(t *testing.T) {
 		{
 			name:    "no-such-container",
 			inCnt:   "no-such-container",
-			wantErr: status.Error(codes.NotFound, "container no-such-container not found"),
+			wantErr: detailedError(codes.NotFound, reasonContainerNotFound, map[string]string{"instance": "no-such-container"}, "container no-such-container not found"),
 		},
 		{
 			name:  "container-running",
@@ -50,7 +50,7 @@ func TestContainerRemove(t *testing.T) {
 					Status: "Up",
 				},
 			},
-			wantErr: status.Errorf(codes.FailedPrecondition, "container container-running is running"),
+			wantErr: detailedError(codes.FailedPrecondition, reasonContainerRunning, map[string]string{"instance": "container-running"}, "container container-running is running"),
 		},
 		{
 			name:   "container-running-with-force",
@@ -70,6 +70,52 @@ func TestContainerRemove(t *testing.T) {
 			},
 			wantState: &fakeRemovingDocker{
 				Name: "container-running",
+				Opts: container.RemoveOptions{Force: true},
+			},
+		},
+		{
+			name:   "container-running-with-force-and-volumes",
+			inCnt:  "container-running",
+			inOpts: []options.Option{options.Force(), options.WithRemoveVolumes()},
+			inCnts: []types.Container{
+				types.Container{
+					Image:  "container-running",
+					Names:  []string{"container-running"},
+					Status: "Up",
+				},
+			},
+			wantState: &fakeRemovingDocker{
+				Name: "container-running",
+				Opts: container.RemoveOptions{Force: true, RemoveVolumes: true},
+			},
+		},
+		{
+			name:   "container-running-with-volumes",
+			inCnt:  "container-running",
+			inOpts: []options.Option{options.WithRemoveVolumes()},
+			inCnts: []types.Container{
+				types.Container{
+					Image:  "container-running",
+					Names:  []string{"container-running"},
+					Status: "Up",
+				},
+			},
+			wantErr: detailedError(codes.FailedPrecondition, reasonContainerRunning, map[string]string{"instance": "container-running"}, "container container-running is running"),
+		},
+		{
+			name:   "container-remove-with-volumes",
+			inCnt:  "container-remove",
+			inOpts: []options.Option{options.WithRemoveVolumes()},
+			inCnts: []types.Container{
+				types.Container{
+					Image:  "container-remove",
+					Names:  []string{"container-remove"},
+					Status: "Exited",
+				},
+			},
+			wantState: &fakeRemovingDocker{
+				Name: "container-remove",
+				Opts: container.RemoveOptions{RemoveVolumes: true},
 			},
 		},
 		{
@@ -110,6 +156,9 @@ func TestContainerRemove(t *testing.T) {
 				}
 				t.Errorf("ContainerRemove(%q, %+v) returned error: %v", tc.inCnt, tc.inOpts, err)
 			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerRemove(%q, %+v) did not return an error, want %v", tc.inCnt, tc.inOpts, tc.wantErr)
+			}
 
 			if tc.wantState != nil {
 				if diff := cmp.Diff(tc.wantState, fpd, cmpopts.IgnoreUnexported(fakeRemovingDocker{})); diff != "" {
@@ -0,0 +1,39 @@
+package docker
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/docker/docker/api/types/container"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validContainerName is the set of names accepted by docker for containers.
+var validContainerName = regexp.MustCompile(`^/?[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// ContainerRename renames an existing container instance. The new name must be valid and must not
+// already be in use by another container.
+func (m *Manager) ContainerRename(ctx context.Context, instance, newName string, opts ...options.Option) error {
+	if !validContainerName.MatchString(newName) {
+		return status.Errorf(codes.InvalidArgument, "invalid container name %q", newName)
+	}
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+
+	if err := checkInstanceExists(instance, cnts); err != nil {
+		return err
+	}
+	if err := checkInstanceExists(newName, cnts); err == nil {
+		return detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": newName}, "instance name %s already in use", newName)
+	}
+
+	if err := m.client.ContainerRename(ctx, m.containerName(instance), m.containerName(newName)); err != nil {
+		return status.Errorf(codes.Internal, "unable to rename container %s to %s: %v", instance, newName, err)
+	}
+	return nil
+}
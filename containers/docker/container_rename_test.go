@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRenamingDocker struct {
+	fakeDocker
+	cnts []types.Container
+
+	Instance string
+	NewName  string
+}
+
+func (f fakeRenamingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeRenamingDocker) ContainerRename(ctx context.Context, container, newContainerName string) error {
+	f.Instance = container
+	f.NewName = newContainerName
+	return nil
+}
+
+func TestContainerRename(t *testing.T) {
+	tests := []struct {
+		name       string
+		inInstance string
+		inNewName  string
+		inCnts     []types.Container
+		wantState  *fakeRenamingDocker
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inNewName:  "new-name",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "invalid-name",
+			inInstance: "my-instance",
+			inNewName:  "-bad name",
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid container name "-bad name"`),
+		},
+		{
+			name:       "name-in-use",
+			inInstance: "my-instance",
+			inNewName:  "other-instance",
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+				{
+					Names: []string{"/other-instance"},
+				},
+			},
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "other-instance"}, "instance name other-instance already in use"),
+		},
+		{
+			name:       "rename",
+			inInstance: "my-instance",
+			inNewName:  "my-canary",
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRenamingDocker{
+				Instance: "my-instance",
+				NewName:  "my-canary",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeRenamingDocker{
+				cnts: tc.inCnts,
+			}
+			mgr := New(fsd)
+
+			if err := mgr.ContainerRename(context.Background(), tc.inInstance, tc.inNewName); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerRename(%q, %q) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inNewName, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerRename(%q, %q) returned error: %v", tc.inInstance, tc.inNewName, err)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeRenamingDocker{})); diff != "" {
+					t.Errorf("ContainerRename(%q, %q) returned diff(-want, +got):\n%s", tc.inInstance, tc.inNewName, diff)
+				}
+			}
+		})
+	}
+}
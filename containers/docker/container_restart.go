@@ -0,0 +1,55 @@
+package docker
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// ContainerRestart stops and starts a container while preserving its configuration. The timeout
+// is the time to wait for the container to stop before killing it; if it is zero the engine
+// default is used unless the Force option is set, in which case the container is killed
+// immediately. Timeouts are rounded up to the next second. A stop signal may be provided to
+// replace the engine default (SIGTERM).
+func (m *Manager) ContainerRestart(ctx context.Context, instance string, timeout time.Duration, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+
+	signal, err := stopSignal(optionz.StopSignal)
+	if err != nil {
+		return err
+	}
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+
+	if err := checkInstanceExists(instance, cnts); err != nil {
+		return err
+	}
+
+	var pTimeout *int
+	switch {
+	case timeout > 0:
+		// The engine counts in whole seconds: round up so a short timeout never kills right away.
+		seconds := int(math.Ceil(timeout.Seconds()))
+		pTimeout = &seconds
+	case optionz.Force:
+		seconds := 0
+		pTimeout = &seconds
+	}
+
+	if err := m.client.ContainerRestart(ctx, m.containerName(instance), container.StopOptions{Signal: signal, Timeout: pTimeout}); err != nil {
+		klog.Warningf("container %s failed to restart", instance)
+		return status.Errorf(codes.Unknown, "failed to restart container %s with error %s",
+			instance, err)
+	}
+
+	return nil
+}
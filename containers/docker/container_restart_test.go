@@ -0,0 +1,150 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRestartingDocker struct {
+	fakeDocker
+	cnts []types.Container
+
+	Instance string
+	Timeout  *int
+	Signal   string
+}
+
+func (f fakeRestartingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeRestartingDocker) ContainerRestart(ctx context.Context, container string, options container.StopOptions) error {
+	f.Instance = container
+	f.Timeout = options.Timeout
+	f.Signal = options.Signal
+	return nil
+}
+
+func TestContainerRestart(t *testing.T) {
+	seconds := func(s int) *int { return &s }
+
+	tests := []struct {
+		name       string
+		inInstance string
+		inTimeout  time.Duration
+		inOpts     []options.Option
+		inCnts     []types.Container
+		wantState  *fakeRestartingDocker
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "restart-default-timeout",
+			inInstance: "my-instance",
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRestartingDocker{
+				Instance: "my-instance",
+			},
+		},
+		{
+			name:       "restart-with-timeout-and-signal",
+			inInstance: "my-instance",
+			inTimeout:  30 * time.Second,
+			inOpts:     []options.Option{options.WithStopSignal("SIGINT")},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRestartingDocker{
+				Instance: "my-instance",
+				Timeout:  seconds(30),
+				Signal:   "SIGINT",
+			},
+		},
+		{
+			name:       "restart-with-sub-second-timeout",
+			inInstance: "my-instance",
+			inTimeout:  500 * time.Millisecond,
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRestartingDocker{
+				Instance: "my-instance",
+				Timeout:  seconds(1),
+			},
+		},
+		{
+			name:       "restart-with-fractional-timeout",
+			inInstance: "my-instance",
+			inTimeout:  1500 * time.Millisecond,
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRestartingDocker{
+				Instance: "my-instance",
+				Timeout:  seconds(2),
+			},
+		},
+		{
+			name:       "restart-with-force",
+			inInstance: "my-instance",
+			inOpts:     []options.Option{options.Force()},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-instance"},
+				},
+			},
+			wantState: &fakeRestartingDocker{
+				Instance: "my-instance",
+				Timeout:  seconds(0),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeRestartingDocker{
+				cnts: tc.inCnts,
+			}
+			mgr := New(fsd)
+
+			if err := mgr.ContainerRestart(context.Background(), tc.inInstance, tc.inTimeout, tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerRestart(%q, %v, %+v) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inTimeout, tc.inOpts, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerRestart(%q, %v, %+v) returned error: %v", tc.inInstance, tc.inTimeout, tc.inOpts, err)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeRestartingDocker{})); diff != "" {
+					t.Errorf("ContainerRestart(%q, %v, %+v) returned diff(-want, +got):\n%s", tc.inInstance, tc.inTimeout, tc.inOpts, diff)
+				}
+			}
+		})
+	}
+}
@@ -1,53 +1,142 @@
 package docker
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	options "github.com/openconfig/containerz/containers"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"k8s.io/klog/v2"
 
 	"github.com/google/shlex"
 	cpb "github.com/openconfig/gnoi/containerz"
+	tpb "github.com/openconfig/gnoi/types"
+)
+
+const (
+	// managedByLabel is the label set on every container started by containerz.
+	managedByLabel = "managed-by"
+	// managedByValue is the value of managedByLabel on containers started by containerz.
+	managedByValue = "containerz"
+
+	// maxPort is the highest valid port number.
+	maxPort = 65535
+
+	// cleanupTimeout bounds the removal of a container whose start did not complete.
+	cleanupTimeout = 10 * time.Second
 )
 
 // ContainerStart starts a container provided the image exists and that the ports requested are not
-// currently in use.
+// currently in use. Containers are labelled as managed by containerz, overriding any user supplied
+// value for that label. If asked to wait for the container to become healthy, the container is
+// left running when it does not, and its name is returned along with the error.
 func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string, opts ...options.Option) (string, error) {
+	start := time.Now()
+	name, err := m.containerStart(ctx, imageName, tag, cmd, opts...)
+
+	if optionz := options.ApplyOptions(opts...); optionz.DryRun == nil {
+		instance := name
+		if instance == "" {
+			instance = optionz.InstanceName
+		}
+		ref, refErr := normalizeReference(imageName, tag)
+		if refErr != nil {
+			ref = fmt.Sprintf("%s:%s", imageName, tag)
+		}
+		m.emit(ctx, OperationContainerStart, start, instance, ref, err)
+	}
+	return name, err
+}
+
+func (m *Manager) containerStart(ctx context.Context, imageName, tag, cmd string, opts ...options.Option) (string, error) {
 	optionz := options.ApplyOptions(opts...)
 
-	images, err := m.client.ImageList(ctx, image.ListOptions{
-		// TODO(alshabib): consider filtering for the image we care about
-	})
+	ref, err := normalizeReference(imageName, tag)
+	if err != nil {
+		return "", err
+	}
+	policy := optionz.PullPolicy
+	if optionz.DryRun != nil {
+		policy = options.PullNever
+	}
+	platform, err := ociPlatform(optionz.Platform)
+	if err != nil {
+		return "", err
+	}
+	summary, err := m.startImage(ctx, ref, policy, optionz.Credentials, optionz.Platform)
 	if err != nil {
 		return "", err
 	}
 
-	ref := fmt.Sprintf("%s:%s", imageName, tag)
-	if err := findImage(ref, images); err != nil {
+	if optionz.ExpectedDigest != "" {
+		if err := checkDigest(ref, optionz.ExpectedDigest, summary); err != nil {
+			return "", err
+		}
+	}
+	if err := checkImageLabels(ref, m.requiredImageLabels, summary.Labels); err != nil {
 		return "", err
 	}
 
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{
+	cnts, err := m.listContainers(ctx, container.ListOptions{
 		// TODO(alshabib): consider filtering for the image we care about
 	})
 	if err != nil {
 		return "", err
 	}
 
-	if err := checkExistingInstanceAndPorts(optionz.InstanceName, optionz.PortMapping, cnts); err != nil {
+	ports, err := portMappings(optionz.PortMapping, optionz.Ports)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkPorts(ports, optionz.PrivilegedPortThreshold, optionz.AllowPrivilegedPorts); err != nil {
+		return "", err
+	}
+
+	instance, scoped, err := m.tenantInstance(optionz.InstanceName, optionz.Labels, cnts)
+	if err != nil {
+		return "", err
+	}
+	// An idempotent start may find the instance already running, in which case it is only
+	// compared with the requested container once its configuration is known.
+	var existing *types.Container
+	if optionz.IdempotentStart {
+		if cnt, ok := runningInstance(instance, scoped); ok {
+			existing = &cnt
+			cnts = withoutContainer(cnts, cnt.ID)
+		}
+	}
+	if existing == nil {
+		if err := checkExistingInstanceAndPorts(instance, nil, scoped); err != nil {
+			return "", err
+		}
+	}
+	if err := checkExistingInstanceAndPorts("", ports, cnts); err != nil {
 		return "", err
 	}
 
-	mounts := make([]mount.Mount, 0, len(optionz.Volumes))
+	mounts := make([]mount.Mount, 0, len(optionz.Volumes)+len(optionz.BindMounts))
 	for _, vol := range optionz.Volumes {
 		mounts = append(mounts, mount.Mount{
 			Type:     "volume",
@@ -57,6 +146,33 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 		})
 	}
 
+	for _, bind := range optionz.BindMounts {
+		mnt, err := bindMount(bind)
+		if err != nil {
+			return "", err
+		}
+		mounts = append(mounts, mnt)
+	}
+	if err := m.checkDockerSocket(optionz.BindMounts, optionz.AcknowledgeDockerSocket); err != nil {
+		return "", err
+	}
+
+	if err := checkTmpfsTargets(optionz.Tmpfs, mounts); err != nil {
+		return "", err
+	}
+
+	var secretsDir string
+	if len(optionz.Secrets) > 0 {
+		if secretsDir, err = m.newSecretsDir(); err != nil {
+			return "", err
+		}
+		secrets, err := secretMounts(optionz.Secrets, secretsDir, mounts, optionz.Tmpfs)
+		if err != nil {
+			return "", err
+		}
+		mounts = append(mounts, secrets...)
+	}
+
 	devices := make([]container.DeviceMapping, 0, len(optionz.Devices))
 	for _, dev := range optionz.Devices {
 		devices = append(devices, container.DeviceMapping{
@@ -71,15 +187,193 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 		return "", fmt.Errorf("unable to parse cpu limit %f: %v", optionz.CPU, err)
 	}
 
+	if err := checkCPUScheduling(cpu, optionz.CPUShares, optionz.CPUQuota, optionz.CPUPeriod); err != nil {
+		return "", err
+	}
+
+	if m.checkCapacity {
+		if err := m.checkHostCapacity(ctx, cpu, optionz.HardMemory); err != nil {
+			return "", err
+		}
+	}
+
+	if err := m.checkRuntime(ctx, optionz.Runtime); err != nil {
+		return "", err
+	}
+
+	var pidsLimit *int64
+	switch {
+	case optionz.PidsLimit > 0:
+		pidsLimit = &optionz.PidsLimit
+	case optionz.PidsLimit < -1:
+		return "", status.Errorf(codes.InvalidArgument, "invalid pids limit %d", optionz.PidsLimit)
+	}
+
+	if err := checkCpuset(optionz.CpusetCpus); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid cpuset cpus %q: %v", optionz.CpusetCpus, err)
+	}
+	if err := checkCpuset(optionz.CpusetMems); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid cpuset mems %q: %v", optionz.CpusetMems, err)
+	}
+
+	deviceRequests, err := gpuRequests(optionz.GPUs, optionz.GPUCapabilities)
+	if err != nil {
+		return "", err
+	}
+
+	ulimits := make([]*container.Ulimit, 0, len(optionz.Ulimits))
+	for _, ulimit := range optionz.Ulimits {
+		// Unknown limit names are left for the runtime to validate.
+		if ulimit.Soft > ulimit.Hard {
+			return "", status.Errorf(codes.InvalidArgument,
+				"soft limit %d exceeds hard limit %d for ulimit %s", ulimit.Soft, ulimit.Hard, ulimit.Name)
+		}
+		ulimits = append(ulimits, &container.Ulimit{
+			Name: ulimit.Name,
+			Soft: ulimit.Soft,
+			Hard: ulimit.Hard,
+		})
+	}
+
+	// A zero weight leaves the block IO weight to the runtime default.
+	if optionz.BlkioWeight != 0 && (optionz.BlkioWeight < 10 || optionz.BlkioWeight > 1000) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid blkio weight %d: must be between 10 and 1000", optionz.BlkioWeight)
+	}
+
+	var readBps, writeBps, readIOps, writeIOps []*blkiodev.ThrottleDevice
+	for _, limit := range optionz.BlkioDeviceLimits {
+		if !path.IsAbs(limit.Path) {
+			return "", status.Errorf(codes.InvalidArgument, "blkio device path %q must be an absolute path", limit.Path)
+		}
+		readBps = appendThrottle(readBps, limit.Path, limit.ReadBps)
+		writeBps = appendThrottle(writeBps, limit.Path, limit.WriteBps)
+		readIOps = appendThrottle(readIOps, limit.Path, limit.ReadIOps)
+		writeIOps = appendThrottle(writeIOps, limit.Path, limit.WriteIOps)
+	}
+
+	// A zero size leaves the shm size to the runtime default.
+	if optionz.ShmSize < 0 {
+		return "", status.Errorf(codes.InvalidArgument, "shm size can not be negative, got %d", optionz.ShmSize)
+	}
+
+	if err := checkNamespaceMode("pid", optionz.PidMode, nil, cnts); err != nil {
+		return "", err
+	}
+
+	if err := checkNamespaceMode("ipc", optionz.IpcMode, ipcModes, cnts); err != nil {
+		return "", err
+	}
+
+	usernsMode, err := usernsMode(optionz.UsernsMode)
+	if err != nil {
+		return "", err
+	}
+
+	for _, group := range optionz.GroupAdd {
+		if group == "" {
+			return "", status.Errorf(codes.InvalidArgument, "supplementary group can not be empty")
+		}
+	}
+
+	var securityOpts []string
+	for _, opt := range optionz.SecurityOpts {
+		if err := checkSecurityOpt(opt); err != nil {
+			return "", err
+		}
+		opt, err := seccompProfile(opt)
+		if err != nil {
+			return "", err
+		}
+		securityOpts = append(securityOpts, opt)
+	}
+
+	for key, value := range optionz.Sysctls {
+		if key == "" || value == "" {
+			return "", status.Errorf(codes.InvalidArgument, "sysctl %q=%q must have a non-empty key and value", key, value)
+		}
+	}
+
+	if err := checkLogConfig(optionz.LogDriver, optionz.LogOptions); err != nil {
+		return "", err
+	}
+
+	if optionz.WorkingDir != "" && !path.IsAbs(optionz.WorkingDir) {
+		return "", status.Errorf(codes.InvalidArgument, "working directory %q must be an absolute path", optionz.WorkingDir)
+	}
+
+	if err := checkMemorySwap(optionz.MemorySwap, optionz.HardMemory); err != nil {
+		return "", err
+	}
+
+	if optionz.StopTimeout != nil && *optionz.StopTimeout < 0 {
+		return "", status.Errorf(codes.InvalidArgument, "stop timeout can not be negative, got %d", *optionz.StopTimeout)
+	}
+
+	if optionz.OOMScoreAdj < -1000 || optionz.OOMScoreAdj > 1000 {
+		return "", status.Errorf(codes.InvalidArgument, "invalid oom score adjustment %d: must be between -1000 and 1000", optionz.OOMScoreAdj)
+	}
+	var oomKillDisable *bool
+	if optionz.OOMKillDisable {
+		if optionz.HardMemory <= 0 {
+			return "", status.Error(codes.FailedPrecondition, "disabling the OOM killer requires a hard memory limit")
+		}
+		oomKillDisable = &optionz.OOMKillDisable
+	}
+
+	if optionz.WaitHealthy > 0 && optionz.HealthCheck != nil && optionz.HealthCheck.Test == "" {
+		return "", status.Error(codes.FailedPrecondition, "cannot wait for a container to become healthy with its health check disabled")
+	}
+
+	if optionz.Privileged && !m.allowPrivileged {
+		return "", status.Error(codes.PermissionDenied, "privileged containers are not allowed by this server")
+	}
+
+	if optionz.Hostname != "" && !hostnameLabelRE.MatchString(optionz.Hostname) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid hostname %q: must be at most 63 letters, digits or hyphens and not start or end with a hyphen", optionz.Hostname)
+	}
+	if err := checkDomainname(optionz.Domainname); err != nil {
+		return "", err
+	}
+
 	hostConfig := &container.HostConfig{
-		Mounts:      mounts,
-		NetworkMode: "host",
+		Mounts:         mounts,
+		NetworkMode:    "host",
+		ReadonlyRootfs: optionz.ReadOnlyRootFS,
+		Privileged:     optionz.Privileged,
+		Init:           optionz.Init,
+		OomScoreAdj:    optionz.OOMScoreAdj,
+		Tmpfs:          optionz.Tmpfs,
+		ShmSize:        optionz.ShmSize,
+		SecurityOpt:    securityOpts,
+		GroupAdd:       optionz.GroupAdd,
+		UsernsMode:     usernsMode,
+		PidMode:        container.PidMode(m.namespaceMode(optionz.PidMode, cnts)),
+		IpcMode:        container.IpcMode(m.namespaceMode(optionz.IpcMode, cnts)),
+		Sysctls:        optionz.Sysctls,
+		LogConfig:      container.LogConfig{Type: optionz.LogDriver, Config: optionz.LogOptions},
+		Runtime:        optionz.Runtime,
 
 		Resources: container.Resources{
 			NanoCPUs:          cpu,
+			CPUShares:         optionz.CPUShares,
+			CPUQuota:          optionz.CPUQuota,
+			CPUPeriod:         optionz.CPUPeriod,
 			Memory:            optionz.HardMemory, // hard
 			MemoryReservation: optionz.SoftMemory, // soft
+			MemorySwap:        optionz.MemorySwap,
 			Devices:           devices,
+			PidsLimit:         pidsLimit,
+			Ulimits:           ulimits,
+			CpusetCpus:        optionz.CpusetCpus,
+			CpusetMems:        optionz.CpusetMems,
+			DeviceRequests:    deviceRequests,
+			OomKillDisable:    oomKillDisable,
+
+			BlkioWeight:          optionz.BlkioWeight,
+			BlkioDeviceReadBps:   readBps,
+			BlkioDeviceWriteBps:  writeBps,
+			BlkioDeviceReadIOps:  readIOps,
+			BlkioDeviceWriteIOps: writeIOps,
 		},
 	}
 	splitCmd, err := shlex.Split(cmd)
@@ -92,28 +386,76 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 		splitCmd = nil
 	}
 
+	labels := make(map[string]string, len(optionz.Labels)+1)
+	for k, v := range optionz.Labels {
+		labels[k] = v
+	}
+	labels[managedByLabel] = managedByValue
+	// The secrets directory is removed along with the container, and the configuration digest
+	// vouches for an identical container, so only containerz may set them.
+	delete(labels, secretsLabel)
+	delete(labels, configLabel)
+	if secretsDir != "" {
+		labels[secretsLabel] = secretsDir
+	}
+
+	tty := true
+	if optionz.TTY != nil {
+		tty = *optionz.TTY
+	}
+
 	config := &container.Config{
 		Cmd:          splitCmd,
-		Labels:       optionz.Labels,
+		Labels:       labels,
 		Image:        ref,
-		AttachStdin:  false,
+		AttachStdin:  optionz.StdinOpen,
 		AttachStdout: false,
 		AttachStderr: false,
+		OpenStdin:    optionz.StdinOpen,
 		StdinOnce:    false,
-		Tty:          true,
+		Tty:          tty,
+		WorkingDir:   optionz.WorkingDir,
+		Hostname:     optionz.Hostname,
+		Domainname:   optionz.Domainname,
+		StopTimeout:  optionz.StopTimeout,
+	}
+	if optionz.Entrypoint != nil {
+		entrypoint, err := shlex.Split(*optionz.Entrypoint)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument,
+				"failed to split entrypoint %q, got error %s", *optionz.Entrypoint, err)
+		}
+		// An empty, non-nil entrypoint tells docker to clear the image entrypoint.
+		config.Entrypoint = append([]string{}, entrypoint...)
 	}
-	if len(optionz.PortMapping) > 0 {
+	if optionz.HealthCheck != nil {
+		healthCheck, err := healthConfig(optionz.HealthCheck)
+		if err != nil {
+			return "", err
+		}
+		config.Healthcheck = healthCheck
+	}
+
+	if len(ports) > 0 {
 		portMap := nat.PortMap{}
 		portSet := nat.PortSet{}
-		for in, out := range optionz.PortMapping {
-			internal := fmt.Sprintf("%d", in)
-			external := fmt.Sprintf("%d", out)
-			in, err := nat.NewPort("tcp", internal)
+		for _, port := range ports {
+			internal := fmt.Sprintf("%d", port.Internal)
+			external := fmt.Sprintf("%d", port.External)
+			in, err := nat.NewPort(port.Protocol, internal)
 			if err != nil {
 				return "", err
 			}
 
 			portSet[in] = struct{}{}
+			if port.HostIP != "" {
+				portMap[in] = append(portMap[in], nat.PortBinding{
+					HostIP:   port.HostIP,
+					HostPort: external,
+				})
+				continue
+			}
+
 			bindingV4 := nat.PortBinding{
 				HostIP:   "0.0.0.0", // TODO(alshabib): do we want this to be configurable?
 				HostPort: external,
@@ -123,24 +465,56 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 				HostPort: external,
 			}
 
-			portMap[in] = []nat.PortBinding{bindingV4, bindingV6}
+			portMap[in] = append(portMap[in], bindingV4, bindingV6)
 		}
 
 		hostConfig.PortBindings = portMap
 		config.ExposedPorts = portSet
 	}
 
-	if len(optionz.EnvMapping) > 0 {
-		for envName, envVal := range optionz.EnvMapping {
-			config.Env = append(config.Env, fmt.Sprintf("%s=%s", envName, envVal))
-		}
+	env, err := containerEnv(optionz.EnvFile, optionz.EnvMapping)
+	if err != nil {
+		return "", err
 	}
+	config.Env = env
 
 	// Handle Network
 	if optionz.Network != "" {
 		hostConfig.NetworkMode = container.NetworkMode(optionz.Network)
 	}
 
+	networkingConfig := &network.NetworkingConfig{}
+	if len(optionz.Networks) > 0 {
+		networks, err := dedupNetworks(optionz.Networks)
+		if err != nil {
+			return "", err
+		}
+		hostConfig.NetworkMode = container.NetworkMode(networks[0])
+		networkingConfig.EndpointsConfig = make(map[string]*network.EndpointSettings, len(networks))
+		for _, nw := range networks {
+			networkingConfig.EndpointsConfig[nw] = &network.EndpointSettings{}
+		}
+	}
+
+	if err := assignStaticIPs(networkingConfig, hostConfig.NetworkMode, optionz.StaticIPs); err != nil {
+		return "", err
+	}
+
+	// Handle DNS
+	for _, server := range optionz.DNS {
+		if net.ParseIP(server) == nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid dns server %q", server)
+		}
+	}
+	for _, host := range optionz.ExtraHosts {
+		if err := checkExtraHost(host); err != nil {
+			return "", err
+		}
+	}
+	hostConfig.DNS = optionz.DNS
+	hostConfig.DNSSearch = optionz.DNSSearch
+	hostConfig.ExtraHosts = optionz.ExtraHosts
+
 	// Handle Capabilities
 	if optionz.Capabilities != nil {
 		caps := optionz.Capabilities.(*cpb.StartContainerRequest_Capabilities)
@@ -150,26 +524,11 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 
 	// Handle RestartPolicy
 	if optionz.RestartPolicy != nil {
-		restartPolicy := optionz.RestartPolicy.(*cpb.StartContainerRequest_Restart)
-
-		var policy container.RestartPolicyMode
-		switch restartPolicy.GetPolicy() {
-		case cpb.StartContainerRequest_Restart_ALWAYS:
-			policy = container.RestartPolicyAlways
-		case cpb.StartContainerRequest_Restart_ON_FAILURE:
-			policy = container.RestartPolicyOnFailure
-		case cpb.StartContainerRequest_Restart_NONE:
-			policy = container.RestartPolicyDisabled
-		case cpb.StartContainerRequest_Restart_UNLESS_STOPPED:
-			policy = container.RestartPolicyUnlessStopped
-		default:
-			return "", status.Errorf(codes.FailedPrecondition, "unkown restart policy '%v'", restartPolicy.GetPolicy())
-		}
-
-		hostConfig.RestartPolicy = container.RestartPolicy{
-			Name:              policy,
-			MaximumRetryCount: int(restartPolicy.GetAttempts()),
+		policy, err := restartPolicy(optionz.RestartPolicy.(*cpb.StartContainerRequest_Restart))
+		if err != nil {
+			return "", err
 		}
+		hostConfig.RestartPolicy = policy
 	}
 
 	// Handle RunAs
@@ -185,24 +544,310 @@ func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string
 		config.User = user
 	}
 
-	resp, err := m.client.ContainerCreate(ctx, config, hostConfig, &network.NetworkingConfig{}, nil, optionz.InstanceName)
+	if err := m.checkAPIVersion(config, hostConfig); err != nil {
+		return "", err
+	}
+
+	if optionz.IdempotentStart {
+		digest, err := configDigest(summary.ID, config, hostConfig, networkingConfig, platform, secretsDir)
+		if err != nil {
+			return "", err
+		}
+		config.Labels[configLabel] = digest
+		if existing != nil {
+			if err := m.checkIdentical(instance, *existing, summary.ID, digest, optionz.Secrets); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	if optionz.DryRun != nil {
+		return instance, fillPlan(optionz.DryRun, config, hostConfig, networkingConfig)
+	}
+
+	if existing != nil {
+		return instance, m.startedContainer(ctx, existing.ID, instance, optionz.Addresses, optionz.WaitHealthy)
+	}
+
+	if secretsDir != "" {
+		if err := m.writeSecrets(secretsDir, optionz.Secrets); err != nil {
+			return "", err
+		}
+	}
+
+	resp, err := m.createContainer(ctx, config, hostConfig, networkingConfig, platform, instance)
 	if err != nil {
-		return "", status.Errorf(codes.Internal, "unable to create container: %v", err)
+		m.removeSecrets(secretsDir)
+		// Runtimes adapting the docker API report the settings they do not support as such.
+		if status.Code(err) == codes.Unimplemented {
+			return "", err
+		}
+		return "", contextError(ctx, status.Errorf(codes.Internal, "unable to create container: %v", err))
 	}
 
-	if err := m.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		return "", status.Errorf(codes.Internal, "unable to start container: %v", err)
+	// A container that was created but never started would hold on to its instance name, so
+	// remove it if the start is cancelled or fails.
+	err = ctx.Err()
+	if err == nil {
+		err = m.client.ContainerStart(ctx, resp.ID, container.StartOptions{})
+	}
+	if err != nil {
+		m.removeCreated(ctx, resp.ID)
+		m.removeSecrets(secretsDir)
+		return "", contextError(ctx, status.Errorf(codes.Internal, "unable to start container: %v", err))
 	}
 
 	name := resp.ID
-	if optionz.InstanceName != "" {
-		name = optionz.InstanceName
+	if instance != "" {
+		name = instance
+	}
+	return name, m.startedContainer(ctx, resp.ID, name, optionz.Addresses, optionz.WaitHealthy)
+}
+
+// startedContainer stores the addresses of the started container with the provided ID in addrs,
+// if set, and waits up to waitHealthy for it to become healthy, if positive.
+func (m *Manager) startedContainer(ctx context.Context, id, name string, addrs map[string][]string, waitHealthy time.Duration) error {
+	if addrs != nil {
+		if err := m.assignedAddresses(ctx, id, name, addrs); err != nil {
+			return err
+		}
+	}
+
+	if waitHealthy > 0 {
+		if err := m.waitHealthy(ctx, id, name, waitHealthy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restartPolicy converts a gNOI restart policy to its docker counterpart.
+func restartPolicy(restart *cpb.StartContainerRequest_Restart) (container.RestartPolicy, error) {
+	var policy container.RestartPolicyMode
+	switch restart.GetPolicy() {
+	case cpb.StartContainerRequest_Restart_ALWAYS:
+		policy = container.RestartPolicyAlways
+	case cpb.StartContainerRequest_Restart_ON_FAILURE:
+		policy = container.RestartPolicyOnFailure
+	case cpb.StartContainerRequest_Restart_NONE:
+		policy = container.RestartPolicyDisabled
+	case cpb.StartContainerRequest_Restart_UNLESS_STOPPED:
+		policy = container.RestartPolicyUnlessStopped
+	default:
+		return container.RestartPolicy{}, status.Errorf(codes.FailedPrecondition, "unkown restart policy '%v'", restart.GetPolicy())
+	}
+
+	// Docker only honours a retry count for the on-failure policy, and rejects it otherwise.
+	if restart.GetAttempts() != 0 && policy != container.RestartPolicyOnFailure {
+		return container.RestartPolicy{}, status.Errorf(codes.InvalidArgument, "restart attempts can only be set with the on-failure restart policy, got policy %q", policy)
 	}
 
-	return name, nil
+	return container.RestartPolicy{
+		Name:              policy,
+		MaximumRetryCount: int(restart.GetAttempts()),
+	}, nil
 }
 
-func checkExistingInstanceAndPorts(instance string, ports map[uint32]uint32, cnts []types.Container) error {
+// checkHostCapacity ensures that a container limited to nanoCPUs and memory bytes fits on the
+// host. Limits that are unset, and capacities the runtime does not report, are not checked.
+func (m *Manager) checkHostCapacity(ctx context.Context, nanoCPUs, memory int64) error {
+	if nanoCPUs <= 0 && memory <= 0 {
+		return nil
+	}
+	info, err := m.client.Info(ctx)
+	if err != nil {
+		return contextError(ctx, status.Errorf(codes.Unavailable, "unable to query host capacity: %v", err))
+	}
+	if hostCPUs := int64(info.NCPU) * 1e9; hostCPUs > 0 && nanoCPUs > hostCPUs {
+		return status.Errorf(codes.ResourceExhausted, "requested %g cpus exceeds the %d cpus of the host", float64(nanoCPUs)/1e9, info.NCPU)
+	}
+	if info.MemTotal > 0 && memory > info.MemTotal {
+		return status.Errorf(codes.ResourceExhausted, "requested memory limit of %d bytes exceeds the %d bytes of the host", memory, info.MemTotal)
+	}
+	return nil
+}
+
+// assignedAddresses stores the IP addresses of each network of the started container identified
+// by id in addrs.
+func (m *Manager) assignedAddresses(ctx context.Context, id, name string, addrs map[string][]string) error {
+	info, err := m.inspectContainer(ctx, id)
+	if err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "container %s started but its addresses could not be inspected: %v", name, err))
+	}
+	if info.NetworkSettings == nil {
+		return nil
+	}
+	for nw, settings := range info.NetworkSettings.Networks {
+		if settings == nil {
+			continue
+		}
+		var ips []string
+		for _, ip := range []string{settings.IPAddress, settings.GlobalIPv6Address} {
+			if ip != "" {
+				ips = append(ips, ip)
+			}
+		}
+		if len(ips) > 0 {
+			addrs[nw] = ips
+		}
+	}
+	return nil
+}
+
+// fillPlan stores the configuration a container would be created with in plan.
+func fillPlan(plan *structpb.Struct, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig) error {
+	b, err := json.Marshal(struct {
+		Config           *container.Config
+		HostConfig       *container.HostConfig
+		NetworkingConfig *network.NetworkingConfig
+	}{config, hostConfig, networkingConfig})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to encode container configuration: %v", err)
+	}
+	if err := plan.UnmarshalJSON(b); err != nil {
+		return status.Errorf(codes.Internal, "unable to encode container configuration: %v", err)
+	}
+	return nil
+}
+
+// removeCreated removes a container that was created but could not be started. The removal is
+// attempted even if ctx was cancelled, within cleanupTimeout.
+func (m *Manager) removeCreated(ctx context.Context, id string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+
+	if err := m.client.ContainerRemove(ctx, id, container.RemoveOptions{Force: true}); err != nil {
+		klog.Warningf("unable to remove container %s after a failed start: %v", id, err)
+	}
+}
+
+// portMappings returns the tcp ports of the port mapping, ordered by container port, followed by
+// the protocol specific ports. Protocols are normalized to lower case and default to tcp.
+func portMappings(tcp map[uint32]uint32, ports []options.Port) ([]options.Port, error) {
+	mappings := make([]options.Port, 0, len(tcp)+len(ports))
+	for _, in := range slices.Sorted(maps.Keys(tcp)) {
+		mappings = append(mappings, options.Port{Internal: in, External: tcp[in], Protocol: "tcp"})
+	}
+
+	for _, port := range ports {
+		proto := strings.ToLower(port.Protocol)
+		switch proto {
+		case "":
+			proto = "tcp"
+		case "tcp", "udp", "sctp":
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported protocol %q for port %d: expected tcp, udp or sctp", port.Protocol, port.Internal)
+		}
+		if port.HostIP != "" && net.ParseIP(port.HostIP) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid host IP %q for port %d", port.HostIP, port.Internal)
+		}
+		mappings = append(mappings, options.Port{Internal: port.Internal, External: port.External, Protocol: proto, HostIP: port.HostIP})
+	}
+	return mappings, nil
+}
+
+// portName formats a port for error messages, qualifying it with its protocol unless it is tcp.
+func portName(port uint32, proto string) string {
+	if proto == "" || proto == "tcp" {
+		return strconv.FormatUint(uint64(port), 10)
+	}
+	return fmt.Sprintf("%d/%s", port, proto)
+}
+
+// checkPorts ensures that every container and host port is a valid port number, that no two host
+// ports of the request conflict, as a port published on a wildcard address conflicts with the
+// same port on any address, and that no host port is below the privileged port threshold, unless
+// privileged ports are allowed. The same port number may be used by different protocols.
+func checkPorts(ports []options.Port, threshold uint32, allowPrivileged bool) error {
+	published := make([]hostBinding, 0, len(ports))
+	for _, port := range ports {
+		switch {
+		case port.Internal == 0 || port.Internal > maxPort:
+			return status.Errorf(codes.InvalidArgument, "invalid container port %d: must be between 1 and %d", port.Internal, maxPort)
+		case port.External == 0 || port.External > maxPort:
+			return status.Errorf(codes.InvalidArgument, "invalid host port %d: must be between 1 and %d", port.External, maxPort)
+		case port.External < threshold && !allowPrivileged:
+			return status.Errorf(codes.PermissionDenied, "host port %d is privileged: ports below %d are not allowed", port.External, threshold)
+		}
+
+		binding := newHostBinding(port.HostIP, port.External, port.Protocol)
+		if slices.ContainsFunc(published, binding.conflicts) {
+			name := portName(port.External, port.Protocol)
+			if port.HostIP != "" {
+				name = net.JoinHostPort(port.HostIP, name)
+			}
+			return status.Errorf(codes.InvalidArgument, "host port %s is published more than once", name)
+		}
+		published = append(published, binding)
+	}
+	return nil
+}
+
+// hostBinding is a host address, port and protocol a container port is published on.
+type hostBinding struct {
+	ip       string
+	port     uint32
+	protocol string
+}
+
+// newHostBinding returns the host binding of the given address, port and protocol, using tcp if
+// no protocol is set.
+func newHostBinding(ip string, port uint32, protocol string) hostBinding {
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	return hostBinding{ip: ip, port: port, protocol: protocol}
+}
+
+// isWildcard reports whether the address binds a port on every address of the host. An unset
+// address is published on both the IPv4 and IPv6 wildcard addresses.
+func isWildcard(ip string) bool {
+	if ip == "" {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	return addr != nil && addr.IsUnspecified()
+}
+
+// conflicts reports whether b and other can not both be bound. Bindings of the same port and
+// protocol conflict if they share an address, or if either is bound to a wildcard address, as
+// that covers every specific address of the host.
+func (b hostBinding) conflicts(other hostBinding) bool {
+	if b.port != other.port || b.protocol != other.protocol {
+		return false
+	}
+	if isWildcard(b.ip) || isWildcard(other.ip) {
+		return true
+	}
+	if a, o := net.ParseIP(b.ip), net.ParseIP(other.ip); a != nil && o != nil {
+		return a.Equal(o)
+	}
+	return b.ip == other.ip
+}
+
+// conflictingPort returns the port published by cnts that one of ports can not be bound
+// alongside, if any. Ports a container exposes without publishing them on the host are ignored.
+func conflictingPort(ports []options.Port, cnts []types.Container) (types.Port, bool) {
+	if len(ports) == 0 {
+		return types.Port{}, false
+	}
+	for _, cnt := range cnts {
+		for _, port := range cnt.Ports {
+			if port.PublicPort == 0 {
+				continue
+			}
+			published := newHostBinding(port.IP, uint32(port.PublicPort), port.Type)
+			for _, p := range ports {
+				if newHostBinding(p.HostIP, p.External, p.Protocol).conflicts(published) {
+					return port, true
+				}
+			}
+		}
+	}
+	return types.Port{}, false
+}
+
+func checkExistingInstanceAndPorts(instance string, ports []options.Port, cnts []types.Container) error {
 	if instance == "" && len(ports) == 0 {
 		return nil
 	}
@@ -211,20 +856,502 @@ func checkExistingInstanceAndPorts(instance string, ports map[uint32]uint32, cnt
 		for _, name := range cnt.Names {
 			strippedname := strings.Replace(name, "/", "", 1)
 			if strippedname == instance {
-				return status.Errorf(codes.AlreadyExists, "instance name %s already in use", instance)
+				return detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": instance},
+					"instance name %s already in use", instance)
 			}
 		}
-		for _, port := range cnt.Ports {
-			for _, ext := range ports {
-				if ext == uint32(port.PublicPort) {
-					return status.Errorf(codes.Unavailable, "port %d already in use", ext)
+	}
+	if port, ok := conflictingPort(ports, cnts); ok {
+		return portInUseError(port)
+	}
+	return nil
+}
+
+// portInUseError returns the error reported when a requested host port is published by another
+// container. The address the port is published on is only reported if it is a specific one.
+func portInUseError(port types.Port) error {
+	proto := port.Type
+	if proto == "" {
+		proto = "tcp"
+	}
+	metadata := map[string]string{
+		"port":     strconv.Itoa(int(port.PublicPort)),
+		"protocol": proto,
+	}
+	if isWildcard(port.IP) {
+		return detailedError(codes.Unavailable, reasonPortInUse, metadata, "port %s already in use", portName(uint32(port.PublicPort), port.Type))
+	}
+	metadata["host_ip"] = port.IP
+	return detailedError(codes.Unavailable, reasonPortInUse, metadata, "port %s already in use on %s", portName(uint32(port.PublicPort), port.Type), port.IP)
+}
+
+// bindPropagations is the set of bind propagation modes understood by docker.
+var bindPropagations = map[mount.Propagation]bool{
+	mount.PropagationPrivate:  true,
+	mount.PropagationRPrivate: true,
+	mount.PropagationShared:   true,
+	mount.PropagationRShared:  true,
+	mount.PropagationSlave:    true,
+	mount.PropagationRSlave:   true,
+}
+
+// mountConsistencies is the set of mount consistency requirements understood by docker.
+var mountConsistencies = map[mount.Consistency]bool{
+	mount.ConsistencyFull:      true,
+	mount.ConsistencyCached:    true,
+	mount.ConsistencyDelegated: true,
+	mount.ConsistencyDefault:   true,
+}
+
+// bindMount validates a bind mount and converts it to its docker representation. Both the host
+// source and the container target must be absolute paths.
+func bindMount(bind options.BindMount) (mount.Mount, error) {
+	if !path.IsAbs(bind.Source) {
+		return mount.Mount{}, status.Errorf(codes.InvalidArgument, "bind mount source %q must be an absolute path", bind.Source)
+	}
+	if !path.IsAbs(bind.Target) {
+		return mount.Mount{}, status.Errorf(codes.InvalidArgument, "bind mount target %q must be an absolute path", bind.Target)
+	}
+
+	m := mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   bind.Source,
+		Target:   bind.Target,
+		ReadOnly: bind.ReadOnly,
+	}
+	if bind.Propagation != "" {
+		propagation := mount.Propagation(bind.Propagation)
+		if !bindPropagations[propagation] {
+			return mount.Mount{}, status.Errorf(codes.InvalidArgument, "unknown bind propagation %q", bind.Propagation)
+		}
+		m.BindOptions = &mount.BindOptions{Propagation: propagation}
+	}
+	if bind.Consistency != "" {
+		consistency := mount.Consistency(bind.Consistency)
+		if !mountConsistencies[consistency] {
+			return mount.Mount{}, status.Errorf(codes.InvalidArgument, "unknown mount consistency %q", bind.Consistency)
+		}
+		m.Consistency = consistency
+	}
+	return m, nil
+}
+
+// checkTmpfsTargets ensures that every tmpfs mount has an absolute target and that it does not
+// collide with the target of another mount. Targets are compared once cleaned, so that /data/ and
+// /data collide.
+func checkTmpfsTargets(tmpfs map[string]string, mounts []mount.Mount) error {
+	seen := make(map[string]string, len(tmpfs))
+	for _, target := range slices.Sorted(maps.Keys(tmpfs)) {
+		if target == "" {
+			return status.Errorf(codes.InvalidArgument, "tmpfs mount target can not be empty")
+		}
+		if !path.IsAbs(target) {
+			return status.Errorf(codes.InvalidArgument, "tmpfs mount target %q must be an absolute path", target)
+		}
+		cleaned := path.Clean(target)
+		if other, ok := seen[cleaned]; ok {
+			return status.Errorf(codes.AlreadyExists, "tmpfs mount target %s is already used by tmpfs mount %s", target, other)
+		}
+		seen[cleaned] = target
+		for _, m := range mounts {
+			if path.Clean(m.Target) == cleaned {
+				return status.Errorf(codes.AlreadyExists, "tmpfs mount target %s is already used by volume %s", target, m.Source)
+			}
+		}
+	}
+	return nil
+}
+
+// ipcModes is the set of IPC namespace modes, besides host and joining a container, understood by
+// docker.
+var ipcModes = map[string]bool{
+	"private":   true,
+	"shareable": true,
+	"none":      true,
+}
+
+// checkNamespaceMode ensures that the mode of the namespace kind is empty, "host", one of the
+// extra modes or "container:<name>", where name is a running container in cnts.
+func checkNamespaceMode(kind, mode string, extra map[string]bool, cnts []types.Container) error {
+	if mode == "" || mode == "host" || extra[mode] {
+		return nil
+	}
+	target, ok := strings.CutPrefix(mode, "container:")
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown %s namespace mode %q", kind, mode)
+	}
+	if target == "" {
+		return status.Errorf(codes.InvalidArgument, "%s namespace mode %q does not name a container", kind, mode)
+	}
+	for _, cnt := range cnts {
+		if cnt.ID == target || slices.Contains(cnt.Names, "/"+target) {
+			return nil
+		}
+	}
+	return status.Errorf(codes.NotFound, "container %s sharing its %s namespace is not running", target, kind)
+}
+
+// usernsMode converts a user namespace mode to its docker representation. Docker has no name for
+// the private namespace of a runtime remapping users: it is the default.
+func usernsMode(mode string) (container.UsernsMode, error) {
+	switch mode {
+	case "", "private":
+		return "", nil
+	case "host":
+		return "host", nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown user namespace mode %q, must be host or private", mode)
+	}
+}
+
+// securityOptKeys is the set of security option keys understood by docker.
+var securityOptKeys = map[string]bool{
+	"apparmor":          true,
+	"label":             true,
+	"no-new-privileges": true,
+	"seccomp":           true,
+	"systempaths":       true,
+	"writable-cgroups":  true,
+}
+
+// checkSecurityOpt ensures that the security option uses a known key. Options are either a bare
+// key (e.g. "no-new-privileges") or of the form key=value (or the legacy key:value).
+func checkSecurityOpt(opt string) error {
+	key, _, _ := strings.Cut(opt, "=")
+	if !strings.Contains(opt, "=") {
+		key, _, _ = strings.Cut(opt, ":")
+	}
+	if !securityOptKeys[key] {
+		return status.Errorf(codes.InvalidArgument, "unknown security option %q", opt)
+	}
+	return nil
+}
+
+// seccompProfile returns opt with the seccomp profile it names by path replaced by the content of
+// the profile, as the daemon expects the profile itself rather than a path. Other options, and the
+// unconfined, builtin and inline JSON profiles, are returned as is.
+func seccompProfile(opt string) (string, error) {
+	sep := "="
+	if !strings.Contains(opt, sep) {
+		sep = ":"
+	}
+	key, file, ok := strings.Cut(opt, sep)
+	if !ok || key != "seccomp" || file == "unconfined" || file == "builtin" || strings.HasPrefix(strings.TrimSpace(file), "{") {
+		return opt, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", status.Errorf(codes.NotFound, "seccomp profile %s not found", file)
+		}
+		return "", status.Errorf(codes.InvalidArgument, "unable to read seccomp profile %s: %v", file, err)
+	}
+	profile := &bytes.Buffer{}
+	if err := json.Compact(profile, b); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "seccomp profile %s is not valid JSON: %v", file, err)
+	}
+	return "seccomp=" + profile.String(), nil
+}
+
+// checkCpuset validates a cpuset string, i.e. a comma-separated list of numbers or ranges of
+// numbers (e.g. "0-2,4"). An empty cpuset is valid and means no restriction.
+func checkCpuset(cpuset string) error {
+	if cpuset == "" {
+		return nil
+	}
+
+	for _, part := range strings.Split(cpuset, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		lo, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("malformed element %q", part)
+		}
+		if len(bounds) == 2 {
+			hi, err := strconv.ParseUint(bounds[1], 10, 32)
+			if err != nil {
+				return fmt.Errorf("malformed element %q", part)
+			}
+			if hi < lo {
+				return fmt.Errorf("range %q is inverted", part)
+			}
+		}
+	}
+	return nil
+}
+
+// gpuRequests builds the device requests needed to attach GPUs to a container. The count is
+// either a positive number or "all".
+func gpuRequests(count string, capabilities []string) ([]container.DeviceRequest, error) {
+	if count == "" {
+		return nil, nil
+	}
+
+	n := -1
+	if count != "all" {
+		var err error
+		n, err = strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "gpu count must be a positive number or \"all\", got %q", count)
+		}
+	}
+
+	caps := []string{"gpu"}
+	for _, c := range capabilities {
+		if c != "gpu" {
+			caps = append(caps, c)
+		}
+	}
+
+	return []container.DeviceRequest{
+		{
+			Driver:       "nvidia",
+			Count:        n,
+			Capabilities: [][]string{caps},
+		},
+	}, nil
+}
+
+// dedupNetworks removes duplicate networks while preserving the order in which they were
+// provided. Empty network names are rejected.
+func dedupNetworks(networks []string) ([]string, error) {
+	seen := make(map[string]bool, len(networks))
+	deduped := make([]string, 0, len(networks))
+	for _, nw := range networks {
+		if nw == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "network name can not be empty")
+		}
+		if seen[nw] {
+			continue
+		}
+		seen[nw] = true
+		deduped = append(deduped, nw)
+	}
+	return deduped, nil
+}
+
+// appendThrottle appends a throttle of the device at path to the provided rate, unless the rate is
+// zero and the device is unlimited.
+func appendThrottle(throttles []*blkiodev.ThrottleDevice, path string, rate uint64) []*blkiodev.ThrottleDevice {
+	if rate == 0 {
+		return throttles
+	}
+	return append(throttles, &blkiodev.ThrottleDevice{Path: path, Rate: rate})
+}
+
+// checkCPUScheduling ensures that the CPU shares, quota and period are within the ranges
+// accepted by docker and that the CFS quota and period are not combined with a CPU limit, which
+// docker derives them from.
+func checkCPUScheduling(nanoCPUs, shares, quota, period int64) error {
+	switch {
+	case shares < 0:
+		return status.Errorf(codes.InvalidArgument, "invalid cpu shares %d: must not be negative", shares)
+	case quota > 0 && quota < 1000:
+		return status.Errorf(codes.InvalidArgument, "invalid cpu quota %d: must be at least 1000 microseconds", quota)
+	case period != 0 && (period < 1000 || period > 1000000):
+		return status.Errorf(codes.InvalidArgument, "invalid cpu period %d: must be between 1000 and 1000000 microseconds", period)
+	case nanoCPUs != 0 && (quota != 0 || period != 0):
+		return status.Error(codes.InvalidArgument, "a cpu limit cannot be combined with a cpu quota or period")
+	}
+	return nil
+}
+
+// checkMemorySwap ensures that a swap limit is either unlimited (-1) or no lower than the hard
+// memory limit, which must then be set.
+func checkMemorySwap(swap, memory int64) error {
+	switch {
+	case swap == 0 || swap == -1:
+		return nil
+	case swap < -1:
+		return status.Errorf(codes.InvalidArgument, "invalid memory swap limit %d: must be -1 or at least the hard memory limit", swap)
+	case memory <= 0:
+		return status.Errorf(codes.InvalidArgument, "memory swap limit %d requires a hard memory limit", swap)
+	case swap < memory:
+		return status.Errorf(codes.InvalidArgument, "memory swap limit %d is lower than the hard memory limit %d", swap, memory)
+	}
+	return nil
+}
+
+// hostnameLabelRE matches an RFC 1123 label.
+var hostnameLabelRE = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// checkLogConfig ensures that the logging driver is supported and, for the json-file driver, that
+// the rotation options are valid.
+func checkLogConfig(driver string, opts map[string]string) error {
+	switch driver {
+	case "":
+		if len(opts) > 0 {
+			return status.Error(codes.InvalidArgument, "log options require a log driver")
+		}
+	case "journald":
+	case "none":
+		if len(opts) > 0 {
+			return status.Error(codes.InvalidArgument, "the none log driver takes no options")
+		}
+	case "json-file":
+		if size, ok := opts["max-size"]; ok {
+			if n, err := units.RAMInBytes(size); err != nil || n <= 0 {
+				return status.Errorf(codes.InvalidArgument, "invalid log max-size %q: must be a positive size such as 10m", size)
+			}
+		}
+		if files, ok := opts["max-file"]; ok {
+			if n, err := strconv.Atoi(files); err != nil || n <= 0 {
+				return status.Errorf(codes.InvalidArgument, "invalid log max-file %q: must be a positive integer", files)
+			}
+		}
+	default:
+		return status.Errorf(codes.InvalidArgument, "unsupported log driver %q: must be json-file, journald or none", driver)
+	}
+	return nil
+}
+
+// checkDomainname ensures that every label of the domain name is an RFC 1123 label and that the
+// name is no longer than 253 characters.
+func checkDomainname(domainname string) error {
+	if domainname == "" {
+		return nil
+	}
+	if len(domainname) > 253 {
+		return status.Errorf(codes.InvalidArgument, "invalid domain name %q: must be at most 253 characters", domainname)
+	}
+	for _, label := range strings.Split(domainname, ".") {
+		if !hostnameLabelRE.MatchString(label) {
+			return status.Errorf(codes.InvalidArgument, "invalid domain name %q: label %q must be at most 63 letters, digits or hyphens and not start or end with a hyphen", domainname, label)
+		}
+	}
+	return nil
+}
+
+// digestRE matches a sha256 image digest.
+var digestRE = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// startImage returns the image identified by the normalized reference ref, first pulling it if
+// policy requires. A failed pull is returned even if the image is present, rather than starting
+// from a stale image.
+func (m *Manager) startImage(ctx context.Context, ref string, policy options.PullPolicy, creds *tpb.Credentials, platform *options.Platform) (image.Summary, error) {
+	switch policy {
+	case "", options.PullNever:
+		return m.lookupImage(ctx, ref)
+	case options.PullIfNotPresent:
+		summary, err := m.lookupImage(ctx, ref)
+		if status.Code(err) != codes.NotFound {
+			return summary, err
+		}
+	case options.PullAlways:
+	default:
+		return image.Summary{}, status.Errorf(codes.InvalidArgument, "unknown image pull policy %q", policy)
+	}
+
+	pullOpts := []options.Option{options.WithRegistryAuth(creds)}
+	if platform != nil {
+		pullOpts = append(pullOpts, options.WithPlatform(platform.OS, platform.Architecture, platform.Variant))
+	}
+	if err := m.ImagePull(ctx, ref, "", pullOpts...); err != nil {
+		return image.Summary{}, err
+	}
+	return m.lookupImage(ctx, ref)
+}
+
+// checkDigest ensures that summary, the image resolved for ref, has the expected repo digest.
+// Images without any repo digest, such as locally built ones, cannot be verified and are rejected.
+func checkDigest(ref, expected string, summary image.Summary) error {
+	if !digestRE.MatchString(expected) {
+		return status.Errorf(codes.InvalidArgument, "invalid digest %q: expected sha256:<hex>", expected)
+	}
+	if len(summary.RepoDigests) == 0 {
+		return status.Errorf(codes.FailedPrecondition, "image %s has no repo digest to verify against %s", ref, expected)
+	}
+
+	digests := make([]string, 0, len(summary.RepoDigests))
+	for _, repoDigest := range summary.RepoDigests {
+		_, digest, _ := strings.Cut(repoDigest, "@")
+		if digest == expected {
+			return nil
+		}
+		digests = append(digests, digest)
+	}
+	return status.Errorf(codes.FailedPrecondition, "image %s has digest %s, expected %s", ref, strings.Join(digests, ","), expected)
+}
+
+// assignStaticIPs sets the static addresses requested for each network on the networking config.
+// Static addresses are only supported on named networks the container is attached to.
+func assignStaticIPs(cfg *network.NetworkingConfig, mode container.NetworkMode, ips map[string][]string) error {
+	for nw, addrs := range ips {
+		if nw == "" || container.NetworkMode(nw).IsHost() || container.NetworkMode(nw).IsNone() {
+			return status.Errorf(codes.InvalidArgument, "static addresses require a named network, got %q", nw)
+		}
+		if cfg.EndpointsConfig == nil && string(mode) == nw {
+			cfg.EndpointsConfig = map[string]*network.EndpointSettings{nw: {}}
+		}
+		endpoint, ok := cfg.EndpointsConfig[nw]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "static addresses provided for network %s which the container is not attached to", nw)
+		}
+
+		ipam := &network.EndpointIPAMConfig{}
+		for _, addr := range addrs {
+			ip := net.ParseIP(addr)
+			switch {
+			case ip == nil:
+				return status.Errorf(codes.InvalidArgument, "invalid address %q for network %s", addr, nw)
+			case ip.To4() != nil:
+				if ipam.IPv4Address != "" {
+					return status.Errorf(codes.InvalidArgument, "multiple IPv4 addresses provided for network %s", nw)
 				}
+				ipam.IPv4Address = addr
+			default:
+				if ipam.IPv6Address != "" {
+					return status.Errorf(codes.InvalidArgument, "multiple IPv6 addresses provided for network %s", nw)
+				}
+				ipam.IPv6Address = addr
 			}
 		}
+		endpoint.IPAMConfig = ipam
 	}
 	return nil
 }
 
+// checkExtraHost ensures that an extra host entry is of the form hostname:ip. The address may
+// be an IPv6 address or the special "host-gateway" value understood by docker.
+func checkExtraHost(entry string) error {
+	host, ip, ok := strings.Cut(entry, ":")
+	if !ok || host == "" {
+		return status.Errorf(codes.InvalidArgument, "extra host %q must be of the form hostname:ip", entry)
+	}
+	if ip != "host-gateway" && net.ParseIP(ip) == nil {
+		return status.Errorf(codes.InvalidArgument, "extra host %q has an invalid address", entry)
+	}
+	return nil
+}
+
+// healthConfig converts the health check option into a docker health config. An empty test
+// command disables the health check.
+func healthConfig(hc *options.HealthCheck) (*container.HealthConfig, error) {
+	if hc.Test == "" {
+		return &container.HealthConfig{Test: []string{"NONE"}}, nil
+	}
+	if hc.Interval <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "health check interval must be positive, got %s", hc.Interval)
+	}
+	if hc.Timeout <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "health check timeout must be positive, got %s", hc.Timeout)
+	}
+
+	test, err := shlex.Split(hc.Test)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to split health check command %q, got error %s", hc.Test, err)
+	}
+
+	return &container.HealthConfig{
+		Test:        append([]string{"CMD"}, test...),
+		Interval:    hc.Interval,
+		Timeout:     hc.Timeout,
+		Retries:     hc.Retries,
+		StartPeriod: hc.StartPeriod,
+	}, nil
+}
+
 // cgroupPermissions returns the cgroup permissions for the device in the order of rwm.
 func cgroupPermissions(perms []cpb.Device_Permission) string {
 	permMap := map[cpb.Device_Permission]bool{}
@@ -2,19 +2,31 @@ package docker
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	options "github.com/openconfig/containerz/containers"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	cpb "github.com/openconfig/gnoi/containerz"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
@@ -24,6 +36,8 @@ type fakeStartingDocker struct {
 	fakeDocker
 	summaries []image.Summary
 	cnts      []types.Container
+	rawLabels map[string]string
+	bindings  nat.PortMap
 
 	Ports       nat.PortSet
 	Env         []string
@@ -38,12 +52,61 @@ type fakeStartingDocker struct {
 	Devices     []container.DeviceMapping
 	Cmd         []string
 
+	ReadOnlyRootFS bool
+	Privileged     bool
+	Tmpfs          map[string]string
+	Healthcheck    *container.HealthConfig
+	PidsLimit      *int64
+	Ulimits        []*container.Ulimit
+	BlkioWeight    uint16
+	BlkioReadBps   []*blkiodev.ThrottleDevice
+	BlkioWriteBps  []*blkiodev.ThrottleDevice
+	BlkioReadIOps  []*blkiodev.ThrottleDevice
+	BlkioWriteIOps []*blkiodev.ThrottleDevice
+	CpusetCpus     string
+	CpusetMems     string
+	DeviceRequests []container.DeviceRequest
+	Endpoints      map[string]*network.EndpointSettings
+	DNS            []string
+	DNSSearch      []string
+	ExtraHosts     []string
+	ShmSize        int64
+	SecurityOpt    []string
+	GroupAdd       []string
+	UsernsMode     container.UsernsMode
+	Platform       *ocispec.Platform
+	PidMode        container.PidMode
+	IpcMode        container.IpcMode
+	Sysctls        map[string]string
+	LogConfig      container.LogConfig
+	WorkingDir     string
+	Hostname       string
+	Domainname     string
+	Entrypoint     []string
+	// ClearEntrypoint records that an empty, non-nil entrypoint was requested.
+	ClearEntrypoint bool
+	// NoTTY records that the container was created without a TTY.
+	NoTTY       bool
+	OpenStdin   bool
+	AttachStdin bool
+	Init        *bool
+	StopTimeout *int
+	Runtime     string
+
 	CPU        int64
+	CPUShares  int64
+	CPUQuota   int64
+	CPUPeriod  int64
 	HardMemory int64
 	SoftMemory int64
+	MemorySwap int64
+
+	OOMScoreAdj    int
+	OOMKillDisable *bool
 }
 
 func (f *fakeStartingDocker) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	f.Platform = platform
 	f.Ports = config.ExposedPorts
 	f.Cmd = config.Cmd
 	f.Env = config.Env
@@ -52,11 +115,66 @@ func (f *fakeStartingDocker) ContainerCreate(ctx context.Context, config *contai
 	f.Policy = hostConfig.RestartPolicy
 	f.CapAdd = hostConfig.CapAdd
 	f.CapDel = hostConfig.CapDrop
-	f.Labels = config.Labels
+	// The managed-by label is set on every container; only user supplied labels are recorded
+	// here so that each case does not need to repeat it.
+	f.rawLabels = config.Labels
+	for k, v := range config.Labels {
+		if k == managedByLabel {
+			continue
+		}
+		if f.Labels == nil {
+			f.Labels = map[string]string{}
+		}
+		f.Labels[k] = v
+	}
+	f.bindings = hostConfig.PortBindings
 	f.CPU = hostConfig.Resources.NanoCPUs
+	f.CPUShares = hostConfig.Resources.CPUShares
+	f.CPUQuota = hostConfig.Resources.CPUQuota
+	f.CPUPeriod = hostConfig.Resources.CPUPeriod
 	f.HardMemory = hostConfig.Resources.Memory
 	f.SoftMemory = hostConfig.Resources.MemoryReservation
+	f.MemorySwap = hostConfig.Resources.MemorySwap
+	f.OOMScoreAdj = hostConfig.OomScoreAdj
+	f.OOMKillDisable = hostConfig.Resources.OomKillDisable
 	f.Devices = hostConfig.Resources.Devices
+	f.ReadOnlyRootFS = hostConfig.ReadonlyRootfs
+	f.Privileged = hostConfig.Privileged
+	f.Tmpfs = hostConfig.Tmpfs
+	f.Healthcheck = config.Healthcheck
+	f.PidsLimit = hostConfig.Resources.PidsLimit
+	f.Ulimits = hostConfig.Resources.Ulimits
+	f.BlkioWeight = hostConfig.Resources.BlkioWeight
+	f.BlkioReadBps = hostConfig.Resources.BlkioDeviceReadBps
+	f.BlkioWriteBps = hostConfig.Resources.BlkioDeviceWriteBps
+	f.BlkioReadIOps = hostConfig.Resources.BlkioDeviceReadIOps
+	f.BlkioWriteIOps = hostConfig.Resources.BlkioDeviceWriteIOps
+	f.CpusetCpus = hostConfig.Resources.CpusetCpus
+	f.CpusetMems = hostConfig.Resources.CpusetMems
+	f.DeviceRequests = hostConfig.Resources.DeviceRequests
+	f.Endpoints = networkingConfig.EndpointsConfig
+	f.DNS = hostConfig.DNS
+	f.DNSSearch = hostConfig.DNSSearch
+	f.ExtraHosts = hostConfig.ExtraHosts
+	f.ShmSize = hostConfig.ShmSize
+	f.SecurityOpt = hostConfig.SecurityOpt
+	f.GroupAdd = hostConfig.GroupAdd
+	f.UsernsMode = hostConfig.UsernsMode
+	f.PidMode = hostConfig.PidMode
+	f.IpcMode = hostConfig.IpcMode
+	f.Sysctls = hostConfig.Sysctls
+	f.LogConfig = hostConfig.LogConfig
+	f.WorkingDir = config.WorkingDir
+	f.Hostname = config.Hostname
+	f.Domainname = config.Domainname
+	f.Entrypoint = config.Entrypoint
+	f.ClearEntrypoint = config.Entrypoint != nil && len(config.Entrypoint) == 0
+	f.NoTTY = !config.Tty
+	f.OpenStdin = config.OpenStdin
+	f.AttachStdin = config.AttachStdin
+	f.Init = hostConfig.Init
+	f.StopTimeout = config.StopTimeout
+	f.Runtime = hostConfig.Runtime
 	// If this is not out default, remember it.
 	if !hostConfig.NetworkMode.IsHost() {
 		f.Network = string(hostConfig.NetworkMode)
@@ -80,7 +198,21 @@ func (f fakeStartingDocker) ContainerList(ctx context.Context, options container
 	return f.cnts, nil
 }
 
+// intPtr returns a pointer to v.
+func intPtr(v int) *int {
+	return &v
+}
+
 func TestContainerStart(t *testing.T) {
+	seccompFile := filepath.Join(t.TempDir(), "profile.json")
+	if err := os.WriteFile(seccompFile, []byte("{\n  \"defaultAction\": \"SCMP_ACT_ERRNO\"\n}\n"), 0600); err != nil {
+		t.Fatalf("unable to write seccomp profile: %v", err)
+	}
+	invalidSeccompFile := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalidSeccompFile, []byte("not json"), 0600); err != nil {
+		t.Fatalf("unable to write seccomp profile: %v", err)
+	}
+
 	tests := []struct {
 		name        string
 		inOpts      []options.Option
@@ -96,7 +228,7 @@ func TestContainerStart(t *testing.T) {
 			name:    "no-such-image",
 			inImage: "no-such-image",
 			inTag:   "no-such-tag",
-			wantErr: status.Error(codes.NotFound, "image no-such-image:no-such-tag not found"),
+			wantErr: detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "no-such-image:no-such-tag"}, "image no-such-image:no-such-tag not found"),
 		},
 		{
 			name:    "container-with-instance-name-exists",
@@ -114,7 +246,7 @@ func TestContainerStart(t *testing.T) {
 				},
 			},
 			inOpts:  []options.Option{options.WithInstanceName("my-container")},
-			wantErr: status.Errorf(codes.AlreadyExists, "instance name my-container already in use"),
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-container"}, "instance name my-container already in use"),
 		},
 		{
 			name:    "container-with-empty-user",
@@ -156,7 +288,41 @@ func TestContainerStart(t *testing.T) {
 				},
 			},
 			inOpts:  []options.Option{options.WithInstanceName("my-container"), options.WithPorts(map[uint32]uint32{1: 1})},
-			wantErr: status.Errorf(codes.Unavailable, "port 1 already in use"),
+			wantErr: detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "1", "protocol": "tcp"}, "port 1 already in use"),
+		},
+		{
+			name:    "digest-pinned-image",
+			inImage: "my-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags:    []string{"my-image:my-tag"},
+					RepoDigests: []string{"my-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+				},
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "untagged-image",
+			inImage: "my-image",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:latest"},
+				},
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "malformed-image",
+			inImage: "my-image:my-tag",
+			inTag:   "extra",
+			inCmd:   "my-cmd",
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "my-image:my-tag:extra": invalid reference format`),
 		},
 		{
 			name:    "container-with-ports",
@@ -177,7 +343,7 @@ func TestContainerStart(t *testing.T) {
 			},
 		},
 		{
-			name:    "container-with-user-but-no-group",
+			name:    "container-with-udp-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -186,23 +352,19 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
-			inCnts: []types.Container{
-				{
-					Names: []string{"/my-container"},
-				},
-			},
 			inOpts: []options.Option{
-				options.WithRunAs(&cpb.StartContainerRequest_RunAs{
-					User: "my-user",
-				}),
+				options.WithInstanceName("my-container"),
+				options.WithPortMappings([]options.Port{{Internal: 514, External: 514, Protocol: "UDP"}}),
 			},
 			wantState: &fakeStartingDocker{
-				Cmd:  []string{"my-cmd"},
-				User: "my-user",
+				Cmd:         []string{"my-cmd"},
+				Ports:       nat.PortSet{"514/udp": struct{}{}},
+				ContainerID: "my-container",
+				Volumes:     []mount.Mount{},
 			},
 		},
 		{
-			name:    "container-with-user-and-group",
+			name:    "container-with-tcp-and-udp-on-same-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -213,22 +375,28 @@ func TestContainerStart(t *testing.T) {
 			},
 			inCnts: []types.Container{
 				{
-					Names: []string{"/my-container"},
+					Ports: []types.Port{
+						{
+							PublicPort: 3784,
+							Type:       "sctp",
+						},
+					},
 				},
 			},
 			inOpts: []options.Option{
-				options.WithRunAs(&cpb.StartContainerRequest_RunAs{
-					User:  "my-user",
-					Group: "my-group",
-				}),
+				options.WithInstanceName("my-container"),
+				options.WithPorts(map[uint32]uint32{3784: 3784}),
+				options.WithPortMappings([]options.Port{{Internal: 3784, External: 3784, Protocol: "udp"}}),
 			},
 			wantState: &fakeStartingDocker{
-				Cmd:  []string{"my-cmd"},
-				User: "my-user:my-group",
+				Cmd:         []string{"my-cmd"},
+				Ports:       nat.PortSet{"3784/tcp": struct{}{}, "3784/udp": struct{}{}},
+				ContainerID: "my-container",
+				Volumes:     []mount.Mount{},
 			},
 		},
 		{
-			name:    "container-with-retry-policy-and-attempts",
+			name:    "container-reusing-udp-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -239,25 +407,21 @@ func TestContainerStart(t *testing.T) {
 			},
 			inCnts: []types.Container{
 				{
-					Names: []string{"/my-container"},
+					Ports: []types.Port{
+						{
+							PublicPort: 514,
+							Type:       "udp",
+						},
+					},
 				},
 			},
 			inOpts: []options.Option{
-				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
-					Policy:   cpb.StartContainerRequest_Restart_ON_FAILURE,
-					Attempts: 3,
-				}),
-			},
-			wantState: &fakeStartingDocker{
-				Cmd: []string{"my-cmd"},
-				Policy: container.RestartPolicy{
-					Name:              "on-failure",
-					MaximumRetryCount: 3,
-				},
+				options.WithPortMappings([]options.Port{{Internal: 514, External: 514, Protocol: "udp"}}),
 			},
+			wantErr: detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "514", "protocol": "udp"}, "port 514/udp already in use"),
 		},
 		{
-			name:    "container-with-capabilities",
+			name:    "container-with-duplicate-host-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -266,25 +430,16 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
-			inCnts: []types.Container{
-				{
-					Names: []string{"/my-container"},
-				},
-			},
 			inOpts: []options.Option{
-				options.WithCapabilities(&cpb.StartContainerRequest_Capabilities{
-					Add:    []string{"my-add-capability"},
-					Remove: []string{"my-remove-capability"},
+				options.WithPortMappings([]options.Port{
+					{Internal: 514, External: 514, Protocol: "udp"},
+					{Internal: 515, External: 514, Protocol: "udp"},
 				}),
 			},
-			wantState: &fakeStartingDocker{
-				Cmd:    []string{"my-cmd"},
-				CapAdd: []string{"my-add-capability"},
-				CapDel: []string{"my-remove-capability"},
-			},
+			wantErr: status.Errorf(codes.InvalidArgument, "host port 514/udp is published more than once"),
 		},
 		{
-			name:    "container-with-network",
+			name:    "container-with-host-port-conflicting-with-wildcard",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -293,21 +448,16 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
-			inCnts: []types.Container{
-				{
-					Names: []string{"/my-container"},
-				},
-			},
 			inOpts: []options.Option{
-				options.WithNetwork("my-network"),
-			},
-			wantState: &fakeStartingDocker{
-				Cmd:     []string{"my-cmd"},
-				Network: "my-network",
+				options.WithPortMappings([]options.Port{
+					{Internal: 80, External: 80, HostIP: "0.0.0.0"},
+					{Internal: 81, External: 80, HostIP: "10.0.0.1"},
+				}),
 			},
+			wantErr: status.Errorf(codes.InvalidArgument, "host port 10.0.0.1:80 is published more than once"),
 		},
 		{
-			name:    "container-with-labels",
+			name:    "container-with-unsupported-protocol",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -316,21 +466,29 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
-			inCnts: []types.Container{
+			inOpts: []options.Option{
+				options.WithPortMappings([]options.Port{{Internal: 514, External: 514, Protocol: "icmp"}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unsupported protocol "icmp" for port 514: expected tcp, udp or sctp`),
+		},
+		{
+			name:    "container-with-privileged-port",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
 				{
-					Names: []string{"/my-container"},
+					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
 			inOpts: []options.Option{
-				options.WithLabels(map[string]string{"label": "value"}),
-			},
-			wantState: &fakeStartingDocker{
-				Cmd:    []string{"my-cmd"},
-				Labels: map[string]string{"label": "value"},
+				options.WithPorts(map[uint32]uint32{80: 80}),
+				options.WithPrivilegedPortThreshold(1024),
 			},
+			wantErr: status.Errorf(codes.PermissionDenied, "host port 80 is privileged: ports below 1024 are not allowed"),
 		},
 		{
-			name:    "container-with-env-and-port",
+			name:    "container-with-allowed-privileged-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -339,17 +497,21 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
-			inOpts: []options.Option{options.WithInstanceName("my-container"), options.WithPorts(map[uint32]uint32{1: 1}), options.WithEnv(map[string]string{"AA": "BB"})},
+			inOpts: []options.Option{
+				options.WithInstanceName("my-container"),
+				options.WithPorts(map[uint32]uint32{80: 80}),
+				options.WithPrivilegedPortThreshold(1024),
+				options.WithAllowPrivilegedPorts(),
+			},
 			wantState: &fakeStartingDocker{
 				Cmd:         []string{"my-cmd"},
-				Ports:       nat.PortSet{"1/tcp": struct{}{}},
-				Env:         []string{"AA=BB"},
+				Ports:       nat.PortSet{"80/tcp": struct{}{}},
 				ContainerID: "my-container",
 				Volumes:     []mount.Mount{},
 			},
 		},
 		{
-			name:    "container-with-env-and-port-and-volumes-and-devices",
+			name:    "container-with-unprivileged-port",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -360,45 +522,44 @@ func TestContainerStart(t *testing.T) {
 			},
 			inOpts: []options.Option{
 				options.WithInstanceName("my-container"),
-				options.WithPorts(map[uint32]uint32{1: 1}),
-				options.WithEnv(map[string]string{"AA": "BB"}),
-				options.WithVolumes([]*cpb.Volume{
-					{
-						Name:       "my-volume",
-						MountPoint: "/tmp",
-					},
-				}),
-				options.WithDevices([]*cpb.Device{
-					{
-						SrcPath:     "/dev/my-device",
-						DstPath:     "/dev/my-device",
-						Permissions: []cpb.Device_Permission{cpb.Device_READ, cpb.Device_WRITE, cpb.Device_MKNOD},
-					},
-				}),
+				options.WithPorts(map[uint32]uint32{80: 8080}),
+				options.WithPrivilegedPortThreshold(1024),
 			},
 			wantState: &fakeStartingDocker{
 				Cmd:         []string{"my-cmd"},
-				Ports:       nat.PortSet{"1/tcp": struct{}{}},
-				Env:         []string{"AA=BB"},
+				Ports:       nat.PortSet{"80/tcp": struct{}{}},
 				ContainerID: "my-container",
-				Volumes: []mount.Mount{
-					{
-						Type:   "volume",
-						Source: "my-volume",
-						Target: "/tmp",
-					},
+				Volumes:     []mount.Mount{},
+			},
+		},
+		{
+			name:    "container-with-zero-host-port",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
 				},
-				Devices: []container.DeviceMapping{
-					{
-						PathOnHost:        "/dev/my-device",
-						PathInContainer:   "/dev/my-device",
-						CgroupPermissions: "rwm",
-					},
+			},
+			inOpts:  []options.Option{options.WithPorts(map[uint32]uint32{80: 0})},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid host port 0: must be between 1 and 65535"),
+		},
+		{
+			name:    "container-with-out-of-range-container-port",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
+			inOpts:  []options.Option{options.WithPorts(map[uint32]uint32{70000: 8080})},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid container port 70000: must be between 1 and 65535"),
 		},
 		{
-			name:    "container-with-cpu-soft-and-hard-memory",
+			name:    "container-with-user-but-no-group",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   "my-cmd",
@@ -407,46 +568,2236 @@ func TestContainerStart(t *testing.T) {
 					RepoTags: []string{"my-image:my-tag"},
 				},
 			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
 			inOpts: []options.Option{
-				options.WithInstanceName("my-container"),
-				options.WithCPUs(1.0),
-				options.WithSoftLimit(1000),
-				options.WithHardLimit(2000),
+				options.WithRunAs(&cpb.StartContainerRequest_RunAs{
+					User: "my-user",
+				}),
 			},
 			wantState: &fakeStartingDocker{
-				Cmd:         []string{"my-cmd"},
-				ContainerID: "my-container",
-				CPU:         1000000000,
-				HardMemory:  2000,
-				SoftMemory:  1000,
+				Cmd:  []string{"my-cmd"},
+				User: "my-user",
 			},
 		},
 		{
-			name:    "container-with-cmd",
+			name:    "container-with-user-and-group",
 			inImage: "my-image",
 			inTag:   "my-tag",
-			inCmd:   `sleep 1000`,
-			inSummaries: []image.Summary{{
-				RepoTags: []string{"my-image:my-tag"},
-			}},
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRunAs(&cpb.StartContainerRequest_RunAs{
+					User:  "my-user",
+					Group: "my-group",
+				}),
+			},
 			wantState: &fakeStartingDocker{
-				Cmd: []string{"sleep", "1000"},
+				Cmd:  []string{"my-cmd"},
+				User: "my-user:my-group",
 			},
 		},
 		{
-			name:    "container-with-sh-cmd",
+			name:    "container-with-retry-policy-and-attempts",
 			inImage: "my-image",
 			inTag:   "my-tag",
-			inCmd:   `sh -c "echo 2"`,
-			inSummaries: []image.Summary{{
-				RepoTags: []string{"my-image:my-tag"},
-			}},
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy:   cpb.StartContainerRequest_Restart_ON_FAILURE,
+					Attempts: 3,
+				}),
+			},
 			wantState: &fakeStartingDocker{
-				Cmd: []string{"sh", "-c", "echo 2"},
+				Cmd: []string{"my-cmd"},
+				Policy: container.RestartPolicy{
+					Name:              "on-failure",
+					MaximumRetryCount: 3,
+				},
 			},
 		},
 		{
-			name:    "container-with-quoted-cmd",
+			name:    "container-with-always-restart-policy",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy: cpb.StartContainerRequest_Restart_ALWAYS,
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Policy: container.RestartPolicy{
+					Name: "always",
+				},
+			},
+		},
+		{
+			name:    "container-with-unless-stopped-restart-policy",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy: cpb.StartContainerRequest_Restart_UNLESS_STOPPED,
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Policy: container.RestartPolicy{
+					Name: "unless-stopped",
+				},
+			},
+		},
+		{
+			name:    "container-with-no-restart-policy",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy: cpb.StartContainerRequest_Restart_NONE,
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Policy: container.RestartPolicy{
+					Name: "no",
+				},
+			},
+		},
+		{
+			name:    "container-with-always-restart-policy-and-attempts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy:   cpb.StartContainerRequest_Restart_ALWAYS,
+					Attempts: 3,
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `restart attempts can only be set with the on-failure restart policy, got policy "always"`),
+		},
+		{
+			name:    "container-with-unless-stopped-restart-policy-and-attempts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy:   cpb.StartContainerRequest_Restart_UNLESS_STOPPED,
+					Attempts: 3,
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `restart attempts can only be set with the on-failure restart policy, got policy "unless-stopped"`),
+		},
+		{
+			name:    "container-with-no-restart-policy-and-attempts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy:   cpb.StartContainerRequest_Restart_NONE,
+					Attempts: 3,
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `restart attempts can only be set with the on-failure restart policy, got policy "no"`),
+		},
+		{
+			name:    "container-with-capabilities",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCapabilities(&cpb.StartContainerRequest_Capabilities{
+					Add:    []string{"my-add-capability"},
+					Remove: []string{"my-remove-capability"},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:    []string{"my-cmd"},
+				CapAdd: []string{"my-add-capability"},
+				CapDel: []string{"my-remove-capability"},
+			},
+		},
+		{
+			name:    "container-with-network",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetwork("my-network"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				Network: "my-network",
+			},
+		},
+		{
+			name:    "container-with-labels",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					Names: []string{"/my-container"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLabels(map[string]string{"label": "value"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:    []string{"my-cmd"},
+				Labels: map[string]string{"label": "value"},
+			},
+		},
+		{
+			name:    "container-with-env-and-port",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{options.WithInstanceName("my-container"), options.WithPorts(map[uint32]uint32{1: 1}), options.WithEnv(map[string]string{"AA": "BB"})},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				Ports:       nat.PortSet{"1/tcp": struct{}{}},
+				Env:         []string{"AA=BB"},
+				ContainerID: "my-container",
+				Volumes:     []mount.Mount{},
+			},
+		},
+		{
+			name:    "container-with-missing-env-file",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithInstanceName("my-container"),
+				options.WithEnv(map[string]string{"AA": "BB"}),
+				options.WithEnvFile("/no/such/dir/my-app.env"),
+			},
+			wantErr: status.Error(codes.NotFound, "env file /no/such/dir/my-app.env not found"),
+		},
+		{
+			name:    "container-with-env-and-port-and-volumes-and-devices",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithInstanceName("my-container"),
+				options.WithPorts(map[uint32]uint32{1: 1}),
+				options.WithEnv(map[string]string{"AA": "BB"}),
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-volume",
+						MountPoint: "/tmp",
+					},
+				}),
+				options.WithDevices([]*cpb.Device{
+					{
+						SrcPath:     "/dev/my-device",
+						DstPath:     "/dev/my-device",
+						Permissions: []cpb.Device_Permission{cpb.Device_READ, cpb.Device_WRITE, cpb.Device_MKNOD},
+					},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				Ports:       nat.PortSet{"1/tcp": struct{}{}},
+				Env:         []string{"AA=BB"},
+				ContainerID: "my-container",
+				Volumes: []mount.Mount{
+					{
+						Type:   "volume",
+						Source: "my-volume",
+						Target: "/tmp",
+					},
+				},
+				Devices: []container.DeviceMapping{
+					{
+						PathOnHost:        "/dev/my-device",
+						PathInContainer:   "/dev/my-device",
+						CgroupPermissions: "rwm",
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-cpu-soft-and-hard-memory",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithInstanceName("my-container"),
+				options.WithCPUs(1.0),
+				options.WithSoftLimit(1000),
+				options.WithHardLimit(2000),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				ContainerID: "my-container",
+				CPU:         1000000000,
+				HardMemory:  2000,
+				SoftMemory:  1000,
+			},
+		},
+		{
+			name:    "container-with-cpu-shares",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUShares(512),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:       []string{"my-cmd"},
+				CPUShares: 512,
+			},
+		},
+		{
+			name:    "container-with-cpu-quota-and-period",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUQuota(50000),
+				options.WithCPUPeriod(100000),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:       []string{"my-cmd"},
+				CPUQuota:  50000,
+				CPUPeriod: 100000,
+			},
+		},
+		{
+			name:    "container-with-cpus-and-cpu-quota",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUs(1.0),
+				options.WithCPUQuota(50000),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "a cpu limit cannot be combined with a cpu quota or period"),
+		},
+		{
+			name:    "container-with-cpus-and-cpu-period",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUs(1.0),
+				options.WithCPUPeriod(100000),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "a cpu limit cannot be combined with a cpu quota or period"),
+		},
+		{
+			name:    "container-with-invalid-cpu-period",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUPeriod(100),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid cpu period 100: must be between 1000 and 1000000 microseconds"),
+		},
+		{
+			name:    "container-with-blkio-limits",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBlkioWeight(300),
+				options.WithBlkioDeviceLimits([]*options.BlkioDeviceLimit{
+					{Path: "/dev/sda", ReadBps: 1048576, WriteIOps: 100},
+					{Path: "/dev/sdb", WriteBps: 2097152, ReadIOps: 200},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:            []string{"my-cmd"},
+				BlkioWeight:    300,
+				BlkioReadBps:   []*blkiodev.ThrottleDevice{{Path: "/dev/sda", Rate: 1048576}},
+				BlkioWriteBps:  []*blkiodev.ThrottleDevice{{Path: "/dev/sdb", Rate: 2097152}},
+				BlkioReadIOps:  []*blkiodev.ThrottleDevice{{Path: "/dev/sdb", Rate: 200}},
+				BlkioWriteIOps: []*blkiodev.ThrottleDevice{{Path: "/dev/sda", Rate: 100}},
+			},
+		},
+		{
+			name:    "container-with-invalid-blkio-weight",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBlkioWeight(5),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid blkio weight 5: must be between 10 and 1000"),
+		},
+		{
+			name:    "container-with-relative-blkio-device",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBlkioDeviceLimits([]*options.BlkioDeviceLimit{{Path: "sda", ReadBps: 1}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `blkio device path "sda" must be an absolute path`),
+		},
+		{
+			name:    "container-with-memory-swap",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHardLimit(2000),
+				options.WithMemorySwap(4000),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				HardMemory: 2000,
+				MemorySwap: 4000,
+			},
+		},
+		{
+			name:    "container-with-unlimited-swap",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHardLimit(2000),
+				options.WithMemorySwap(-1),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				HardMemory: 2000,
+				MemorySwap: -1,
+			},
+		},
+		{
+			name:    "container-with-swap-below-hard-limit",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHardLimit(2000),
+				options.WithMemorySwap(1000),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "memory swap limit 1000 is lower than the hard memory limit 2000"),
+		},
+		{
+			name:    "container-with-swap-without-hard-limit",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithMemorySwap(1000),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "memory swap limit 1000 requires a hard memory limit"),
+		},
+		{
+			name:    "container-with-oom-settings",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHardLimit(2000),
+				options.WithOOMScoreAdj(-1000),
+				options.WithOOMKillDisable(true),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:            []string{"my-cmd"},
+				HardMemory:     2000,
+				OOMScoreAdj:    -1000,
+				OOMKillDisable: proto.Bool(true),
+			},
+		},
+		{
+			name:    "container-with-out-of-range-oom-score",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithOOMScoreAdj(1001),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid oom score adjustment 1001: must be between -1000 and 1000"),
+		},
+		{
+			name:    "container-with-oom-kill-disabled-without-memory-limit",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithOOMKillDisable(true),
+			},
+			wantErr: status.Errorf(codes.FailedPrecondition, "disabling the OOM killer requires a hard memory limit"),
+		},
+		{
+			name:    "container-with-read-only-rootfs-and-volumes",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithInstanceName("my-container"),
+				options.WithReadOnlyRootFS(true),
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-volume",
+						MountPoint: "/data",
+					},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				ContainerID: "my-container",
+				Volumes: []mount.Mount{
+					{
+						Type:   "volume",
+						Source: "my-volume",
+						Target: "/data",
+					},
+				},
+				ReadOnlyRootFS: true,
+			},
+		},
+		{
+			name:    "container-with-read-only-and-writable-volumes",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithReadOnlyRootFS(false),
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-config",
+						MountPoint: "/etc/my-config",
+						ReadOnly:   true,
+					},
+					{
+						Name:       "my-data",
+						MountPoint: "/data",
+					},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Volumes: []mount.Mount{
+					{
+						Type:     "volume",
+						Source:   "my-config",
+						Target:   "/etc/my-config",
+						ReadOnly: true,
+					},
+					{
+						Type:   "volume",
+						Source: "my-data",
+						Target: "/data",
+					},
+				},
+				ReadOnlyRootFS: false,
+			},
+		},
+		{
+			name:    "container-with-read-only-rootfs-disabled",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithReadOnlyRootFS(false),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:            []string{"my-cmd"},
+				ReadOnlyRootFS: false,
+			},
+		},
+		{
+			name:    "container-with-tmpfs",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithTmpfs(map[string]string{
+					"/run":     "rw,size=64m",
+					"/scratch": "",
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Tmpfs: map[string]string{
+					"/run":     "rw,size=64m",
+					"/scratch": "",
+				},
+			},
+		},
+		{
+			name:    "container-with-tmpfs-empty-target",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithTmpfs(map[string]string{"": "rw"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "tmpfs mount target can not be empty"),
+		},
+		{
+			name:    "container-with-tmpfs-colliding-with-volume",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-volume",
+						MountPoint: "/run",
+					},
+				}),
+				options.WithTmpfs(map[string]string{"/run": "rw"}),
+			},
+			wantErr: status.Errorf(codes.AlreadyExists, "tmpfs mount target /run is already used by volume my-volume"),
+		},
+		{
+			name:    "container-with-tmpfs-colliding-with-uncleaned-volume",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-volume",
+						MountPoint: "/data",
+					},
+				}),
+				options.WithTmpfs(map[string]string{"/data/": "rw"}),
+			},
+			wantErr: status.Errorf(codes.AlreadyExists, "tmpfs mount target /data/ is already used by volume my-volume"),
+		},
+		{
+			name:    "container-with-tmpfs-colliding-with-tmpfs",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithTmpfs(map[string]string{"/run": "rw", "/run/": "ro"}),
+			},
+			wantErr: status.Errorf(codes.AlreadyExists, "tmpfs mount target /run/ is already used by tmpfs mount /run"),
+		},
+		{
+			name:    "container-with-tmpfs-relative-target",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithTmpfs(map[string]string{"scratch": "rw"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `tmpfs mount target "scratch" must be an absolute path`),
+		},
+		{
+			name:    "container-with-bind-mounts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithVolumes([]*cpb.Volume{
+					{
+						Name:       "my-volume",
+						MountPoint: "/data",
+					},
+				}),
+				options.WithBindMounts([]options.BindMount{
+					{
+						Source:   "/var/log",
+						Target:   "/logs",
+						ReadOnly: true,
+					},
+					{
+						Source:      "/run/shared",
+						Target:      "/shared",
+						Propagation: "rslave",
+						Consistency: "delegated",
+					},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Volumes: []mount.Mount{
+					{
+						Type:   "volume",
+						Source: "my-volume",
+						Target: "/data",
+					},
+					{
+						Type:     "bind",
+						Source:   "/var/log",
+						Target:   "/logs",
+						ReadOnly: true,
+					},
+					{
+						Type:        "bind",
+						Source:      "/run/shared",
+						Target:      "/shared",
+						Consistency: mount.ConsistencyDelegated,
+						BindOptions: &mount.BindOptions{Propagation: mount.PropagationRSlave},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-relative-bind-mount-source",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: "var/log", Target: "/logs"}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "bind mount source \"var/log\" must be an absolute path"),
+		},
+		{
+			name:    "container-with-relative-bind-mount-target",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: "/var/log", Target: "logs"}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "bind mount target \"logs\" must be an absolute path"),
+		},
+		{
+			name:    "container-with-unknown-bind-propagation",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: "/var/log", Target: "/logs", Propagation: "bogus"}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "unknown bind propagation \"bogus\""),
+		},
+		{
+			name:    "container-with-rshared-bind-mount",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: "/mnt", Target: "/mnt", Propagation: "rshared"}}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Volumes: []mount.Mount{
+					{
+						Type:        "bind",
+						Source:      "/mnt",
+						Target:      "/mnt",
+						BindOptions: &mount.BindOptions{Propagation: mount.PropagationRShared},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-unknown-mount-consistency",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: "/var/log", Target: "/logs", Consistency: "bogus"}}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "unknown mount consistency \"bogus\""),
+		},
+		{
+			name:    "container-with-health-check",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHealthCheck("curl -f http://localhost", 10*time.Second, 5*time.Second, 3, 30*time.Second),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Healthcheck: &container.HealthConfig{
+					Test:        []string{"CMD", "curl", "-f", "http://localhost"},
+					Interval:    10 * time.Second,
+					Timeout:     5 * time.Second,
+					Retries:     3,
+					StartPeriod: 30 * time.Second,
+				},
+			},
+		},
+		{
+			name:    "container-with-disabled-health-check",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHealthCheck("", 0, 0, 0, 0),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Healthcheck: &container.HealthConfig{
+					Test: []string{"NONE"},
+				},
+			},
+		},
+		{
+			name:    "container-with-health-check-bad-interval",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHealthCheck("true", 0, 5*time.Second, 3, 0),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "health check interval must be positive, got 0s"),
+		},
+		{
+			name:    "container-with-health-check-bad-timeout",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHealthCheck("true", 5*time.Second, -time.Second, 3, 0),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "health check timeout must be positive, got -1s"),
+		},
+		{
+			name:    "container-with-pids-limit",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidsLimit(100),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:       []string{"my-cmd"},
+				PidsLimit: proto.Int64(100),
+			},
+		},
+		{
+			name:    "container-with-unlimited-pids",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidsLimit(-1),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-invalid-pids-limit",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidsLimit(-2),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid pids limit -2"),
+		},
+		{
+			name:    "container-with-ulimits",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithUlimits([]*options.Ulimit{
+					{Name: "nofile", Soft: 1024, Hard: 4096},
+					{Name: "my-limit", Soft: 1, Hard: 1},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Ulimits: []*container.Ulimit{
+					{Name: "nofile", Soft: 1024, Hard: 4096},
+					{Name: "my-limit", Soft: 1, Hard: 1},
+				},
+			},
+		},
+		{
+			name:    "container-with-soft-ulimit-above-hard",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithUlimits([]*options.Ulimit{
+					{Name: "nproc", Soft: 10, Hard: 5},
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "soft limit 10 exceeds hard limit 5 for ulimit nproc"),
+		},
+		{
+			name:    "container-with-cpuset-and-cpus",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCPUs(2.0),
+				options.WithCpusetCpus("0-2,4"),
+				options.WithCpusetMems("0,1"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				CPU:        2000000000,
+				CpusetCpus: "0-2,4",
+				CpusetMems: "0,1",
+			},
+		},
+		{
+			name:    "container-with-malformed-cpuset-cpus",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCpusetCpus("0-,a"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid cpuset cpus "0-,a": malformed element "0-"`),
+		},
+		{
+			name:    "container-with-inverted-cpuset-mems",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithCpusetMems("3-1"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid cpuset mems "3-1": range "3-1" is inverted`),
+		},
+		{
+			name:    "container-with-all-gpus",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithGPUs("all", nil),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				DeviceRequests: []container.DeviceRequest{
+					{
+						Driver:       "nvidia",
+						Count:        -1,
+						Capabilities: [][]string{{"gpu"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-gpu-count-and-capabilities",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithGPUs("2", []string{"compute", "utility"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				DeviceRequests: []container.DeviceRequest{
+					{
+						Driver:       "nvidia",
+						Count:        2,
+						Capabilities: [][]string{{"gpu", "compute", "utility"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-zero-gpus",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithGPUs("0", nil),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `gpu count must be a positive number or "all", got "0"`),
+		},
+		{
+			name:    "container-with-multiple-networks",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetworks([]string{"mgmt", "data", "mgmt"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				Network: "mgmt",
+				Endpoints: map[string]*network.EndpointSettings{
+					"mgmt": {},
+					"data": {},
+				},
+			},
+		},
+		{
+			name:    "container-with-empty-network-name",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetworks([]string{"mgmt", ""}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "network name can not be empty"),
+		},
+		{
+			name:    "container-with-static-ips",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetworks([]string{"mgmt", "data"}),
+				options.WithStaticIPs(map[string][]string{
+					"data": {"10.0.0.2", "2001:db8::2"},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				Network: "mgmt",
+				Endpoints: map[string]*network.EndpointSettings{
+					"mgmt": {},
+					"data": {
+						IPAMConfig: &network.EndpointIPAMConfig{
+							IPv4Address: "10.0.0.2",
+							IPv6Address: "2001:db8::2",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-static-ip-on-single-network",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetwork("my-network"),
+				options.WithStaticIPs(map[string][]string{
+					"my-network": {"10.0.0.2"},
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				Network: "my-network",
+				Endpoints: map[string]*network.EndpointSettings{
+					"my-network": {
+						IPAMConfig: &network.EndpointIPAMConfig{
+							IPv4Address: "10.0.0.2",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "container-with-static-ip-in-host-mode",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithStaticIPs(map[string][]string{
+					"host": {"10.0.0.2"},
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `static addresses require a named network, got "host"`),
+		},
+		{
+			name:    "container-with-invalid-static-ip",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithNetworks([]string{"data"}),
+				options.WithStaticIPs(map[string][]string{
+					"data": {"10.0.0.256"},
+				}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid address "10.0.0.256" for network data`),
+		},
+		{
+			name:    "container-with-dns-and-extra-hosts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithDNS([]string{"8.8.8.8", "2001:4860:4860::8888"}),
+				options.WithDNSSearch([]string{"example.com"}),
+				options.WithExtraHosts([]string{"my-host:10.0.0.1", "my-v6-host:2001:db8::1", "gw:host-gateway"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				DNS:        []string{"8.8.8.8", "2001:4860:4860::8888"},
+				DNSSearch:  []string{"example.com"},
+				ExtraHosts: []string{"my-host:10.0.0.1", "my-v6-host:2001:db8::1", "gw:host-gateway"},
+			},
+		},
+		{
+			name:    "container-with-invalid-dns",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithDNS([]string{"dns.example.com"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid dns server "dns.example.com"`),
+		},
+		{
+			name:    "container-with-malformed-extra-host",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExtraHosts([]string{"my-host"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `extra host "my-host" must be of the form hostname:ip`),
+		},
+		{
+			name:    "container-with-extra-host-bad-address",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExtraHosts([]string{"my-host:not-an-ip"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `extra host "my-host:not-an-ip" has an invalid address`),
+		},
+		{
+			name:    "container-with-shm-size",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithShmSize(256 * 1024 * 1024),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				ShmSize: 256 * 1024 * 1024,
+			},
+		},
+		{
+			name:    "container-with-default-shm-size",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithShmSize(0),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-negative-shm-size",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithShmSize(-1),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "shm size can not be negative, got -1"),
+		},
+		{
+			name:    "container-with-security-opts",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSecurityOpts([]string{"seccomp=" + seccompFile, "apparmor=my-profile", "no-new-privileges"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				SecurityOpt: []string{`seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`, "apparmor=my-profile", "no-new-privileges"},
+			},
+		},
+		{
+			name:    "container-with-unconfined-and-inline-seccomp",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSecurityOpts([]string{"seccomp=unconfined", `seccomp={"defaultAction":"SCMP_ACT_LOG"}`}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				SecurityOpt: []string{"seccomp=unconfined", `seccomp={"defaultAction":"SCMP_ACT_LOG"}`},
+			},
+		},
+		{
+			name:    "container-with-missing-seccomp-profile",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSecurityOpts([]string{"seccomp=/no/such/profile.json"}),
+			},
+			wantErr: status.Errorf(codes.NotFound, "seccomp profile /no/such/profile.json not found"),
+		},
+		{
+			name:    "container-with-invalid-seccomp-profile",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSecurityOpts([]string{"seccomp=" + invalidSeccompFile}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "seccomp profile %s is not valid JSON: invalid character 'o' in literal null (expecting 'u')", invalidSeccompFile),
+		},
+		{
+			name:    "container-with-unknown-security-opt",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSecurityOpts([]string{"selinux=off"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown security option "selinux=off"`),
+		},
+		{
+			name:    "container-with-platform",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPlatform("linux", "arm64", "v8"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:      []string{"my-cmd"},
+				Platform: &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			},
+		},
+		{
+			name:    "container-with-unknown-platform-os",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPlatform("plan9", "amd64", ""),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown platform operating system "plan9"`),
+		},
+		{
+			name:    "container-with-unknown-platform-arch",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPlatform("windows", "s390x", ""),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown architecture "s390x" for platform operating system windows`),
+		},
+		{
+			name:    "container-with-unknown-platform-variant",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPlatform("linux", "arm", "v9"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown variant "v9" for platform architecture arm`),
+		},
+		{
+			name:    "container-with-host-pid-and-ipc",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidMode("host"),
+				options.WithIpcMode("host"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				PidMode: "host",
+				IpcMode: "host",
+			},
+		},
+		{
+			name:    "container-joining-pid-and-ipc",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					ID:    "target-id",
+					Names: []string{"/target"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidMode("container:target"),
+				options.WithIpcMode("container:target-id"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				PidMode: "container:target",
+				IpcMode: "container:target-id",
+			},
+		},
+		{
+			name:    "container-with-shareable-ipc",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithIpcMode("shareable"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:     []string{"my-cmd"},
+				IpcMode: "shareable",
+			},
+		},
+		{
+			name:    "container-joining-missing-pid",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					ID:    "target-id",
+					Names: []string{"/target"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidMode("container:missing"),
+			},
+			wantErr: status.Errorf(codes.NotFound, "container missing sharing its pid namespace is not running"),
+		},
+		{
+			name:    "container-joining-missing-ipc",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inCnts: []types.Container{
+				{
+					ID:    "target-id",
+					Names: []string{"/target"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithIpcMode("container:missing"),
+			},
+			wantErr: status.Errorf(codes.NotFound, "container missing sharing its ipc namespace is not running"),
+		},
+		{
+			name:    "container-with-unknown-pid-mode",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithPidMode("shareable"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown pid namespace mode "shareable"`),
+		},
+		{
+			name:    "container-with-unnamed-ipc-container",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithIpcMode("container:"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `ipc namespace mode "container:" does not name a container`),
+		},
+		{
+			name:    "container-with-host-userns",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithUsernsMode("host"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				UsernsMode: "host",
+			},
+		},
+		{
+			name:    "container-with-private-userns",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithUsernsMode("private"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				UsernsMode: "",
+			},
+		},
+		{
+			name:    "container-with-unknown-userns",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithUsernsMode("container:other"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unknown user namespace mode "container:other", must be host or private`),
+		},
+		{
+			name:    "container-with-group-add",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithGroupAdd([]string{"dialout", "20"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:      []string{"my-cmd"},
+				GroupAdd: []string{"dialout", "20"},
+			},
+		},
+		{
+			name:    "container-with-empty-group-add",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithGroupAdd([]string{"dialout", ""}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "supplementary group can not be empty"),
+		},
+		{
+			name:    "container-with-sysctls",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSysctls(map[string]string{
+					"net.core.somaxconn":  "1024",
+					"net.ipv4.ip_forward": "1",
+				}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				Sysctls: map[string]string{
+					"net.core.somaxconn":  "1024",
+					"net.ipv4.ip_forward": "1",
+				},
+			},
+		},
+		{
+			name:    "container-with-empty-sysctl-value",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithSysctls(map[string]string{"net.core.somaxconn": ""}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `sysctl "net.core.somaxconn"="" must have a non-empty key and value`),
+		},
+		{
+			name:    "container-with-log-rotation",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("json-file", map[string]string{"max-size": "10m", "max-file": "3"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				LogConfig: container.LogConfig{
+					Type:   "json-file",
+					Config: map[string]string{"max-size": "10m", "max-file": "3"},
+				},
+			},
+		},
+		{
+			name:    "container-with-journald-logs",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("journald", map[string]string{"tag": "my-tag"}),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+				LogConfig: container.LogConfig{
+					Type:   "journald",
+					Config: map[string]string{"tag": "my-tag"},
+				},
+			},
+		},
+		{
+			name:    "container-with-invalid-log-max-size",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("json-file", map[string]string{"max-size": "10 lightyears"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid log max-size "10 lightyears": must be a positive size such as 10m`),
+		},
+		{
+			name:    "container-with-invalid-log-max-file",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("json-file", map[string]string{"max-file": "0"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid log max-file "0": must be a positive integer`),
+		},
+		{
+			name:    "container-with-unsupported-log-driver",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("syslog", nil),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `unsupported log driver "syslog": must be json-file, journald or none`),
+		},
+		{
+			name:    "container-with-none-log-driver-options",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithLogConfig("none", map[string]string{"max-size": "10m"}),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "the none log driver takes no options"),
+		},
+		{
+			name:    "container-with-working-dir",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithWorkingDir("/app"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				WorkingDir: "/app",
+			},
+		},
+		{
+			name:    "container-without-working-dir",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-relative-working-dir",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithWorkingDir("app"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `working directory "app" must be an absolute path`),
+		},
+		{
+			name:    "container-with-hostname-and-domainname",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHostname("router-1"),
+				options.WithDomainname("lab.example.net"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"my-cmd"},
+				Hostname:   "router-1",
+				Domainname: "lab.example.net",
+			},
+		},
+		{
+			name:    "container-with-invalid-hostname",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHostname("router_1"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid hostname "router_1": must be at most 63 letters, digits or hyphens and not start or end with a hyphen`),
+		},
+		{
+			name:    "container-with-overlong-hostname",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithHostname(strings.Repeat("a", 64)),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "invalid hostname %q: must be at most 63 letters, digits or hyphens and not start or end with a hyphen", strings.Repeat("a", 64)),
+		},
+		{
+			name:    "container-with-invalid-domainname",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithDomainname("lab..example.net"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid domain name "lab..example.net": label "" must be at most 63 letters, digits or hyphens and not start or end with a hyphen`),
+		},
+		{
+			name:    "container-with-matching-digest",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags:    []string{"my-image:my-tag"},
+					RepoDigests: []string{"my-image@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExpectedDigest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-mismatched-digest",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags:    []string{"my-image:my-tag"},
+					RepoDigests: []string{"my-image@sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExpectedDigest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			wantErr: status.Errorf(codes.FailedPrecondition, "image my-image:my-tag has digest sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb, expected sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+		},
+		{
+			name:    "digest-pinned-container-with-matching-digest",
+			inImage: "my-image@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoDigests: []string{"my-image@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExpectedDigest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-no-repo-digest",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExpectedDigest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			wantErr: status.Errorf(codes.FailedPrecondition, "image my-image:my-tag has no repo digest to verify against sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+		},
+		{
+			name:    "container-with-invalid-digest",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{
+				{
+					RepoTags: []string{"my-image:my-tag"},
+				},
+			},
+			inOpts: []options.Option{
+				options.WithExpectedDigest("md5:abcd"),
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, `invalid digest "md5:abcd": expected sha256:<hex>`),
+		},
+		{
+			name:    "container-with-cmd",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   `sleep 1000`,
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"sleep", "1000"},
+			},
+		},
+		{
+			name:    "container-with-sh-cmd",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   `sh -c "echo 2"`,
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"sh", "-c", "echo 2"},
+			},
+		},
+		{
+			name:    "container-with-quoted-cmd",
 			inImage: "my-image",
 			inTag:   "my-tag",
 			inCmd:   `echo 'echo "quoted"'`,
@@ -454,41 +2805,1174 @@ func TestContainerStart(t *testing.T) {
 				RepoTags: []string{"my-image:my-tag"},
 			}},
 			wantState: &fakeStartingDocker{
-				Cmd: []string{"echo", `echo "quoted"`},
+				Cmd: []string{"echo", `echo "quoted"`},
+			},
+		},
+		{
+			name:    "container-with-no-cmd",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			wantState: &fakeStartingDocker{},
+		},
+		{
+			name:    "container-with-entrypoint",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithEntrypoint("/bin/my-entrypoint")},
+			wantState: &fakeStartingDocker{
+				Entrypoint: []string{"/bin/my-entrypoint"},
+			},
+		},
+		{
+			name:    "container-with-entrypoint-and-cmd",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   `--flag "some value"`,
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithEntrypoint("/bin/my-entrypoint --verbose")},
+			wantState: &fakeStartingDocker{
+				Cmd:        []string{"--flag", "some value"},
+				Entrypoint: []string{"/bin/my-entrypoint", "--verbose"},
+			},
+		},
+		{
+			name:    "container-with-sh-entrypoint",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithEntrypoint(`sh -c "echo 2"`)},
+			wantState: &fakeStartingDocker{
+				Entrypoint: []string{"sh", "-c", "echo 2"},
+			},
+		},
+		{
+			name:    "container-with-quoted-entrypoint",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithEntrypoint(`echo 'echo "quoted"'`)},
+			wantState: &fakeStartingDocker{
+				Entrypoint: []string{"echo", `echo "quoted"`},
+			},
+		},
+		{
+			name:    "container-with-cleared-entrypoint",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithEntrypoint("")},
+			wantState: &fakeStartingDocker{
+				Cmd:             []string{"my-cmd"},
+				ClearEntrypoint: true,
+			},
+		},
+		{
+			name:    "container-without-tty",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithTTY(false)},
+			wantState: &fakeStartingDocker{
+				Cmd:   []string{"my-cmd"},
+				NoTTY: true,
+			},
+		},
+		{
+			name:    "container-with-tty-and-stdin",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "sh",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithTTY(true), options.WithStdinOpen(true)},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"sh"},
+				OpenStdin:   true,
+				AttachStdin: true,
+			},
+		},
+		{
+			name:    "container-with-init",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithInit(true)},
+			wantState: &fakeStartingDocker{
+				Cmd:  []string{"my-cmd"},
+				Init: proto.Bool(true),
+			},
+		},
+		{
+			name:    "container-without-init",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithInit(false)},
+			wantState: &fakeStartingDocker{
+				Cmd:  []string{"my-cmd"},
+				Init: proto.Bool(false),
+			},
+		},
+		{
+			name:    "container-with-default-init",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			wantState: &fakeStartingDocker{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name:    "container-with-stop-timeout",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			// The grace period of a stop call is not recorded on the container.
+			inOpts: []options.Option{options.WithStopTimeout(30), options.WithStopGracePeriod(5)},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				StopTimeout: intPtr(30),
+			},
+		},
+		{
+			name:    "container-with-zero-stop-timeout",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inCmd:   "my-cmd",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts: []options.Option{options.WithStopTimeout(0)},
+			wantState: &fakeStartingDocker{
+				Cmd:         []string{"my-cmd"},
+				StopTimeout: intPtr(0),
+			},
+		},
+		{
+			name:    "container-with-negative-stop-timeout",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts:  []options.Option{options.WithStopTimeout(-1)},
+			wantErr: status.Error(codes.InvalidArgument, "stop timeout can not be negative, got -1"),
+		},
+		{
+			name:    "container-with-unterminated-entrypoint",
+			inImage: "my-image",
+			inTag:   "my-tag",
+			inSummaries: []image.Summary{{
+				RepoTags: []string{"my-image:my-tag"},
+			}},
+			inOpts:  []options.Option{options.WithEntrypoint(`sh -c "echo`)},
+			wantErr: status.Errorf(codes.InvalidArgument, `failed to split entrypoint "sh -c \"echo", got error EOF found when expecting closing quote`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: tc.inSummaries,
+				cnts:      tc.inCnts,
+			}
+			mgr := New(fsd)
+
+			if _, err := mgr.ContainerStart(context.Background(), tc.inImage, tc.inTag, tc.inCmd, tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerStart(%q, %q, %q, %+v) returned unexpected error(-want, got):\n %s", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerStart(%q, %q, %q, %+v) returned error: %v", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, err)
+			} else if tc.wantErr != nil {
+				t.Fatalf("ContainerStart(%q, %q, %q, %+v) did not return an error, want %v", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, tc.wantErr)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeStartingDocker{}), cmpopts.EquateEmpty()); diff != "" {
+					t.Errorf("ContainerStart(%q, %q, %q, %+v) returned diff(-want, +got):\n%s", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerStartManagedByLabel(t *testing.T) {
+	tests := []struct {
+		name       string
+		inOpts     []options.Option
+		wantLabels map[string]string
+	}{
+		{
+			name: "no-labels",
+			wantLabels: map[string]string{
+				"managed-by": "containerz",
+			},
+		},
+		{
+			name: "user-labels",
+			inOpts: []options.Option{
+				options.WithLabels(map[string]string{"some-label": "some-value"}),
+			},
+			wantLabels: map[string]string{
+				"managed-by": "containerz",
+				"some-label": "some-value",
+			},
+		},
+		{
+			name: "user-managed-by-overridden",
+			inOpts: []options.Option{
+				options.WithLabels(map[string]string{
+					"managed-by": "someone-else",
+					"some-label": "some-value",
+				}),
+			},
+			wantLabels: map[string]string{
+				"managed-by": "containerz",
+				"some-label": "some-value",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			mgr := New(fsd)
+
+			if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", tc.inOpts...); err != nil {
+				t.Fatalf("ContainerStart(%+v) returned error: %v", tc.inOpts, err)
+			}
+
+			if diff := cmp.Diff(tc.wantLabels, fsd.rawLabels); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned diff(-want, +got):\n%s", tc.inOpts, diff)
+			}
+		})
+	}
+}
+
+type fakeCachingDocker struct {
+	fakeStartingDocker
+	lists int
+}
+
+func (f *fakeCachingDocker) ImageList(context.Context, image.ListOptions) ([]image.Summary, error) {
+	f.lists++
+	return f.summaries, nil
+}
+
+func (f *fakeCachingDocker) ImageTag(_ context.Context, source, target string) error {
+	for i, summary := range f.summaries {
+		if slices.Contains(summary.RepoTags, source) {
+			f.summaries[i].RepoTags = append(summary.RepoTags, target)
+		}
+	}
+	return nil
+}
+
+func (f *fakeCachingDocker) ImagesPrune(context.Context, filters.Args) (image.PruneReport, error) {
+	f.summaries = nil
+	return image.PruneReport{}, nil
+}
+
+func TestContainerStartImageCache(t *testing.T) {
+	ctx := context.Background()
+	fsd := &fakeCachingDocker{
+		fakeStartingDocker: fakeStartingDocker{
+			summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+		},
+	}
+	mgr := New(fsd)
+
+	start := func(img, tag string, wantLists int) error {
+		t.Helper()
+		_, err := mgr.ContainerStart(ctx, img, tag, "my-cmd")
+		if fsd.lists != wantLists {
+			t.Errorf("ContainerStart(%s:%s) listed images %d times in total, want %d", img, tag, fsd.lists, wantLists)
+		}
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := start("my-image", "my-tag", 1); err != nil {
+			t.Fatalf("ContainerStart() returned error: %v", err)
+		}
+	}
+
+	// Retagging lists the images to find the source, and invalidates the cache.
+	if err := mgr.ImageTag(ctx, "my-image:my-tag", "other-image", "other-tag"); err != nil {
+		t.Fatalf("ImageTag() returned error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := start("other-image", "other-tag", 3); err != nil {
+			t.Fatalf("ContainerStart() returned error: %v", err)
+		}
+	}
+
+	// Pruning removed the image, it must not be reported from the cache.
+	if _, _, err := mgr.ImagePrune(ctx, false); err != nil {
+		t.Fatalf("ImagePrune() returned error: %v", err)
+	}
+	wantErr := detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "my-image:my-tag"}, "image my-image:my-tag not found")
+	if diff := cmp.Diff(wantErr, start("my-image", "my-tag", 4), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+	}
+}
+
+type fakeCancellingDocker struct {
+	fakeStartingDocker
+	cancel    context.CancelFunc
+	startErr  error
+	removeErr error
+
+	Started bool
+	Removed []string
+}
+
+func (f *fakeCancellingDocker) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return container.CreateResponse{ID: "created-id"}, nil
+}
+
+func (f *fakeCancellingDocker) ContainerStart(ctx context.Context, _ string, options container.StartOptions) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.Started = true
+	return nil
+}
+
+func (f *fakeCancellingDocker) ContainerRemove(ctx context.Context, id string, options container.RemoveOptions) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if options.Force {
+		f.Removed = append(f.Removed, id)
+	}
+	return f.removeErr
+}
+
+func TestContainerStartCleanup(t *testing.T) {
+	tests := []struct {
+		name        string
+		inCancel    bool
+		inStartErr  error
+		inRemoveErr error
+		wantState   *fakeCancellingDocker
+		wantErr     error
+	}{
+		{
+			name:      "started",
+			wantState: &fakeCancellingDocker{Started: true},
+		},
+		{
+			name:      "cancelled-after-create",
+			inCancel:  true,
+			wantState: &fakeCancellingDocker{Removed: []string{"created-id"}},
+			wantErr:   status.Error(codes.Canceled, context.Canceled.Error()),
+		},
+		{
+			name:       "start-failed",
+			inStartErr: fmt.Errorf("no such device"),
+			wantState:  &fakeCancellingDocker{Removed: []string{"created-id"}},
+			wantErr:    status.Error(codes.Internal, "unable to start container: no such device"),
+		},
+		{
+			name:        "start-failed-rollback-failed",
+			inStartErr:  fmt.Errorf("no such device"),
+			inRemoveErr: fmt.Errorf("removal in progress"),
+			wantState:   &fakeCancellingDocker{Removed: []string{"created-id"}},
+			wantErr:     status.Error(codes.Internal, "unable to start container: no such device"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			fsd := &fakeCancellingDocker{
+				fakeStartingDocker: fakeStartingDocker{
+					summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				},
+				startErr:  tc.inStartErr,
+				removeErr: tc.inRemoveErr,
+			}
+			if tc.inCancel {
+				fsd.cancel = cancel
+			}
+			mgr := New(fsd)
+
+			if _, err := mgr.ContainerStart(ctx, "my-image", "my-tag", "my-cmd"); err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf("ContainerStart() returned error: %v", err)
+				}
+				if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+					t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+				}
+			} else if tc.wantErr != nil {
+				t.Fatalf("ContainerStart() did not return an error, want %v", tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeCancellingDocker{}), cmpopts.IgnoreFields(fakeCancellingDocker{}, "fakeStartingDocker")); diff != "" {
+				t.Errorf("ContainerStart() returned diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContainerStartPortBindings(t *testing.T) {
+	tests := []struct {
+		name         string
+		inOpts       []options.Option
+		wantBindings nat.PortMap
+		wantErr      error
+	}{
+		{
+			name:   "all-interfaces",
+			inOpts: []options.Option{options.WithPorts(map[uint32]uint32{80: 8080})},
+			wantBindings: nat.PortMap{
+				"80/tcp": {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "::", HostPort: "8080"}},
 			},
 		},
 		{
-			name:    "container-with-no-cmd",
-			inImage: "my-image",
-			inTag:   "my-tag",
-			inSummaries: []image.Summary{{
-				RepoTags: []string{"my-image:my-tag"},
-			}},
-			wantState: &fakeStartingDocker{},
+			name: "host-ip",
+			inOpts: []options.Option{options.WithPortMappings([]options.Port{
+				{Internal: 80, External: 8080, HostIP: "192.0.2.1"},
+				{Internal: 514, External: 514, Protocol: "udp", HostIP: "2001:db8::1"},
+			})},
+			wantBindings: nat.PortMap{
+				"80/tcp":  {{HostIP: "192.0.2.1", HostPort: "8080"}},
+				"514/udp": {{HostIP: "2001:db8::1", HostPort: "514"}},
+			},
+		},
+		{
+			name: "same-port-on-different-host-ips",
+			inOpts: []options.Option{options.WithPortMappings([]options.Port{
+				{Internal: 80, External: 8080, HostIP: "192.0.2.1"},
+				{Internal: 80, External: 8080, HostIP: "192.0.2.2"},
+			})},
+			wantBindings: nat.PortMap{
+				"80/tcp": {{HostIP: "192.0.2.1", HostPort: "8080"}, {HostIP: "192.0.2.2", HostPort: "8080"}},
+			},
+		},
+		{
+			name: "invalid-host-ip",
+			inOpts: []options.Option{options.WithPortMappings([]options.Port{
+				{Internal: 80, External: 8080, HostIP: "mgmt0"},
+			})},
+			wantErr: status.Error(codes.InvalidArgument, `invalid host IP "mgmt0" for port 80`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			mgr := New(fsd)
+
+			if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", tc.inOpts...); err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf("ContainerStart(%+v) returned error: %v", tc.inOpts, err)
+				}
+				if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+					t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerStart(%+v) did not return an error, want %v", tc.inOpts, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantBindings, fsd.bindings); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned diff(-want, +got):\n%s", tc.inOpts, diff)
+			}
+		})
+	}
+}
+
+func TestContainerStartPortConflicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		inPublished []types.Port
+		inPorts     []options.Port
+		wantErr     error
+	}{
+		{
+			name:        "same-port-different-ips",
+			inPublished: []types.Port{{IP: "192.0.2.1", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080, HostIP: "192.0.2.2"}},
+		},
+		{
+			name:        "same-port-same-ip",
+			inPublished: []types.Port{{IP: "192.0.2.1", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080, HostIP: "192.0.2.1"}},
+			wantErr:     detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp", "host_ip": "192.0.2.1"}, "port 8080 already in use on 192.0.2.1"),
+		},
+		{
+			name:        "published-wildcard-requested-specific",
+			inPublished: []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080, HostIP: "192.0.2.1"}},
+			wantErr:     detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp"}, "port 8080 already in use"),
+		},
+		{
+			name:        "published-specific-requested-wildcard",
+			inPublished: []types.Port{{IP: "2001:db8::1", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080}},
+			wantErr:     detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp", "host_ip": "2001:db8::1"}, "port 8080 already in use on 2001:db8::1"),
+		},
+		{
+			name:        "same-ip-different-form",
+			inPublished: []types.Port{{IP: "2001:db8::1", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080, HostIP: "2001:db8:0:0::1"}},
+			wantErr:     detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp", "host_ip": "2001:db8::1"}, "port 8080 already in use on 2001:db8::1"),
+		},
+		{
+			name:        "same-port-different-protocol",
+			inPublished: []types.Port{{IP: "0.0.0.0", PrivatePort: 53, PublicPort: 53, Type: "udp"}},
+			inPorts:     []options.Port{{Internal: 53, External: 53, Protocol: "tcp"}},
+		},
+		{
+			name:        "exposed-but-not-published",
+			inPublished: []types.Port{{PrivatePort: 8080, Type: "tcp"}},
+			inPorts:     []options.Port{{Internal: 80, External: 8080}},
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			fsd := &fakeStartingDocker{
-				summaries: tc.inSummaries,
-				cnts:      tc.inCnts,
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				cnts:      []types.Container{{Names: []string{"/other"}, Ports: tc.inPublished}},
 			}
 			mgr := New(fsd)
 
-			if _, err := mgr.ContainerStart(context.Background(), tc.inImage, tc.inTag, tc.inCmd, tc.inOpts...); err != nil {
-				if tc.wantErr != nil {
-					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
-						t.Errorf("ContainerStart(%q, %q, %q, %+v) returned unexpected error(-want, got):\n %s", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, diff)
-					}
-					return
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithPortMappings(tc.inPorts))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inPorts, diff)
+			}
+		})
+	}
+}
+
+func TestContainerStartPrivileged(t *testing.T) {
+	tests := []struct {
+		name           string
+		inMgrOpts      []Option
+		inOpts         []options.Option
+		wantPrivileged bool
+		wantErr        error
+	}{
+		{
+			name: "unprivileged",
+		},
+		{
+			name:    "privileged-disallowed",
+			inOpts:  []options.Option{options.WithPrivileged(true)},
+			wantErr: status.Error(codes.PermissionDenied, "privileged containers are not allowed by this server"),
+		},
+		{
+			name:           "privileged-allowed",
+			inMgrOpts:      []Option{WithAllowPrivileged()},
+			inOpts:         []options.Option{options.WithPrivileged(true)},
+			wantPrivileged: true,
+		},
+		{
+			name:      "allowed-but-not-requested",
+			inMgrOpts: []Option{WithAllowPrivileged()},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			mgr := New(fsd, tc.inMgrOpts...)
+
+			if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", tc.inOpts...); err != nil {
+				if tc.wantErr == nil {
+					t.Fatalf("ContainerStart(%+v) returned error: %v", tc.inOpts, err)
 				}
-				t.Errorf("ContainerStart(%q, %q, %q, %+v) returned error: %v", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, err)
+				if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+					t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerStart(%+v) did not return an error, want %v", tc.inOpts, tc.wantErr)
 			}
 
-			if tc.wantState != nil {
-				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeStartingDocker{}), cmpopts.EquateEmpty()); diff != "" {
-					t.Errorf("ContainerStart(%q, %q, %q, %+v) returned diff(-want, +got):\n%s", tc.inImage, tc.inTag, tc.inCmd, tc.inOpts, diff)
+			if fsd.Privileged != tc.wantPrivileged {
+				t.Errorf("ContainerStart(%+v) created a container with privileged %t, want %t", tc.inOpts, fsd.Privileged, tc.wantPrivileged)
+			}
+		})
+	}
+}
+
+type fakeHealthDocker struct {
+	fakeStartingDocker
+	// health is the sequence of health states reported by successive inspects, the last repeating.
+	// A nil health reports a container without a health check.
+	health []*types.Health
+
+	Removed  bool
+	Inspects int
+}
+
+func (f *fakeHealthDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	health := f.health[min(f.Inspects, len(f.health)-1)]
+	f.Inspects++
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: &types.ContainerState{Running: true, Health: health},
+		},
+	}, nil
+}
+
+func (f *fakeHealthDocker) ContainerRemove(ctx context.Context, id string, options container.RemoveOptions) error {
+	f.Removed = true
+	return nil
+}
+
+func TestContainerStartWaitHealthy(t *testing.T) {
+	defer func(interval time.Duration) { healthPollInterval = interval }(healthPollInterval)
+	healthPollInterval = 0
+
+	starting := &types.Health{Status: types.Starting}
+	healthy := &types.Health{Status: types.Healthy}
+	unhealthy := &types.Health{Status: types.Unhealthy}
+
+	tests := []struct {
+		name         string
+		inHealth     []*types.Health
+		inOpts       []options.Option
+		wantName     string
+		wantInspects int
+		wantErr      error
+	}{
+		{
+			name:     "no-wait",
+			inHealth: []*types.Health{starting},
+			wantName: "my-name",
+		},
+		{
+			name:         "becomes-healthy",
+			inHealth:     []*types.Health{starting, starting, healthy},
+			inOpts:       []options.Option{options.WithWaitHealthy(time.Minute)},
+			wantName:     "my-name",
+			wantInspects: 3,
+		},
+		{
+			name:     "never-healthy",
+			inHealth: []*types.Health{starting},
+			inOpts:   []options.Option{options.WithWaitHealthy(10 * time.Millisecond)},
+			wantName: "my-name",
+			wantErr:  status.Error(codes.DeadlineExceeded, "container my-name did not become healthy within 10ms"),
+		},
+		{
+			name:         "unhealthy",
+			inHealth:     []*types.Health{starting, unhealthy},
+			inOpts:       []options.Option{options.WithWaitHealthy(time.Minute)},
+			wantName:     "my-name",
+			wantInspects: 2,
+			wantErr:      status.Error(codes.Unavailable, "container my-name is unhealthy"),
+		},
+		{
+			name:         "no-health-check",
+			inHealth:     []*types.Health{nil},
+			inOpts:       []options.Option{options.WithWaitHealthy(time.Minute)},
+			wantName:     "my-name",
+			wantInspects: 1,
+			wantErr:      status.Error(codes.FailedPrecondition, "container my-name has no health check configured"),
+		},
+		{
+			name:     "health-check-disabled",
+			inHealth: []*types.Health{starting},
+			inOpts: []options.Option{
+				options.WithWaitHealthy(time.Minute),
+				options.WithHealthCheck("", 0, 0, 0, 0),
+			},
+			wantErr: status.Error(codes.FailedPrecondition, "cannot wait for a container to become healthy with its health check disabled"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fhd := &fakeHealthDocker{
+				fakeStartingDocker: fakeStartingDocker{
+					summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				},
+				health: tc.inHealth,
+			}
+			mgr := New(fhd)
+
+			opts := append([]options.Option{options.WithInstanceName("my-name")}, tc.inOpts...)
+			name, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+			}
+			if name != tc.wantName {
+				t.Errorf("ContainerStart(%+v) returned name %q, want %q", tc.inOpts, name, tc.wantName)
+			}
+			if tc.wantInspects != 0 && fhd.Inspects != tc.wantInspects {
+				t.Errorf("ContainerStart(%+v) inspected the container %d times, want %d", tc.inOpts, fhd.Inspects, tc.wantInspects)
+			}
+			if fhd.Removed {
+				t.Errorf("ContainerStart(%+v) removed the container, want it left running", tc.inOpts)
+			}
+		})
+	}
+}
+
+type fakeAddressedDocker struct {
+	fakeStartingDocker
+	networks   map[string]*network.EndpointSettings
+	inspectErr error
+}
+
+func (f *fakeAddressedDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	if f.inspectErr != nil {
+		return types.ContainerJSON{}, f.inspectErr
+	}
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: id},
+		NetworkSettings:   &types.NetworkSettings{Networks: f.networks},
+	}, nil
+}
+
+func TestContainerStartAssignedAddresses(t *testing.T) {
+	tests := []struct {
+		name       string
+		inNetworks map[string]*network.EndpointSettings
+		inErr      error
+		wantAddrs  map[string][]string
+		wantErr    error
+	}{
+		{
+			name: "networks",
+			inNetworks: map[string]*network.EndpointSettings{
+				"bridge":  {IPAddress: "172.17.0.2"},
+				"mgmt":    {IPAddress: "10.0.0.5", GlobalIPv6Address: "2001:db8::5"},
+				"v6-only": {GlobalIPv6Address: "2001:db8::6"},
+			},
+			wantAddrs: map[string][]string{
+				"bridge":  {"172.17.0.2"},
+				"mgmt":    {"10.0.0.5", "2001:db8::5"},
+				"v6-only": {"2001:db8::6"},
+			},
+		},
+		{
+			name:       "host-network",
+			inNetworks: map[string]*network.EndpointSettings{"host": {}},
+			wantAddrs:  map[string][]string{},
+		},
+		{
+			name:      "inspect-failure",
+			inErr:     fmt.Errorf("daemon busy"),
+			wantAddrs: map[string][]string{},
+			wantErr:   status.Error(codes.Internal, "container my-name started but its addresses could not be inspected: daemon busy"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fad := &fakeAddressedDocker{
+				fakeStartingDocker: fakeStartingDocker{
+					summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				},
+				networks:   tc.inNetworks,
+				inspectErr: tc.inErr,
+			}
+			mgr := New(fad)
+
+			addrs := map[string][]string{}
+			name, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("my-name"), options.WithAssignedAddresses(addrs))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if name != "my-name" {
+				t.Errorf("ContainerStart() returned name %q, want %q", name, "my-name")
+			}
+			if diff := cmp.Diff(tc.wantAddrs, addrs); diff != "" {
+				t.Errorf("ContainerStart() assigned addresses diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type fakeCapacityDocker struct {
+	fakeStartingDocker
+	info    system.Info
+	infoErr error
+}
+
+func (f *fakeCapacityDocker) Info(ctx context.Context) (system.Info, error) {
+	return f.info, f.infoErr
+}
+
+func TestContainerStartCapacityCheck(t *testing.T) {
+	small := system.Info{NCPU: 2, MemTotal: 1 << 30}
+
+	tests := []struct {
+		name      string
+		inMgrOpts []Option
+		inInfo    system.Info
+		inInfoErr error
+		inOpts    []options.Option
+		wantErr   error
+	}{
+		{
+			name:   "unchecked",
+			inInfo: small,
+			inOpts: []options.Option{options.WithCPUs(8), options.WithHardLimit(4 << 30)},
+		},
+		{
+			name:      "within-capacity",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inInfo:    small,
+			inOpts:    []options.Option{options.WithCPUs(2), options.WithHardLimit(1 << 30)},
+		},
+		{
+			name:      "too-many-cpus",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inInfo:    small,
+			inOpts:    []options.Option{options.WithCPUs(2.5)},
+			wantErr:   status.Error(codes.ResourceExhausted, "requested 2.5 cpus exceeds the 2 cpus of the host"),
+		},
+		{
+			name:      "too-much-memory",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inInfo:    small,
+			inOpts:    []options.Option{options.WithHardLimit(4 << 30)},
+			wantErr:   status.Error(codes.ResourceExhausted, "requested memory limit of 4294967296 bytes exceeds the 1073741824 bytes of the host"),
+		},
+		{
+			name:      "capacity-unreported",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inOpts:    []options.Option{options.WithCPUs(8), options.WithHardLimit(4 << 30)},
+		},
+		{
+			name:      "no-limits",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inInfoErr: fmt.Errorf("not implemented"),
+		},
+		{
+			name:      "info-unavailable",
+			inMgrOpts: []Option{WithCapacityCheck()},
+			inInfoErr: fmt.Errorf("connection refused"),
+			inOpts:    []options.Option{options.WithCPUs(1)},
+			wantErr:   status.Error(codes.Unavailable, "unable to query host capacity: connection refused"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fcd := &fakeCapacityDocker{
+				fakeStartingDocker: fakeStartingDocker{
+					summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				},
+				info:    tc.inInfo,
+				infoErr: tc.inInfoErr,
+			}
+			mgr := New(fcd, tc.inMgrOpts...)
+
+			opts := append([]options.Option{options.WithInstanceName("my-name")}, tc.inOpts...)
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+			}
+			if started := fcd.ContainerID != ""; started != (tc.wantErr == nil) {
+				t.Errorf("ContainerStart(%+v) started a container: %t, want %t", tc.inOpts, started, tc.wantErr == nil)
+			}
+		})
+	}
+}
+
+type fakePullingStartDocker struct {
+	fakeStartingDocker
+	pullErr error
+
+	Pulls        int
+	PullPlatform string
+}
+
+func (f *fakePullingStartDocker) ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
+	f.Pulls++
+	f.PullPlatform = options.Platform
+	if f.pullErr != nil {
+		return nil, f.pullErr
+	}
+	if !slices.ContainsFunc(f.summaries, func(s image.Summary) bool { return slices.Contains(s.RepoTags, ref) }) {
+		f.summaries = append(f.summaries, image.Summary{RepoTags: []string{ref}})
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestContainerStartPullPolicy(t *testing.T) {
+	present := []image.Summary{{RepoTags: []string{"my-image:my-tag"}}}
+
+	tests := []struct {
+		name        string
+		inSummaries []image.Summary
+		inPolicy    options.PullPolicy
+		inPullErr   error
+		wantPulls   int
+		wantErr     error
+	}{
+		{
+			name:        "default-present",
+			inSummaries: present,
+		},
+		{
+			name:    "default-missing",
+			wantErr: detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "my-image:my-tag"}, "image my-image:my-tag not found"),
+		},
+		{
+			name:        "never-present",
+			inSummaries: present,
+			inPolicy:    options.PullNever,
+		},
+		{
+			name:     "never-missing",
+			inPolicy: options.PullNever,
+			wantErr:  detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "my-image:my-tag"}, "image my-image:my-tag not found"),
+		},
+		{
+			name:        "if-not-present-present",
+			inSummaries: present,
+			inPolicy:    options.PullIfNotPresent,
+		},
+		{
+			name:      "if-not-present-missing",
+			inPolicy:  options.PullIfNotPresent,
+			wantPulls: 1,
+		},
+		{
+			name:      "if-not-present-pull-failed",
+			inPolicy:  options.PullIfNotPresent,
+			inPullErr: fmt.Errorf("registry unreachable"),
+			wantPulls: 1,
+			wantErr:   status.Error(codes.Internal, "unable to pull container: registry unreachable"),
+		},
+		{
+			name:        "always-present",
+			inSummaries: present,
+			inPolicy:    options.PullAlways,
+			wantPulls:   1,
+		},
+		{
+			name:      "always-missing",
+			inPolicy:  options.PullAlways,
+			wantPulls: 1,
+		},
+		{
+			name:        "always-pull-failed-present",
+			inSummaries: present,
+			inPolicy:    options.PullAlways,
+			inPullErr:   fmt.Errorf("registry unreachable"),
+			wantPulls:   1,
+			wantErr:     status.Error(codes.Internal, "unable to pull container: registry unreachable"),
+		},
+		{
+			name:        "unknown",
+			inSummaries: present,
+			inPolicy:    options.PullPolicy("Sometimes"),
+			wantErr:     status.Error(codes.InvalidArgument, `unknown image pull policy "Sometimes"`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fpd := &fakePullingStartDocker{
+				fakeStartingDocker: fakeStartingDocker{summaries: tc.inSummaries},
+				pullErr:            tc.inPullErr,
+			}
+			mgr := New(fpd)
+
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("my-name"), options.WithPullPolicy(tc.inPolicy))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%q) returned unexpected error(-want, got):\n %s", tc.inPolicy, diff)
+			}
+			if fpd.Pulls != tc.wantPulls {
+				t.Errorf("ContainerStart(%q) pulled the image %d times, want %d", tc.inPolicy, fpd.Pulls, tc.wantPulls)
+			}
+			if started := fpd.ContainerID != ""; started != (tc.wantErr == nil) {
+				t.Errorf("ContainerStart(%q) started a container: %t, want %t", tc.inPolicy, started, tc.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestContainerStartPlatformPull(t *testing.T) {
+	fpd := &fakePullingStartDocker{}
+	mgr := New(fpd)
+
+	if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("my-name"), options.WithPullPolicy(options.PullAlways), options.WithPlatform("linux", "arm", "v7")); err != nil {
+		t.Fatalf("ContainerStart() returned error: %v", err)
+	}
+	if want := "linux/arm/v7"; fpd.PullPlatform != want {
+		t.Errorf("ContainerStart() pulled platform %q, want %q", fpd.PullPlatform, want)
+	}
+	want := &ocispec.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	if diff := cmp.Diff(want, fpd.Platform); diff != "" {
+		t.Errorf("ContainerStart() created container with platform diff (-want, +got):\n%s", diff)
+	}
+}
+
+type fakeDryRunDocker struct {
+	fakeStartingDocker
+
+	Creates int
+	Starts  int
+	Pulls   int
+}
+
+func (f *fakeDryRunDocker) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	f.Creates++
+	return container.CreateResponse{ID: containerName}, nil
+}
+
+func (f *fakeDryRunDocker) ContainerStart(ctx context.Context, container string, options container.StartOptions) error {
+	f.Starts++
+	return nil
+}
+
+func (f *fakeDryRunDocker) ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
+	f.Pulls++
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func TestContainerStartDryRun(t *testing.T) {
+	present := []image.Summary{{RepoTags: []string{"my-image:my-tag"}}}
+
+	tests := []struct {
+		name        string
+		inSummaries []image.Summary
+		inCnts      []types.Container
+		inOpts      []options.Option
+		// wantConfig and wantHostConfig are the planned fields to check.
+		wantConfig     map[string]any
+		wantHostConfig map[string]any
+		wantErr        error
+	}{
+		{
+			name:        "valid",
+			inSummaries: present,
+			inOpts: []options.Option{
+				options.WithInstanceName("my-name"),
+				options.WithHardLimit(1 << 20),
+				options.WithPortMappings([]options.Port{{Internal: 80, External: 8080, HostIP: "127.0.0.1"}}),
+			},
+			wantConfig: map[string]any{
+				"Image": "my-image:my-tag",
+				"Cmd":   []any{"my-cmd"},
+			},
+			wantHostConfig: map[string]any{
+				"Memory": float64(1 << 20),
+				"PortBindings": map[string]any{
+					"80/tcp": []any{map[string]any{"HostIp": "127.0.0.1", "HostPort": "8080"}},
+				},
+			},
+		},
+		{
+			name:        "pull-always",
+			inSummaries: present,
+			inOpts:      []options.Option{options.WithPullPolicy(options.PullAlways)},
+			wantConfig: map[string]any{
+				"Image": "my-image:my-tag",
+				"Cmd":   []any{"my-cmd"},
+			},
+		},
+		{
+			name:    "image-missing",
+			inOpts:  []options.Option{options.WithPullPolicy(options.PullIfNotPresent)},
+			wantErr: detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "my-image:my-tag"}, "image my-image:my-tag not found"),
+		},
+		{
+			name:        "port-in-use",
+			inSummaries: present,
+			inCnts:      []types.Container{{Ports: []types.Port{{PublicPort: 8080}}}},
+			inOpts:      []options.Option{options.WithPorts(map[uint32]uint32{80: 8080})},
+			wantErr:     detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp"}, "port 8080 already in use"),
+		},
+		{
+			name:        "invalid-option",
+			inSummaries: present,
+			inOpts:      []options.Option{options.WithOOMScoreAdj(2000)},
+			wantErr:     status.Error(codes.InvalidArgument, "invalid oom score adjustment 2000: must be between -1000 and 1000"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fdd := &fakeDryRunDocker{
+				fakeStartingDocker: fakeStartingDocker{summaries: tc.inSummaries, cnts: tc.inCnts},
+			}
+			mgr := New(fdd)
+
+			plan := &structpb.Struct{}
+			opts := append([]options.Option{options.WithDryRun(plan)}, tc.inOpts...)
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if fdd.Creates != 0 || fdd.Starts != 0 || fdd.Pulls != 0 {
+				t.Errorf("ContainerStart() created %d, started %d and pulled %d times in a dry run, want none", fdd.Creates, fdd.Starts, fdd.Pulls)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			got := plan.AsMap()
+			config, _ := got["Config"].(map[string]any)
+			hostConfig, _ := got["HostConfig"].(map[string]any)
+			for key, want := range tc.wantConfig {
+				if diff := cmp.Diff(want, config[key]); diff != "" {
+					t.Errorf("ContainerStart() planned Config.%s diff(-want, +got):\n%s", key, diff)
+				}
+			}
+			for key, want := range tc.wantHostConfig {
+				if diff := cmp.Diff(want, hostConfig[key]); diff != "" {
+					t.Errorf("ContainerStart() planned HostConfig.%s diff(-want, +got):\n%s", key, diff)
 				}
 			}
 		})
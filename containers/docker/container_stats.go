@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/docker/docker/api/types/container"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ContainerStats returns a snapshot of the resource usage of a running container. The CPU usage
+// is computed over two consecutive samples; if only a single sample is available the CPU usage
+// is reported as zero.
+func (m *Manager) ContainerStats(ctx context.Context, instance string) (*options.ContainerStats, error) {
+	state, err := m.containerState(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if !state.Running {
+		return nil, status.Errorf(codes.FailedPrecondition, "container %s is not running", instance)
+	}
+
+	resp, err := m.client.ContainerStats(ctx, m.containerName(instance), true)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to fetch stats for container %s: %v", instance, err)
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	var sample container.StatsResponse
+	if err := dec.Decode(&sample); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to decode stats for container %s: %v", instance, err)
+	}
+
+	// The first sample returned by docker may not carry any previous CPU usage, in which case
+	// wait for the next one.
+	prev := sample.PreCPUStats
+	if prev.SystemUsage == 0 {
+		var next container.StatsResponse
+		switch err := dec.Decode(&next); err {
+		case nil:
+			prev = sample.CPUStats
+			sample = next
+		case io.EOF:
+		default:
+			return nil, status.Errorf(codes.Internal, "unable to decode stats for container %s: %v", instance, err)
+		}
+	}
+
+	stats := &options.ContainerStats{
+		CPUPercent:  cpuPercent(prev, sample.CPUStats),
+		MemoryUsage: memoryUsage(sample.MemoryStats),
+		MemoryLimit: sample.MemoryStats.Limit,
+	}
+	for _, nw := range sample.Networks {
+		stats.NetworkRxBytes += nw.RxBytes
+		stats.NetworkTxBytes += nw.TxBytes
+	}
+
+	return stats, nil
+}
+
+// cpuPercent computes the CPU usage between two samples. A zero value is returned if there is
+// no usable previous sample.
+func cpuPercent(prev, cur container.CPUStats) float64 {
+	if prev.SystemUsage == 0 || cur.SystemUsage <= prev.SystemUsage || cur.CPUUsage.TotalUsage < prev.CPUUsage.TotalUsage {
+		return 0
+	}
+
+	cpus := float64(cur.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(cur.CPUUsage.PercpuUsage))
+	}
+
+	cpuDelta := float64(cur.CPUUsage.TotalUsage - prev.CPUUsage.TotalUsage)
+	systemDelta := float64(cur.SystemUsage - prev.SystemUsage)
+	return cpuDelta / systemDelta * cpus * 100.0
+}
+
+// memoryUsage returns the memory used by the container excluding the inactive page cache, as
+// is done by the docker CLI.
+func memoryUsage(mem container.MemoryStats) uint64 {
+	// cgroup v2 reports inactive_file, cgroup v1 reports total_inactive_file.
+	for _, key := range []string{"inactive_file", "total_inactive_file"} {
+		if v, ok := mem.Stats[key]; ok && v < mem.Usage {
+			return mem.Usage - v
+		}
+	}
+	return mem.Usage
+}
@@ -0,0 +1,180 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeStatsDocker struct {
+	fakeDocker
+	cnts    []types.Container
+	running bool
+	samples []container.StatsResponse
+
+	Instance string
+	Stream   bool
+}
+
+func (f fakeStatsDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f fakeStatsDocker) ContainerInspect(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:    id,
+			State: &types.ContainerState{Running: f.running},
+		},
+	}, nil
+}
+
+func (f *fakeStatsDocker) ContainerStats(ctx context.Context, id string, stream bool) (container.StatsResponseReader, error) {
+	f.Instance = id
+	f.Stream = stream
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, sample := range f.samples {
+		if err := enc.Encode(sample); err != nil {
+			return container.StatsResponseReader{}, err
+		}
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(&buf)}, nil
+}
+
+func TestContainerStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		inInstance string
+		inCnts     []types.Container
+		inRunning  bool
+		inSamples  []container.StatsResponse
+		wantStats  *options.ContainerStats
+		wantErr    error
+	}{
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			wantErr:    status.Errorf(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:       "stopped-instance",
+			inInstance: "stopped-instance",
+			inCnts: []types.Container{
+				{
+					ID:    "stopped-instance",
+					Names: []string{"/stopped-instance"},
+				},
+			},
+			wantErr: status.Errorf(codes.FailedPrecondition, "container stopped-instance is not running"),
+		},
+		{
+			name:       "single-sample",
+			inInstance: "my-instance",
+			inCnts: []types.Container{
+				{
+					ID:    "my-instance",
+					Names: []string{"/my-instance"},
+				},
+			},
+			inRunning: true,
+			inSamples: []container.StatsResponse{
+				{
+					CPUStats: container.CPUStats{
+						CPUUsage:    container.CPUUsage{TotalUsage: 100},
+						SystemUsage: 1000,
+						OnlineCPUs:  2,
+					},
+					MemoryStats: container.MemoryStats{
+						Usage: 2048,
+						Limit: 4096,
+						Stats: map[string]uint64{"inactive_file": 1024},
+					},
+					Networks: map[string]container.NetworkStats{
+						"eth0": {RxBytes: 10, TxBytes: 20},
+						"eth1": {RxBytes: 1, TxBytes: 2},
+					},
+				},
+			},
+			wantStats: &options.ContainerStats{
+				MemoryUsage:    1024,
+				MemoryLimit:    4096,
+				NetworkRxBytes: 11,
+				NetworkTxBytes: 22,
+			},
+		},
+		{
+			name:       "two-samples",
+			inInstance: "my-instance",
+			inCnts: []types.Container{
+				{
+					ID:    "my-instance",
+					Names: []string{"/my-instance"},
+				},
+			},
+			inRunning: true,
+			inSamples: []container.StatsResponse{
+				{
+					CPUStats: container.CPUStats{
+						CPUUsage:    container.CPUUsage{TotalUsage: 100},
+						SystemUsage: 1000,
+						OnlineCPUs:  2,
+					},
+				},
+				{
+					CPUStats: container.CPUStats{
+						CPUUsage:    container.CPUUsage{TotalUsage: 200},
+						SystemUsage: 2000,
+						OnlineCPUs:  2,
+					},
+					MemoryStats: container.MemoryStats{
+						Usage: 2048,
+						Limit: 4096,
+					},
+				},
+			},
+			wantStats: &options.ContainerStats{
+				CPUPercent:  20,
+				MemoryUsage: 2048,
+				MemoryLimit: 4096,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStatsDocker{
+				cnts:    tc.inCnts,
+				running: tc.inRunning,
+				samples: tc.inSamples,
+			}
+			mgr := New(fsd)
+
+			got, err := mgr.ContainerStats(context.Background(), tc.inInstance)
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerStats(%q) returned unexpected error(-want, got):\n %s", tc.inInstance, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerStats(%q) returned error: %v", tc.inInstance, err)
+			}
+
+			if diff := cmp.Diff(tc.wantStats, got); diff != "" {
+				t.Errorf("ContainerStats(%q) returned diff(-want, +got):\n%s", tc.inInstance, diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
@@ -16,15 +17,39 @@ import (
 // 10 (seconds) is chosen as this is the default timeout used by container.StopOptions
 const maximumStopTimeout = 10
 
+// stopSignals is the set of signals that may be used to stop a container.
+var stopSignals = map[string]bool{
+	"SIGHUP":  true,
+	"SIGINT":  true,
+	"SIGQUIT": true,
+	"SIGKILL": true,
+	"SIGUSR1": true,
+	"SIGUSR2": true,
+	"SIGTERM": true,
+}
+
 // ContainerStop stops a container. If the Force option is set and a timeout
 // is specified in the context, the contains if forcefully terminated after that timeout.
 // If the Force option is set but no timeout is provided the container's StopTimeout
 // value is used, if set, otherwise the engine default.
 // If the Force option is not set, no forceful termination is performed.
-func (m *Manager) ContainerStop(ctx context.Context, instance string, opts ...options.Option) error {
+// A stop grace period, if provided, overrides the above and the container is killed once it
+// elapses. A stop signal may also be provided to replace the engine default (SIGTERM).
+func (m *Manager) ContainerStop(ctx context.Context, instance string, opts ...options.Option) (err error) {
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationContainerStop, start, instance, "", err) }()
+
 	optionz := options.ApplyOptions(opts...)
 
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{
+	signal, err := stopSignal(optionz.StopSignal)
+	if err != nil {
+		return err
+	}
+	if optionz.StopGracePeriod < 0 {
+		return status.Errorf(codes.InvalidArgument, "stop grace period can not be negative, got %d", optionz.StopGracePeriod)
+	}
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{
 		// TODO(alshabib): consider filtering for the image we care about
 	})
 	if err != nil {
@@ -33,7 +58,7 @@ func (m *Manager) ContainerStop(ctx context.Context, instance string, opts ...op
 
 	// check if the container exists.
 	if err := checkExistingInstanceAndPorts(instance, nil, cnts); err == nil {
-		return status.Errorf(codes.NotFound, "container %s was not found", instance)
+		return detailedError(codes.NotFound, reasonContainerNotFound, map[string]string{"instance": instance}, "container %s was not found", instance)
 	}
 
 	// a negative timeout indicates to docker that no forceful termination should
@@ -52,16 +77,37 @@ func (m *Manager) ContainerStop(ctx context.Context, instance string, opts ...op
 		}
 	}
 
+	if optionz.StopGracePeriod > 0 {
+		duration = optionz.StopGracePeriod
+	}
+
 	pDuration := &duration
 	if duration == 0 {
 		pDuration = nil
 	}
 
-	if err := m.client.ContainerStop(ctx, instance, container.StopOptions{Timeout: pDuration}); err != nil {
+	if err := m.client.ContainerStop(ctx, m.containerName(instance), container.StopOptions{Signal: signal, Timeout: pDuration}); err != nil {
 		klog.Warningf("container %s failed to stop", instance)
-		return status.Errorf(codes.Unknown, "failed to stop container %s with error %s",
-			instance, err)
+		return contextError(ctx, status.Errorf(codes.Unknown, "failed to stop container %s with error %s",
+			instance, err))
 	}
 
 	return nil
 }
+
+// stopSignal normalizes the provided signal name (e.g. "term" becomes "SIGTERM") and ensures that
+// it is one of the supported stop signals. An empty signal leaves the engine default in place.
+func stopSignal(signal string) (string, error) {
+	if signal == "" {
+		return "", nil
+	}
+
+	sig := strings.ToUpper(signal)
+	if !strings.HasPrefix(sig, "SIG") {
+		sig = "SIG" + sig
+	}
+	if !stopSignals[sig] {
+		return "", status.Errorf(codes.InvalidArgument, "unknown stop signal %q", signal)
+	}
+	return sig, nil
+}
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStopAllConcurrency is the number of containers stopped in parallel by StopAll, unless
+// overridden with options.WithConcurrency.
+const defaultStopAllConcurrency = 8
+
+// StopAll stops every container managed by containerz, in parallel. The containers share a
+// single deadline, timeout from now, by which they must stop gracefully before being killed. The
+// result of each stop is returned, ordered by instance name; a container that was not running is
+// reported as stopped, and a container failing to stop does not prevent the others stopping.
+func (m *Manager) StopAll(ctx context.Context, timeout time.Duration, opts ...options.Option) ([]*options.StopResult, error) {
+	if timeout <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "stop timeout must be positive, got %s", timeout)
+	}
+	optionz := options.ApplyOptions(opts...)
+	deadline := time.Now().Add(timeout)
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", managedByLabel, managedByValue))),
+	})
+	if err != nil {
+		return nil, contextError(ctx, status.Errorf(codes.Internal, "unable to list containers: %v", err))
+	}
+	if m.namePrefix != "" {
+		// Containers left without a name are those outside of the prefix.
+		cnts = slices.DeleteFunc(cnts, func(cnt types.Container) bool { return len(cnt.Names) == 0 })
+	}
+
+	concurrency := optionz.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultStopAllConcurrency
+	}
+
+	results := make([]*options.StopResult, len(cnts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, cnt := range cnts {
+		instance := instanceName(cnt)
+		results[i] = &options.StopResult{Instance: instance}
+		if cnt.State != "running" && cnt.State != "paused" && cnt.State != "restarting" {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Err = m.stopBy(ctx, cnt.ID, instance, deadline)
+		}()
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Instance < results[j].Instance })
+	return results, nil
+}
+
+// stopBy stops the container identified by id, killing it if it has not stopped by deadline. A
+// container that no longer exists is considered stopped.
+func (m *Manager) stopBy(ctx context.Context, id, instance string, deadline time.Time) (err error) {
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationContainerStop, start, instance, "", err) }()
+
+	// Containers that only start stopping once others have stopped get the time that remains.
+	grace := max(int(time.Until(deadline)/time.Second), 0)
+	if err := m.client.ContainerStop(ctx, id, container.StopOptions{Timeout: &grace}); err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return contextError(ctx, status.Errorf(codes.Unknown, "failed to stop container %s with error %s", instance, err))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeStoppingAllDocker struct {
+	fakeDocker
+	cnts     []types.Container
+	stopErrs map[string]error
+
+	mu      sync.Mutex
+	Stopped []string
+	Graces  []int
+	Label   string
+}
+
+func (f *fakeStoppingAllDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	f.Label = options.Filters.Get("label")[0]
+	return f.cnts, nil
+}
+
+func (f *fakeStoppingAllDocker) ContainerStop(ctx context.Context, id string, options container.StopOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Stopped = append(f.Stopped, id)
+	if options.Timeout != nil {
+		f.Graces = append(f.Graces, *options.Timeout)
+	}
+	return f.stopErrs[id]
+}
+
+func TestStopAll(t *testing.T) {
+	fsd := &fakeStoppingAllDocker{
+		cnts: []types.Container{
+			{ID: "c-id", Names: []string{"/c"}, State: "running"},
+			{ID: "a-id", Names: []string{"/a"}, State: "running"},
+			{ID: "b-id", Names: []string{"/b"}, State: "exited"},
+			{ID: "d-id", Names: []string{"/d"}, State: "paused"},
+			{ID: "e-id", Names: []string{"/e"}, State: "running"},
+		},
+		stopErrs: map[string]error{
+			"c-id": fmt.Errorf("daemon timed out"),
+			"e-id": errdefs.NotFound(fmt.Errorf("no such container")),
+		},
+	}
+	mgr := New(fsd)
+
+	got, err := mgr.StopAll(context.Background(), time.Minute, options.WithConcurrency(2))
+	if err != nil {
+		t.Fatalf("StopAll() returned error: %v", err)
+	}
+
+	want := []*options.StopResult{
+		{Instance: "a"},
+		{Instance: "b"},
+		{Instance: "c", Err: status.Error(codes.Unknown, "failed to stop container c with error daemon timed out")},
+		{Instance: "d"},
+		{Instance: "e"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("StopAll() returned diff(-want, +got):\n%s", diff)
+	}
+
+	sort.Strings(fsd.Stopped)
+	if diff := cmp.Diff([]string{"a-id", "c-id", "d-id", "e-id"}, fsd.Stopped); diff != "" {
+		t.Errorf("StopAll() stopped diff(-want, +got):\n%s", diff)
+	}
+	for _, grace := range fsd.Graces {
+		if grace <= 0 || grace > 60 {
+			t.Errorf("StopAll() stopped a container with a grace period of %ds, want at most 60s", grace)
+		}
+	}
+	if want := managedByLabel + "=" + managedByValue; fsd.Label != want {
+		t.Errorf("StopAll() listed containers with label %q, want %q", fsd.Label, want)
+	}
+}
+
+func TestStopAllInvalidTimeout(t *testing.T) {
+	mgr := New(&fakeStoppingAllDocker{})
+
+	_, err := mgr.StopAll(context.Background(), 0)
+	if diff := cmp.Diff(status.Error(codes.InvalidArgument, "stop timeout must be positive, got 0s"), err, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("StopAll() returned unexpected error(-want, got):\n %s", diff)
+	}
+}
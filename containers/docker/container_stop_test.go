@@ -20,11 +20,13 @@ type fakeStoppingDocker struct {
 
 	Instance       string
 	Duration       int
+	Signal         string
 	RemoveInstance string
 }
 
 func (f *fakeStoppingDocker) ContainerStop(ctx context.Context, container string, options container.StopOptions) error {
 	f.Instance = container
+	f.Signal = options.Signal
 
 	if options.Timeout != nil {
 		f.Duration = *options.Timeout
@@ -54,7 +56,7 @@ func TestContainerStop(t *testing.T) {
 		{
 			name:       "no-such-instance",
 			inInstance: "no-such-instance",
-			wantErr:    status.Errorf(codes.NotFound, "container no-such-instance was not found"),
+			wantErr:    detailedError(codes.NotFound, reasonContainerNotFound, map[string]string{"instance": "no-such-instance"}, "container no-such-instance was not found"),
 		},
 		{
 			name:       "stop-no-force",
@@ -97,6 +99,72 @@ func TestContainerStop(t *testing.T) {
 				Duration: maximumStopTimeout,
 			},
 		},
+		{
+			name:       "stop-with-grace-period",
+			inInstance: "stop-with-grace-period",
+			inOpts:     []options.Option{options.WithStopGracePeriod(30)},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"stop-with-grace-period"},
+				},
+			},
+			wantState: &fakeStoppingDocker{
+				Instance: "stop-with-grace-period",
+				Duration: 30,
+			},
+		},
+		{
+			name:       "stop-with-force-and-grace-period",
+			inInstance: "stop-with-force-and-grace-period",
+			inTimeout:  1 * time.Minute,
+			inOpts:     []options.Option{options.Force(), options.WithStopGracePeriod(5)},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"stop-with-force-and-grace-period"},
+				},
+			},
+			wantState: &fakeStoppingDocker{
+				Instance: "stop-with-force-and-grace-period",
+				Duration: 5,
+			},
+		},
+		{
+			name:       "stop-with-signal",
+			inInstance: "stop-with-signal",
+			inOpts:     []options.Option{options.WithStopSignal("int"), options.WithStopGracePeriod(30)},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"stop-with-signal"},
+				},
+			},
+			wantState: &fakeStoppingDocker{
+				Instance: "stop-with-signal",
+				Duration: 30,
+				Signal:   "SIGINT",
+			},
+		},
+		{
+			name:       "stop-with-unknown-signal",
+			inInstance: "stop-with-unknown-signal",
+			inOpts:     []options.Option{options.WithStopSignal("SIGBOGUS")},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"stop-with-unknown-signal"},
+				},
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "unknown stop signal \"SIGBOGUS\""),
+		},
+		{
+			name:       "stop-with-negative-grace-period",
+			inInstance: "stop-with-negative-grace-period",
+			inOpts:     []options.Option{options.WithStopGracePeriod(-1)},
+			inCnts: []types.Container{
+				types.Container{
+					Names: []string{"stop-with-negative-grace-period"},
+				},
+			},
+			wantErr: status.Errorf(codes.InvalidArgument, "stop grace period can not be negative, got -1"),
+		},
 	}
 
 	for _, tc := range tests {
@@ -35,7 +35,7 @@ func (m *Manager) jsonState(ctx context.Context, instance string, cnts []types.C
 	}
 
 	// Fetch instance configs.
-	cntJSON, err := m.client.ContainerInspect(ctx, cntID)
+	cntJSON, err := m.inspectContainer(ctx, cntID)
 	if err != nil {
 		return types.ContainerJSON{}, status.Errorf(codes.Unknown, "failed to inspect container %s: %v", cntID, err)
 	}
@@ -62,20 +62,24 @@ func (m *Manager) performContainerUpdate(ctx context.Context, instance, image, t
 		return "", status.Errorf(codes.Internal, "failed update of instance %s due to: %v", instance, err)
 	}
 	// ContainerStop will stop the container - we want to additionally remove this instance here.
-	if err := m.client.ContainerRemove(ctx, instance, container.RemoveOptions{}); err != nil {
+	if err := m.client.ContainerRemove(ctx, m.containerName(instance), container.RemoveOptions{}); err != nil {
 		return "", status.Errorf(codes.Internal, "failed update of instance %s due to: %v", instance, err)
 	}
 
 	// Attempting to create & start a container with the new config.
 	opts = append(opts, options.WithInstanceName(instance))
 	if _, err = m.ContainerStart(ctx, image, tag, cmd, opts...); err == nil { // if NO error
+		// The secrets of the old container are only needed until it can no longer be restored.
+		if oldCntJSON.Config != nil {
+			m.removeSecrets(oldCntJSON.Config.Labels[secretsLabel])
+		}
 		return instance, nil
 	}
 
 	// There was some error, let's try to restore previous state.
 	errPfx := fmt.Sprintf("failed to update instance %s due to: %v", instance, err)
 
-	resp, err := m.client.ContainerCreate(ctx, oldCntJSON.Config, oldCntJSON.HostConfig, &network.NetworkingConfig{}, nil, instance)
+	resp, err := m.createContainer(ctx, oldCntJSON.Config, oldCntJSON.HostConfig, &network.NetworkingConfig{}, nil, instance)
 	if err != nil {
 		return "", status.Errorf(codes.Internal, "%s; restoration of previous state failed when creating container: %v", errPfx, err)
 	}
@@ -91,11 +95,11 @@ func (m *Manager) performContainerUpdatePrechecks(ctx context.Context, instance,
 	optionz := options.ApplyOptions(opts...)
 
 	// Get available images and containers to perform checks on.
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{All: true})
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
-	images, err := m.client.ImageList(ctx, image.ListOptions{All: true})
+	images, err := m.listImages(ctx, image.ListOptions{All: true})
 	if err != nil {
 		return nil, err
 	}
@@ -112,7 +116,11 @@ func (m *Manager) performContainerUpdatePrechecks(ctx context.Context, instance,
 	}
 
 	// Ensure that the provided port mapping is feasible.
-	if err := checkPortAvailability(optionz.PortMapping, cnts, instance); err != nil {
+	ports, err := portMappings(optionz.PortMapping, optionz.Ports)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPortAvailability(ports, cnts, instance); err != nil {
 		return nil, err
 	}
 
@@ -217,20 +225,17 @@ func checkInstanceExists(instance string, cnts []types.Container) error {
 
 // checkPortAvailability checks whether the provided port map relies on in-use ports.
 // Notably, this check ignores ports on containers matching the provided ignoreInstance name.
-func checkPortAvailability(ports map[uint32]uint32, cnts []types.Container, ignoreInstance string) error {
+func checkPortAvailability(ports []options.Port, cnts []types.Container, ignoreInstance string) error {
+	others := make([]types.Container, 0, len(cnts))
 	for _, cnt := range cnts {
 		// Shall we ignore this container's ports?
 		if containerMatchesInstance(cnt, ignoreInstance) {
 			continue
 		}
-
-		for _, port := range cnt.Ports {
-			for _, ext := range ports {
-				if ext == uint32(port.PublicPort) {
-					return status.Errorf(codes.Unavailable, "port %d already in use", ext)
-				}
-			}
-		}
+		others = append(others, cnt)
+	}
+	if port, ok := conflictingPort(ports, others); ok {
+		return portInUseError(port)
 	}
 	return nil
 }
@@ -0,0 +1,83 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cpb "github.com/openconfig/gnoi/containerz"
+)
+
+// ContainerUpdateResources changes the CPU and memory limits, and the restart policy, of a
+// container in place, without restarting it. Only the limits provided by WithCPUs,
+// WithHardLimit and WithSoftLimit, and the policy provided by WithRestartPolicy, are changed.
+func (m *Manager) ContainerUpdateResources(ctx context.Context, instance string, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+
+	cpu, err := options.ParseCPUs(optionz.CPU)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "unable to parse cpu limit %f: %v", optionz.CPU, err)
+	}
+	if optionz.HardMemory < 0 || optionz.SoftMemory < 0 {
+		return status.Error(codes.InvalidArgument, "memory limits can not be negative")
+	}
+
+	update := container.UpdateConfig{
+		Resources: container.Resources{
+			NanoCPUs:          cpu,
+			Memory:            optionz.HardMemory,
+			MemoryReservation: optionz.SoftMemory,
+		},
+	}
+	if optionz.RestartPolicy != nil {
+		policy, err := restartPolicy(optionz.RestartPolicy.(*cpb.StartContainerRequest_Restart))
+		if err != nil {
+			return err
+		}
+		update.RestartPolicy = policy
+	}
+	if cpu == 0 && optionz.HardMemory == 0 && optionz.SoftMemory == 0 && optionz.RestartPolicy == nil {
+		return status.Error(codes.InvalidArgument, "no cpu limit, memory limit or restart policy to update")
+	}
+
+	if m.checkCapacity {
+		if err := m.checkHostCapacity(ctx, cpu, optionz.HardMemory); err != nil {
+			return err
+		}
+	}
+
+	cnts, err := m.listContainers(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list containers: %v", err)
+	}
+	if err := checkInstanceExists(instance, cnts); err != nil {
+		return err
+	}
+
+	if _, err := m.client.ContainerUpdate(ctx, m.containerName(instance), update); err != nil {
+		return contextError(ctx, updateError(instance, err))
+	}
+	return nil
+}
+
+// updateError converts the error of a failed resource update to a status error. A memory limit
+// below the memory currently used by the container is refused by the kernel as busy.
+func updateError(instance string, err error) error {
+	switch {
+	case errdefs.IsNotFound(err):
+		return status.Errorf(codes.NotFound, "instance name %s not found", instance)
+	case strings.Contains(err.Error(), "device or resource busy"):
+		return status.Errorf(codes.FailedPrecondition, "unable to lower the resources of container %s below its current usage: %v", instance, err)
+	case errdefs.IsInvalidParameter(err):
+		return status.Errorf(codes.InvalidArgument, "unable to update resources of container %s: %v", instance, err)
+	case errdefs.IsConflict(err):
+		return status.Errorf(codes.FailedPrecondition, "unable to update resources of container %s: %v", instance, err)
+	default:
+		return status.Errorf(codes.Internal, "unable to update resources of container %s: %v", instance, err)
+	}
+}
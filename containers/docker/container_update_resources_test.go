@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cpb "github.com/openconfig/gnoi/containerz"
+)
+
+type fakeResourceUpdatingDocker struct {
+	fakeDocker
+	cnts      []types.Container
+	updateErr error
+
+	Instance string
+	Update   container.UpdateConfig
+}
+
+func (f *fakeResourceUpdatingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return f.cnts, nil
+}
+
+func (f *fakeResourceUpdatingDocker) ContainerUpdate(ctx context.Context, id string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	f.Instance = id
+	f.Update = updateConfig
+	return container.ContainerUpdateOKBody{}, f.updateErr
+}
+
+func TestContainerUpdateResources(t *testing.T) {
+	cnts := []types.Container{{ID: "some-id", Names: []string{"/my-instance"}}}
+
+	tests := []struct {
+		name        string
+		inInstance  string
+		inOpts      []options.Option
+		inUpdateErr error
+		wantState   *fakeResourceUpdatingDocker
+		wantErr     error
+	}{
+		{
+			name:       "cpus-and-memory",
+			inInstance: "my-instance",
+			inOpts:     []options.Option{options.WithCPUs(1.5), options.WithHardLimit(512 << 20), options.WithSoftLimit(256 << 20)},
+			wantState: &fakeResourceUpdatingDocker{
+				Instance: "my-instance",
+				Update: container.UpdateConfig{
+					Resources: container.Resources{
+						NanoCPUs:          1500000000,
+						Memory:            512 << 20,
+						MemoryReservation: 256 << 20,
+					},
+				},
+			},
+		},
+		{
+			name:       "restart-policy",
+			inInstance: "my-instance",
+			inOpts: []options.Option{options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+				Policy:   cpb.StartContainerRequest_Restart_ON_FAILURE,
+				Attempts: 3,
+			})},
+			wantState: &fakeResourceUpdatingDocker{
+				Instance: "my-instance",
+				Update: container.UpdateConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+				},
+			},
+		},
+		{
+			name:       "nothing-to-update",
+			inInstance: "my-instance",
+			wantErr:    status.Error(codes.InvalidArgument, "no cpu limit, memory limit or restart policy to update"),
+		},
+		{
+			name:       "negative-memory",
+			inInstance: "my-instance",
+			inOpts:     []options.Option{options.WithHardLimit(-1)},
+			wantErr:    status.Error(codes.InvalidArgument, "memory limits can not be negative"),
+		},
+		{
+			name:       "no-such-instance",
+			inInstance: "no-such-instance",
+			inOpts:     []options.Option{options.WithCPUs(1)},
+			wantErr:    status.Error(codes.NotFound, "instance name no-such-instance not found"),
+		},
+		{
+			name:        "below-current-usage",
+			inInstance:  "my-instance",
+			inOpts:      []options.Option{options.WithHardLimit(4 << 20)},
+			inUpdateErr: errdefs.System(errors.New("write /sys/fs/cgroup/memory/memory.limit_in_bytes: device or resource busy")),
+			wantState: &fakeResourceUpdatingDocker{
+				Instance: "my-instance",
+				Update: container.UpdateConfig{
+					Resources: container.Resources{Memory: 4 << 20},
+				},
+			},
+			wantErr: status.Error(codes.FailedPrecondition, "unable to lower the resources of container my-instance below its current usage: write /sys/fs/cgroup/memory/memory.limit_in_bytes: device or resource busy"),
+		},
+		{
+			name:        "invalid-limits",
+			inInstance:  "my-instance",
+			inOpts:      []options.Option{options.WithHardLimit(4 << 20), options.WithSoftLimit(8 << 20)},
+			inUpdateErr: errdefs.InvalidParameter(errors.New("Minimum memory limit can not be less than memory reservation limit")),
+			wantState: &fakeResourceUpdatingDocker{
+				Instance: "my-instance",
+				Update: container.UpdateConfig{
+					Resources: container.Resources{Memory: 4 << 20, MemoryReservation: 8 << 20},
+				},
+			},
+			wantErr: status.Error(codes.InvalidArgument, "unable to update resources of container my-instance: Minimum memory limit can not be less than memory reservation limit"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			frd := &fakeResourceUpdatingDocker{
+				cnts:      cnts,
+				updateErr: tc.inUpdateErr,
+			}
+			mgr := New(frd)
+
+			err := mgr.ContainerUpdateResources(context.Background(), tc.inInstance, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerUpdateResources(%q, %+v) returned unexpected error(-want, got):\n %s", tc.inInstance, tc.inOpts, diff)
+			}
+
+			if tc.wantState == nil {
+				tc.wantState = &fakeResourceUpdatingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, frd, cmpopts.IgnoreUnexported(fakeResourceUpdatingDocker{})); diff != "" {
+				t.Errorf("ContainerUpdateResources(%q, %+v) returned diff(-want, +got):\n%s", tc.inInstance, tc.inOpts, diff)
+			}
+		})
+	}
+}
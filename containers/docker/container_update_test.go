@@ -205,7 +205,7 @@ func TestContainerUpdateSync(t *testing.T) {
 				},
 			},
 			inOpts:  []options.Option{options.WithInstanceName("container-I-want"), options.WithPorts(map[uint32]uint32{1: 1})},
-			wantErr: status.Errorf(codes.Unavailable, "port 1 already in use"),
+			wantErr: detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "1", "protocol": "tcp"}, "port 1 already in use"),
 		},
 		{
 			name:    "failure-image-found-container-found-port-reusable-instance-progressing",
@@ -407,16 +407,16 @@ func TestContainerUpdateAsync(t *testing.T) {
 	fd := &fakeUpdatingDocker{
 		summaries: []image.Summary{
 			image.Summary{
-				RepoTags: []string{"image-A1:tag-A1"},
+				RepoTags: []string{"image-a1:tag-A1"},
 			},
 			image.Summary{
-				RepoTags: []string{"image-A2:tag-A2"},
+				RepoTags: []string{"image-a2:tag-A2"},
 			},
 			image.Summary{
-				RepoTags: []string{"image-B1:tag-B1"},
+				RepoTags: []string{"image-b1:tag-B1"},
 			},
 			image.Summary{
-				RepoTags: []string{"image-B2:tag-B2"},
+				RepoTags: []string{"image-b2:tag-B2"},
 			},
 		},
 		cnts: []types.Container{
@@ -445,18 +445,18 @@ func TestContainerUpdateAsync(t *testing.T) {
 	mgr := New(fd)
 
 	// Start Async & blocking update of container A.
-	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-A1", "tag-A1", "", true); err != nil {
-		t.Fatalf("ContainerUpdate(context.Background(), block-till-released, image-A1, tag-A1, , true) returned unexpected error: %v", err)
+	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-a1", "tag-A1", "", true); err != nil {
+		t.Fatalf("ContainerUpdate(context.Background(), block-till-released, image-a1, tag-A1, , true) returned unexpected error: %v", err)
 	}
 
 	// Attempt another update of container A -> should fail.
-	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-A2", "tag-A2", "", false); err == nil { // if NO error
-		t.Fatalf("ContainerUpdate(ctx, block-till-released, image-A1, tag-A1, , false) unexpected succeeded. Expected: container ... is already being updated")
+	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-a2", "tag-A2", "", false); err == nil { // if NO error
+		t.Fatalf("ContainerUpdate(ctx, block-till-released, image-a1, tag-A1, , false) unexpected succeeded. Expected: container ... is already being updated")
 	}
 
 	// Attempt update of container B -> should work.
-	if _, err := mgr.ContainerUpdate(context.Background(), "container-B", "image-B1", "tag-B1", "", false); err != nil {
-		t.Fatalf("ContainerUpdate(context.Background(), container-B, image-B1, tag-B1, , false) returned unexpected error: %v", err)
+	if _, err := mgr.ContainerUpdate(context.Background(), "container-B", "image-b1", "tag-B1", "", false); err != nil {
+		t.Fatalf("ContainerUpdate(context.Background(), container-B, image-b1, tag-B1, , false) returned unexpected error: %v", err)
 	}
 
 	// Unblock container A & give it some time to finish up.
@@ -464,7 +464,7 @@ func TestContainerUpdateAsync(t *testing.T) {
 	time.Sleep(time.Millisecond * 100)
 
 	// Start Async & blocking update of container A.
-	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-A2", "tag-A2", "", true); err != nil {
-		t.Fatalf("ContainerUpdate(context.Background(), block-till-released, image-A2, tag-A2, , true) returned unexpected error: %v", err)
+	if _, err := mgr.ContainerUpdate(context.Background(), "block-till-released", "image-a2", "tag-A2", "", true); err != nil {
+		t.Fatalf("ContainerUpdate(context.Background(), block-till-released, image-a2, tag-A2, , true) returned unexpected error: %v", err)
 	}
 }
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/docker/api/types"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DiskUsage reports the disk space used on the target by images, containers, volumes and the
+// build cache, along with the space that removing the unused ones would reclaim. The usage of
+// each image is only included if requested with options.WithPerImageUsage.
+//
+// An image is unused if no container uses it; only its layers not shared with other images are
+// reclaimable. A container is unused if it is not running, and a volume if no container mounts it.
+func (m *Manager) DiskUsage(ctx context.Context, opts ...options.Option) (*options.DiskUsage, error) {
+	optionz := options.ApplyOptions(opts...)
+
+	usage, err := m.client.DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to query disk usage: %v", contextError(ctx, err))
+	}
+
+	res := &options.DiskUsage{
+		Images: options.DiskUsageCategory{SizeBytes: usage.LayersSize},
+	}
+
+	for _, img := range usage.Images {
+		if img == nil {
+			continue
+		}
+		res.Images.Count++
+		if img.Containers == 0 {
+			res.Images.ReclaimableBytes += img.Size - max(img.SharedSize, 0)
+		}
+		if optionz.PerImageUsage {
+			res.PerImage = append(res.PerImage, &options.ImageDiskUsage{
+				ID:          img.ID,
+				Tags:        img.RepoTags,
+				SizeBytes:   img.Size,
+				SharedBytes: img.SharedSize,
+				Containers:  img.Containers,
+			})
+		}
+	}
+	sort.Slice(res.PerImage, func(i, j int) bool { return res.PerImage[i].ID < res.PerImage[j].ID })
+
+	for _, cnt := range usage.Containers {
+		if cnt == nil {
+			continue
+		}
+		res.Containers.Count++
+		res.Containers.SizeBytes += cnt.SizeRw
+		if cnt.State != "running" {
+			res.Containers.ReclaimableBytes += cnt.SizeRw
+		}
+	}
+
+	for _, vol := range usage.Volumes {
+		if vol == nil {
+			continue
+		}
+		res.Volumes.Count++
+		// The runtime reports -1 for sizes and reference counts it could not compute.
+		if vol.UsageData == nil || vol.UsageData.Size < 0 {
+			continue
+		}
+		res.Volumes.SizeBytes += vol.UsageData.Size
+		if vol.UsageData.RefCount == 0 {
+			res.Volumes.ReclaimableBytes += vol.UsageData.Size
+		}
+	}
+
+	for _, cache := range usage.BuildCache {
+		if cache == nil {
+			continue
+		}
+		res.BuildCache.Count++
+		res.BuildCache.SizeBytes += cache.Size
+		if !cache.InUse && !cache.Shared {
+			res.BuildCache.ReclaimableBytes += cache.Size
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeDiskUsageDocker struct {
+	fakeDocker
+	usage types.DiskUsage
+	err   error
+}
+
+func (f *fakeDiskUsageDocker) DiskUsage(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+	return f.usage, f.err
+}
+
+func TestDiskUsage(t *testing.T) {
+	usage := types.DiskUsage{
+		LayersSize: 1500,
+		Images: []*image.Summary{
+			{ID: "sha256:b", RepoTags: []string{"used:latest"}, Size: 1000, SharedSize: 200, Containers: 1},
+			{ID: "sha256:a", RepoTags: []string{"unused:latest"}, Size: 700, SharedSize: 200, Containers: 0},
+		},
+		Containers: []*container.Summary{
+			{SizeRw: 10, State: "running"},
+			{SizeRw: 20, State: "exited"},
+		},
+		Volumes: []*volume.Volume{
+			{UsageData: &volume.UsageData{Size: 300, RefCount: 1}},
+			{UsageData: &volume.UsageData{Size: 400, RefCount: 0}},
+			{UsageData: &volume.UsageData{Size: -1, RefCount: -1}},
+		},
+		BuildCache: []*types.BuildCache{
+			{Size: 50, InUse: true},
+			{Size: 60},
+		},
+	}
+	totals := options.DiskUsage{
+		Images:     options.DiskUsageCategory{Count: 2, SizeBytes: 1500, ReclaimableBytes: 500},
+		Containers: options.DiskUsageCategory{Count: 2, SizeBytes: 30, ReclaimableBytes: 20},
+		Volumes:    options.DiskUsageCategory{Count: 3, SizeBytes: 700, ReclaimableBytes: 400},
+		BuildCache: options.DiskUsageCategory{Count: 2, SizeBytes: 110, ReclaimableBytes: 60},
+	}
+	perImage := totals
+	perImage.PerImage = []*options.ImageDiskUsage{
+		{ID: "sha256:a", Tags: []string{"unused:latest"}, SizeBytes: 700, SharedBytes: 200},
+		{ID: "sha256:b", Tags: []string{"used:latest"}, SizeBytes: 1000, SharedBytes: 200, Containers: 1},
+	}
+
+	tests := []struct {
+		name      string
+		inUsage   types.DiskUsage
+		inErr     error
+		inOpts    []options.Option
+		wantUsage *options.DiskUsage
+		wantErr   error
+	}{
+		{
+			name:      "empty",
+			wantUsage: &options.DiskUsage{},
+		},
+		{
+			name:      "totals",
+			inUsage:   usage,
+			wantUsage: &totals,
+		},
+		{
+			name:      "per-image",
+			inUsage:   usage,
+			inOpts:    []options.Option{options.WithPerImageUsage()},
+			wantUsage: &perImage,
+		},
+		{
+			name:    "unavailable",
+			inErr:   fmt.Errorf("connection refused"),
+			wantErr: status.Errorf(codes.Unavailable, "unable to query disk usage: connection refused"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := New(&fakeDiskUsageDocker{usage: tc.inUsage, err: tc.inErr})
+
+			got, err := mgr.DiskUsage(context.Background(), tc.inOpts...)
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr.Error(), err.Error()); diff != "" {
+						t.Errorf("DiskUsage() returned diff (-want, +got):\n%s", diff)
+					}
+					return
+				}
+				t.Fatalf("DiskUsage() returned unexpected error: %v", err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("DiskUsage() returned no error, want %v", tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantUsage, got); diff != "" {
+				t.Errorf("DiskUsage() returned diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package docker
+
+import (
+	"path/filepath"
+	"strings"
+
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dockerSocket is the path of the docker socket on the host.
+var dockerSocket = "/var/run/docker.sock"
+
+// resolvePath returns p with its symlinks resolved. If p does not exist, only the symlinks of
+// its directory are resolved, and if that does not exist either p is returned cleaned.
+func resolvePath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(p)); err == nil {
+		return filepath.Join(dir, filepath.Base(p))
+	}
+	return filepath.Clean(p)
+}
+
+// exposedDockerSocket describes how source, the host path of a bind mount, exposes the docker
+// socket: by being the socket or one of its ancestor directories. It returns an empty string if
+// source does not expose it. Symlinks are resolved first as the socket is commonly reachable
+// through several paths, e.g. /run/docker.sock when /var/run links to /run.
+func exposedDockerSocket(source string) string {
+	src, socket := resolvePath(source), resolvePath(dockerSocket)
+	switch {
+	case src == socket:
+		return "the docker socket " + source
+	case src == "/" || strings.HasPrefix(socket, src+"/"):
+		return source + ", which holds the docker socket,"
+	default:
+		return ""
+	}
+}
+
+// checkDockerSocket ensures that none of binds mounts the docker socket, directly or through a
+// directory holding it, unless the server allows it and the request acknowledges it. The socket
+// grants full control of the host.
+func (m *Manager) checkDockerSocket(binds []options.BindMount, acknowledged bool) error {
+	for _, bind := range binds {
+		exposed := exposedDockerSocket(bind.Source)
+		if exposed == "" {
+			continue
+		}
+		if !m.allowDockerSocket {
+			return status.Errorf(codes.PermissionDenied, "mounting %s is not allowed by this server", exposed)
+		}
+		if !acknowledged {
+			return status.Errorf(codes.PermissionDenied, "mounting %s must be explicitly acknowledged", exposed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContainerStartDockerSocket(t *testing.T) {
+	defer func(socket string) { dockerSocket = socket }(dockerSocket)
+
+	// Lay out the host as /var/run linking to /run, with the socket in /run.
+	root := t.TempDir()
+	run := filepath.Join(root, "run")
+	if err := os.Mkdir(run, 0755); err != nil {
+		t.Fatalf("unable to create run directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(run, "docker.sock"), nil, 0600); err != nil {
+		t.Fatalf("unable to create docker socket: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "var"), 0755); err != nil {
+		t.Fatalf("unable to create var directory: %v", err)
+	}
+	if err := os.Symlink(run, filepath.Join(root, "var", "run")); err != nil {
+		t.Fatalf("unable to link var/run: %v", err)
+	}
+	dockerSocket = filepath.Join(root, "var", "run", "docker.sock")
+	socket := filepath.Join(run, "docker.sock")
+
+	tests := []struct {
+		name      string
+		inMgrOpts []Option
+		inSource  string
+		inOpts    []options.Option
+		wantErr   error
+	}{
+		{
+			name:      "allowed-and-acknowledged",
+			inMgrOpts: []Option{WithAllowDockerSocket()},
+			inSource:  dockerSocket,
+			inOpts:    []options.Option{options.WithAcknowledgeDockerSocket()},
+		},
+		{
+			name:      "allowed-through-symlink",
+			inMgrOpts: []Option{WithAllowDockerSocket()},
+			inSource:  socket,
+			inOpts:    []options.Option{options.WithAcknowledgeDockerSocket()},
+		},
+		{
+			name:     "not-allowed",
+			inSource: dockerSocket,
+			inOpts:   []options.Option{options.WithAcknowledgeDockerSocket()},
+			wantErr:  status.Errorf(codes.PermissionDenied, "mounting the docker socket %s is not allowed by this server", dockerSocket),
+		},
+		{
+			name:     "not-allowed-through-symlink",
+			inSource: socket,
+			wantErr:  status.Errorf(codes.PermissionDenied, "mounting the docker socket %s is not allowed by this server", socket),
+		},
+		{
+			name:      "not-acknowledged",
+			inMgrOpts: []Option{WithAllowDockerSocket()},
+			inSource:  socket,
+			wantErr:   status.Errorf(codes.PermissionDenied, "mounting the docker socket %s must be explicitly acknowledged", socket),
+		},
+		{
+			name:     "parent-directory",
+			inSource: run,
+			wantErr:  status.Errorf(codes.PermissionDenied, "mounting %s, which holds the docker socket, is not allowed by this server", run),
+		},
+		{
+			name:     "parent-directory-through-symlink",
+			inSource: filepath.Join(root, "var", "run"),
+			wantErr:  status.Errorf(codes.PermissionDenied, "mounting %s, which holds the docker socket, is not allowed by this server", filepath.Join(root, "var", "run")),
+		},
+		{
+			name:     "ancestor-directory",
+			inSource: root,
+			wantErr:  status.Errorf(codes.PermissionDenied, "mounting %s, which holds the docker socket, is not allowed by this server", root),
+		},
+		{
+			name:     "root-directory",
+			inSource: "/",
+			wantErr:  status.Error(codes.PermissionDenied, "mounting /, which holds the docker socket, is not allowed by this server"),
+		},
+		{
+			name:      "parent-directory-not-acknowledged",
+			inMgrOpts: []Option{WithAllowDockerSocket()},
+			inSource:  run,
+			wantErr:   status.Errorf(codes.PermissionDenied, "mounting %s, which holds the docker socket, must be explicitly acknowledged", run),
+		},
+		{
+			name:      "parent-directory-allowed-and-acknowledged",
+			inMgrOpts: []Option{WithAllowDockerSocket()},
+			inSource:  run,
+			inOpts:    []options.Option{options.WithAcknowledgeDockerSocket()},
+		},
+		{
+			name:     "sibling-directory",
+			inSource: run + "ner",
+		},
+		{
+			name:     "other-file",
+			inSource: filepath.Join(run, "other.sock"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			mgr := New(fsd, tc.inMgrOpts...)
+
+			opts := append([]options.Option{
+				options.WithBindMounts([]options.BindMount{{Source: tc.inSource, Target: "/var/run/docker.sock"}}),
+			}, tc.inOpts...)
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%q) returned unexpected error(-want, got):\n %s", tc.inSource, diff)
+			}
+		})
+	}
+}
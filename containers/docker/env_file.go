@@ -0,0 +1,70 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// containerEnv returns the environment of a container, in KEY=VALUE form and sorted by key, made
+// of the variables of the env-file at path, if any, and of envMapping, which take precedence.
+func containerEnv(path string, envMapping map[string]string) ([]string, error) {
+	vars := map[string]string{}
+	if path != "" {
+		fileVars, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		vars = fileVars
+	}
+	for key, value := range envMapping {
+		vars[key] = value
+	}
+	if len(vars) == 0 {
+		return nil, nil
+	}
+
+	env := make([]string, 0, len(vars))
+	for key, value := range vars {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+	slices.Sort(env)
+	return env, nil
+}
+
+// readEnvFile parses the env-file at path. As with docker's --env-file, leading whitespace is
+// ignored, as are blank lines and lines starting with #, while values are kept verbatim.
+func readEnvFile(path string) (map[string]string, error) {
+	if !filepath.IsAbs(path) {
+		return nil, status.Errorf(codes.InvalidArgument, "env file %q must be an absolute path", path)
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, status.Errorf(codes.NotFound, "env file %s not found", path)
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "unable to read env file %s: %v", path, err)
+	}
+
+	vars := map[string]string{}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(strings.TrimLeftFunc(line, unicode.IsSpace), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || key == "" || strings.ContainsFunc(key, unicode.IsSpace) {
+			return nil, status.Errorf(codes.InvalidArgument, "malformed line %d of env file %s: expected KEY=VALUE", i+1, path)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
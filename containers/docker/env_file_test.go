@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContainerEnv(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		inFile   string
+		inNoFile bool
+		inPath   string
+		inEnv    map[string]string
+		wantEnv  []string
+		wantErr  error
+	}{
+		{
+			name: "no-env",
+		},
+		{
+			name:    "env-only",
+			inEnv:   map[string]string{"B": "2", "A": "1"},
+			wantEnv: []string{"A=1", "B=2"},
+		},
+		{
+			name:    "file-only",
+			inFile:  "# settings\n\nA=1\n  B=two words \nC=\nD=x=y\r\n",
+			wantEnv: []string{"A=1", "B=two words ", "C=", "D=x=y"},
+		},
+		{
+			name:    "env-takes-precedence",
+			inFile:  "A=from-file\nB=from-file\n",
+			inEnv:   map[string]string{"B": "from-env", "C": "from-env"},
+			wantEnv: []string{"A=from-file", "B=from-env", "C=from-env"},
+		},
+		{
+			name:    "empty-file",
+			inFile:  "# nothing here\n",
+			wantEnv: nil,
+		},
+		{
+			name:    "missing-equals",
+			inFile:  "A=1\n# comment\nB\n",
+			wantErr: status.Errorf(codes.InvalidArgument, "malformed line 3 of env file %s: expected KEY=VALUE", filepath.Join(dir, "missing-equals.env")),
+		},
+		{
+			name:    "missing-key",
+			inFile:  "=1\n",
+			wantErr: status.Errorf(codes.InvalidArgument, "malformed line 1 of env file %s: expected KEY=VALUE", filepath.Join(dir, "missing-key.env")),
+		},
+		{
+			name:    "space-in-key",
+			inFile:  "A=1\nMY KEY=2\n",
+			wantErr: status.Errorf(codes.InvalidArgument, "malformed line 2 of env file %s: expected KEY=VALUE", filepath.Join(dir, "space-in-key.env")),
+		},
+		{
+			name:     "missing-file",
+			inNoFile: true,
+			inEnv:    map[string]string{"A": "1"},
+			wantErr:  status.Errorf(codes.NotFound, "env file %s not found", filepath.Join(dir, "missing-file.env")),
+		},
+		{
+			name:    "relative-path",
+			inPath:  "my-app.env",
+			wantErr: status.Errorf(codes.InvalidArgument, "env file %q must be an absolute path", "my-app.env"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := tc.inPath
+			if tc.inFile != "" || tc.inNoFile {
+				path = filepath.Join(dir, tc.name+".env")
+			}
+			if tc.inFile != "" {
+				if err := os.WriteFile(path, []byte(tc.inFile), 0600); err != nil {
+					t.Fatalf("unable to write env file: %v", err)
+				}
+			}
+
+			env, err := containerEnv(path, tc.inEnv)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("containerEnv(%q, %v) returned unexpected error(-want, got):\n %s", path, tc.inEnv, diff)
+			}
+			if diff := cmp.Diff(tc.wantEnv, env); diff != "" {
+				t.Errorf("containerEnv(%q, %v) returned diff(-want, +got):\n%s", path, tc.inEnv, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// errorDomain is the domain of the ErrorInfo details attached to the errors of the manager.
+const errorDomain = "containerz.openconfig.net"
+
+// Reasons reported in the ErrorInfo details of errors, letting clients act on a failure without
+// parsing its message.
+const (
+	reasonInstanceInUse     = "INSTANCE_IN_USE"
+	reasonPortInUse         = "PORT_IN_USE"
+	reasonImageNotFound     = "IMAGE_NOT_FOUND"
+	reasonContainerNotFound = "CONTAINER_NOT_FOUND"
+	reasonContainerRunning  = "CONTAINER_RUNNING"
+	reasonVolumeNotFound    = "VOLUME_NOT_FOUND"
+	reasonVolumeInUse       = "VOLUME_IN_USE"
+	reasonBuildFailed       = "BUILD_FAILED"
+	reasonImagePolicy       = "IMAGE_POLICY_VIOLATION"
+)
+
+// detailedError returns a status error with the provided code and message carrying an ErrorInfo
+// with reason and metadata. The message is the same as that of status.Errorf, so that clients
+// unaware of the details are unaffected.
+//
+// The details are marshalled deterministically: status errors compare equal only if their
+// encoded details do, which would otherwise depend on the iteration order of metadata.
+func detailedError(c codes.Code, reason string, metadata map[string]string, format string, a ...any) error {
+	st := status.Newf(c, format, a...)
+	detail := &anypb.Any{}
+	if err := anypb.MarshalFrom(detail, &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	}, proto.MarshalOptions{Deterministic: true}); err != nil {
+		return st.Err()
+	}
+	p := st.Proto()
+	p.Details = append(p.Details, detail)
+	return status.FromProto(p).Err()
+}
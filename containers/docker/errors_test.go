@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	errdetails "google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestErrorDetails(t *testing.T) {
+	cnts := []types.Container{
+		{
+			Names: []string{"/my-instance"},
+			Ports: []types.Port{{PublicPort: 514, Type: "udp"}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		inErr       func() error
+		wantCode    codes.Code
+		wantMessage string
+		wantDetails []any
+	}{
+		{
+			name: "port-conflict",
+			inErr: func() error {
+				return checkExistingInstanceAndPorts("other-instance", []options.Port{{Internal: 514, External: 514, Protocol: "udp"}}, cnts)
+			},
+			wantCode:    codes.Unavailable,
+			wantMessage: "port 514/udp already in use",
+			wantDetails: []any{
+				&errdetails.ErrorInfo{
+					Reason:   "PORT_IN_USE",
+					Domain:   "containerz.openconfig.net",
+					Metadata: map[string]string{"port": "514", "protocol": "udp"},
+				},
+			},
+		},
+		{
+			name: "already-exists",
+			inErr: func() error {
+				return checkExistingInstanceAndPorts("my-instance", nil, cnts)
+			},
+			wantCode:    codes.AlreadyExists,
+			wantMessage: "instance name my-instance already in use",
+			wantDetails: []any{
+				&errdetails.ErrorInfo{
+					Reason:   "INSTANCE_IN_USE",
+					Domain:   "containerz.openconfig.net",
+					Metadata: map[string]string{"instance": "my-instance"},
+				},
+			},
+		},
+		{
+			name: "volume-in-use",
+			inErr: func() error {
+				mgr := New(&fakeVolumeRemovingDocker{
+					volumes:    []*volume.Volume{{Name: "my-volume"}},
+					containers: map[string][]types.Container{"my-volume": cnts},
+				})
+				return mgr.VolumeRemove(context.Background(), "my-volume")
+			},
+			wantCode:    codes.FailedPrecondition,
+			wantMessage: "volume my-volume is in use by containers: my-instance",
+			wantDetails: []any{
+				&errdetails.ErrorInfo{
+					Reason:   "VOLUME_IN_USE",
+					Domain:   "containerz.openconfig.net",
+					Metadata: map[string]string{"volume": "my-volume", "containers": "my-instance"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(tc.inErr())
+			if st.Code() != tc.wantCode {
+				t.Errorf("returned code %s, want %s", st.Code(), tc.wantCode)
+			}
+			if st.Message() != tc.wantMessage {
+				t.Errorf("returned message %q, want %q", st.Message(), tc.wantMessage)
+			}
+			if diff := cmp.Diff(tc.wantDetails, st.Details(), protocmp.Transform()); diff != "" {
+				t.Errorf("returned unexpected details (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Operation names a lifecycle operation reported to an EventSink.
+type Operation string
+
+const (
+	// OperationContainerStart is the start of a container.
+	OperationContainerStart Operation = "ContainerStart"
+	// OperationContainerStop is the stop of a container.
+	OperationContainerStop Operation = "ContainerStop"
+	// OperationContainerRemove is the removal of a container.
+	OperationContainerRemove Operation = "ContainerRemove"
+	// OperationImagePull is the deployment of an image from a registry.
+	OperationImagePull Operation = "ImagePull"
+	// OperationImageImport is the deployment of an image from an archive.
+	OperationImageImport Operation = "ImageImport"
+	// OperationImageBuild is the deployment of an image built from a build context.
+	OperationImageBuild Operation = "ImageBuild"
+)
+
+// Event records the outcome of a lifecycle operation.
+type Event struct {
+	// Time is when the operation completed.
+	Time time.Time
+	// Operation is the operation performed.
+	Operation Operation
+	// Instance is the name of the container operated on, if any.
+	Instance string
+	// Image is the image reference operated on, if known.
+	Image string
+	// Err is the error the operation failed with, or nil if it succeeded.
+	Err error
+}
+
+// EventSink receives an event for every lifecycle operation performed by the manager, whether it
+// succeeded or failed. Events are delivered asynchronously, so Emit may be called concurrently;
+// an error returned by Emit is logged and does not affect the operation.
+type EventSink interface {
+	Emit(ctx context.Context, event Event) error
+}
+
+// WithEventSink reports lifecycle events to sink.
+func WithEventSink(sink EventSink) Option {
+	return func(m *Manager) {
+		m.events = sink
+	}
+}
+
+// emit reports the outcome of op, started at start, to the metrics, if any, and to the event
+// sink, if any, without waiting for the event to be delivered.
+func (m *Manager) emit(ctx context.Context, op Operation, start time.Time, instance, image string, err error) {
+	m.observe(op, start, err)
+	if m.events == nil {
+		return
+	}
+
+	event := Event{
+		Time:      time.Now(),
+		Operation: op,
+		Instance:  instance,
+		Image:     image,
+		Err:       err,
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := m.events.Emit(ctx, event); err != nil {
+			klog.Warningf("unable to emit %s event for %q: %v", event.Operation, event.Instance, err)
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+)
+
+type recordingSink struct {
+	err    error
+	events chan Event
+}
+
+func (r *recordingSink) Emit(_ context.Context, event Event) error {
+	r.events <- event
+	return r.err
+}
+
+func (r *recordingSink) next(t *testing.T) Event {
+	t.Helper()
+	select {
+	case event := <-r.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event was emitted")
+		return Event{}
+	}
+}
+
+func TestContainerStartEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		inSummaries []image.Summary
+		inSinkErr   error
+		wantEvent   Event
+	}{
+		{
+			name:        "success",
+			inSummaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			wantEvent: Event{
+				Operation: OperationContainerStart,
+				Instance:  "my-name",
+				Image:     "my-image:my-tag",
+			},
+		},
+		{
+			name:        "sink-error",
+			inSummaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			inSinkErr:   fmt.Errorf("sink unavailable"),
+			wantEvent: Event{
+				Operation: OperationContainerStart,
+				Instance:  "my-name",
+				Image:     "my-image:my-tag",
+			},
+		},
+		{
+			name: "failure",
+			wantEvent: Event{
+				Operation: OperationContainerStart,
+				Instance:  "my-name",
+				Image:     "my-image:my-tag",
+				Err:       detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "my-image:my-tag"}, "image my-image:my-tag not found"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := &recordingSink{err: tc.inSinkErr, events: make(chan Event, 1)}
+			mgr := New(&fakeStartingDocker{summaries: tc.inSummaries}, WithEventSink(sink))
+
+			before := time.Now()
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("my-name"))
+			if diff := cmp.Diff(tc.wantEvent.Err, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+
+			got := sink.next(t)
+			if got.Time.Before(before) || got.Time.After(time.Now()) {
+				t.Errorf("ContainerStart() emitted an event at %v, want one between %v and now", got.Time, before)
+			}
+			if diff := cmp.Diff(tc.wantEvent, got, cmpopts.IgnoreFields(Event{}, "Time"), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() emitted event diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContainerStartWithoutEventSink(t *testing.T) {
+	mgr := New(&fakeStartingDocker{summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}}})
+
+	if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd"); err != nil {
+		t.Errorf("ContainerStart() returned error: %v", err)
+	}
+}
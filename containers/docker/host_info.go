@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// HostInfo describes the container runtime of the target and the resources available to it.
+// Only a failure to query the runtime information is an error: the version and disk usage are
+// best effort, and are left unset if the runtime does not report them.
+func (m *Manager) HostInfo(ctx context.Context) (*options.HostInfo, error) {
+	info, err := m.client.Info(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unable to query runtime information: %v", contextError(ctx, err))
+	}
+
+	host := &options.HostInfo{
+		RuntimeVersion:    info.ServerVersion,
+		OS:                info.OperatingSystem,
+		KernelVersion:     info.KernelVersion,
+		Architecture:      info.Architecture,
+		StorageDriver:     info.Driver,
+		CPUs:              info.NCPU,
+		MemoryBytes:       info.MemTotal,
+		Containers:        info.Containers,
+		ContainersRunning: info.ContainersRunning,
+		Images:            info.Images,
+	}
+	if host.OS == "" {
+		host.OS = info.OSType
+	}
+
+	if version, err := m.client.ServerVersion(ctx); err != nil {
+		klog.Warningf("unable to query runtime version: %v", err)
+	} else {
+		if version.Version != "" {
+			host.RuntimeVersion = version.Version
+		}
+		host.APIVersion = version.APIVersion
+		if host.Architecture == "" {
+			host.Architecture = version.Arch
+		}
+		if host.KernelVersion == "" {
+			host.KernelVersion = version.KernelVersion
+		}
+	}
+
+	if usage, err := m.client.DiskUsage(ctx, types.DiskUsageOptions{}); err != nil {
+		klog.Warningf("unable to query runtime disk usage: %v", err)
+	} else {
+		host.DiskUsageBytes = diskUsageBytes(usage)
+	}
+
+	return host, nil
+}
+
+// diskUsageBytes sums the space used by image layers, container filesystems and volumes. Volumes
+// whose size is unknown are skipped.
+func diskUsageBytes(usage types.DiskUsage) int64 {
+	total := usage.LayersSize
+	for _, cnt := range usage.Containers {
+		if cnt != nil {
+			total += cnt.SizeRw
+		}
+	}
+	for _, vol := range usage.Volumes {
+		if vol != nil && vol.UsageData != nil && vol.UsageData.Size > 0 {
+			total += vol.UsageData.Size
+		}
+	}
+	return total
+}
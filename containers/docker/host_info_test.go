@@ -0,0 +1,160 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeHostInfoDocker struct {
+	fakeDocker
+	info       system.Info
+	infoErr    error
+	version    types.Version
+	versionErr error
+	usage      types.DiskUsage
+	usageErr   error
+}
+
+func (f *fakeHostInfoDocker) Info(context.Context) (system.Info, error) {
+	return f.info, f.infoErr
+}
+
+func (f *fakeHostInfoDocker) ServerVersion(context.Context) (types.Version, error) {
+	return f.version, f.versionErr
+}
+
+func (f *fakeHostInfoDocker) DiskUsage(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+	return f.usage, f.usageErr
+}
+
+func TestHostInfo(t *testing.T) {
+	info := system.Info{
+		ServerVersion:     "28.1.1",
+		OperatingSystem:   "Debian GNU/Linux 12 (bookworm)",
+		OSType:            "linux",
+		KernelVersion:     "6.1.0-18-amd64",
+		Architecture:      "x86_64",
+		Driver:            "overlay2",
+		NCPU:              8,
+		MemTotal:          16 << 30,
+		Containers:        5,
+		ContainersRunning: 3,
+		Images:            12,
+	}
+
+	tests := []struct {
+		name     string
+		inDocker *fakeHostInfoDocker
+		wantInfo *options.HostInfo
+		wantErr  error
+	}{
+		{
+			name: "full",
+			inDocker: &fakeHostInfoDocker{
+				info:    info,
+				version: types.Version{Version: "28.1.1", APIVersion: "1.49"},
+				usage: types.DiskUsage{
+					LayersSize: 1000,
+					Containers: []*container.Summary{{SizeRw: 10}, {SizeRw: 20}},
+					Volumes: []*volume.Volume{
+						{UsageData: &volume.UsageData{Size: 300}},
+						{UsageData: &volume.UsageData{Size: -1}},
+						{},
+					},
+				},
+			},
+			wantInfo: &options.HostInfo{
+				RuntimeVersion:    "28.1.1",
+				APIVersion:        "1.49",
+				OS:                "Debian GNU/Linux 12 (bookworm)",
+				KernelVersion:     "6.1.0-18-amd64",
+				Architecture:      "x86_64",
+				StorageDriver:     "overlay2",
+				CPUs:              8,
+				MemoryBytes:       16 << 30,
+				Containers:        5,
+				ContainersRunning: 3,
+				Images:            12,
+				DiskUsageBytes:    1330,
+			},
+		},
+		{
+			name: "partial",
+			inDocker: &fakeHostInfoDocker{
+				info: system.Info{
+					OSType:            "linux",
+					ContainersRunning: 1,
+				},
+				version: types.Version{Version: "1.2.3", Arch: "arm64", KernelVersion: "5.15"},
+			},
+			wantInfo: &options.HostInfo{
+				RuntimeVersion:    "1.2.3",
+				OS:                "linux",
+				KernelVersion:     "5.15",
+				Architecture:      "arm64",
+				ContainersRunning: 1,
+			},
+		},
+		{
+			name: "version-and-disk-usage-unavailable",
+			inDocker: &fakeHostInfoDocker{
+				info:       info,
+				versionErr: fmt.Errorf("not implemented"),
+				usageErr:   fmt.Errorf("not implemented"),
+			},
+			wantInfo: &options.HostInfo{
+				RuntimeVersion:    "28.1.1",
+				OS:                "Debian GNU/Linux 12 (bookworm)",
+				KernelVersion:     "6.1.0-18-amd64",
+				Architecture:      "x86_64",
+				StorageDriver:     "overlay2",
+				CPUs:              8,
+				MemoryBytes:       16 << 30,
+				Containers:        5,
+				ContainersRunning: 3,
+				Images:            12,
+			},
+		},
+		{
+			name: "info-unavailable",
+			inDocker: &fakeHostInfoDocker{
+				infoErr: fmt.Errorf("connection refused"),
+			},
+			wantErr: status.Errorf(codes.Unavailable, "unable to query runtime information: connection refused"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := New(tc.inDocker)
+
+			got, err := mgr.HostInfo(context.Background())
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr.Error(), err.Error()); diff != "" {
+						t.Errorf("HostInfo() returned diff (-want, +got):\n%s", diff)
+					}
+					return
+				}
+				t.Fatalf("HostInfo() returned unexpected error: %v", err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("HostInfo() returned no error, want %v", tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantInfo, got); diff != "" {
+				t.Errorf("HostInfo() returned diff (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
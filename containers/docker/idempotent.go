@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// configLabel records the digest of the configuration a container was started with by an
+// idempotent start, so that a later one can tell whether the running container is identical.
+const configLabel = "containerz.config"
+
+// secretsPlaceholder replaces the secrets directory of a container in its configuration digest,
+// as the directory differs between otherwise identical containers.
+const secretsPlaceholder = "$SECRETS"
+
+// runningInstance returns the container of cnts named instance, if any. As cnts only holds the
+// running containers, it is only found if it is running.
+func runningInstance(instance string, cnts []types.Container) (types.Container, bool) {
+	if instance == "" {
+		return types.Container{}, false
+	}
+	for _, cnt := range cnts {
+		if containerMatchesInstance(cnt, instance) {
+			return cnt, true
+		}
+	}
+	return types.Container{}, false
+}
+
+// withoutContainer returns cnts without the container with the provided ID.
+func withoutContainer(cnts []types.Container, id string) []types.Container {
+	others := make([]types.Container, 0, len(cnts))
+	for _, cnt := range cnts {
+		if cnt.ID != id {
+			others = append(others, cnt)
+		}
+	}
+	return others
+}
+
+// configDigest returns the digest of the configuration of a container started from the image
+// with the provided ID. The image ID is included so that a tag moved to another image is seen as
+// a change. The digest is readable from the labels of the container, so the content of secrets is
+// left out of it and compared with the files of the container instead.
+func configDigest(imageID string, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, secretsDir string) (string, error) {
+	b, err := json.Marshal(struct {
+		ImageID          string
+		Config           *container.Config
+		HostConfig       *container.HostConfig
+		NetworkingConfig *network.NetworkingConfig
+		Platform         *ocispec.Platform
+	}{imageID, config, hostConfig, networkingConfig, platform})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to encode container configuration: %v", err)
+	}
+	if secretsDir != "" {
+		b = bytes.ReplaceAll(b, []byte(secretsDir), []byte(secretsPlaceholder))
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkIdentical returns nil if cnt, the running container of instance, was started from the
+// image with the provided ID, with the configuration digest and with secrets. Otherwise the
+// instance is in use by a different container and an AlreadyExists error naming the mismatch is
+// returned.
+func (m *Manager) checkIdentical(instance string, cnt types.Container, imageID, digest string, secrets []options.Secret) error {
+	var mismatch, what string
+	switch {
+	case imageID != "" && cnt.ImageID != imageID:
+		mismatch, what = "image", "a different image"
+	case cnt.Labels[configLabel] != digest:
+		mismatch, what = "config", "a different configuration"
+	case !m.sameSecrets(cnt.Labels[secretsLabel], secrets):
+		mismatch, what = "secrets", "different secrets"
+	default:
+		return nil
+	}
+	return detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": instance, "mismatch": mismatch},
+		"instance name %s already in use by a container with %s", instance, what)
+}
@@ -0,0 +1,186 @@
+package docker
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+)
+
+func TestContainerStartIdempotent(t *testing.T) {
+	summaries := []image.Summary{{ID: "sha256:aaaa", RepoTags: []string{"my-image:my-tag"}}}
+	opts := []options.Option{
+		options.WithInstanceName("my-instance"),
+		options.WithEnv(map[string]string{"MODE": "edge"}),
+		options.WithPorts(map[uint32]uint32{80: 8080}),
+		options.WithIdempotentStart(),
+	}
+
+	// Start the container once to learn the labels it is created with.
+	fsd := &fakeStartingDocker{summaries: summaries}
+	if _, err := New(fsd).ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...); err != nil {
+		t.Fatalf("ContainerStart() returned error: %v", err)
+	}
+	if fsd.rawLabels[configLabel] == "" {
+		t.Fatalf("ContainerStart() did not label the container with its configuration digest")
+	}
+	running := []types.Container{{
+		ID:      "some-id",
+		Names:   []string{"/my-instance"},
+		ImageID: "sha256:aaaa",
+		Labels:  fsd.rawLabels,
+		Ports:   []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+	}}
+
+	tests := []struct {
+		name        string
+		inSummaries []image.Summary
+		inCnts      []types.Container
+		inOpts      []options.Option
+		wantCreated bool
+		wantErr     error
+	}{
+		{
+			name:   "identical-running",
+			inOpts: opts,
+		},
+		{
+			name:    "different-config",
+			inOpts:  append(append([]options.Option{}, opts...), options.WithEnv(map[string]string{"MODE": "core"})),
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-instance", "mismatch": "config"}, "instance name my-instance already in use by a container with a different configuration"),
+		},
+		{
+			name:        "different-image",
+			inSummaries: []image.Summary{{ID: "sha256:bbbb", RepoTags: []string{"my-image:my-tag"}}},
+			inOpts:      opts,
+			wantErr:     detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-instance", "mismatch": "image"}, "instance name my-instance already in use by a container with a different image"),
+		},
+		{
+			name:    "not-idempotent",
+			inOpts:  opts[:3],
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-instance"}, "instance name my-instance already in use"),
+		},
+		{
+			name: "forged-digest",
+			inCnts: func() []types.Container {
+				// A container started without an idempotent start, forging the digest.
+				forged := &fakeStartingDocker{summaries: summaries}
+				forgedOpts := append(append([]options.Option{}, opts[:3]...), options.WithLabels(map[string]string{configLabel: fsd.rawLabels[configLabel]}))
+				if _, err := New(forged).ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", forgedOpts...); err != nil {
+					t.Fatalf("ContainerStart() returned error: %v", err)
+				}
+				return []types.Container{{
+					ID:      "some-id",
+					Names:   []string{"/my-instance"},
+					ImageID: "sha256:aaaa",
+					Labels:  forged.rawLabels,
+					Ports:   []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+				}}
+			}(),
+			inOpts:  opts,
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-instance", "mismatch": "config"}, "instance name my-instance already in use by a container with a different configuration"),
+		},
+		{
+			name:        "other-instance",
+			inOpts:      append(append([]options.Option{}, opts...), options.WithInstanceName("other-instance"), options.WithPorts(map[uint32]uint32{80: 9090})),
+			wantCreated: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.inSummaries == nil {
+				tc.inSummaries = summaries
+			}
+			if tc.inCnts == nil {
+				tc.inCnts = running
+			}
+			fsd := &fakeStartingDocker{summaries: tc.inSummaries, cnts: tc.inCnts}
+			mgr := New(fsd)
+
+			name, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if created := fsd.ContainerID != ""; created != tc.wantCreated {
+				t.Errorf("ContainerStart() created a container: %t, want %t", created, tc.wantCreated)
+			}
+			if !tc.wantCreated && name != "my-instance" {
+				t.Errorf("ContainerStart() returned name %q, want the existing my-instance", name)
+			}
+		})
+	}
+}
+
+func TestContainerStartIdempotentSecrets(t *testing.T) {
+	defer func(check func(string) error) { checkTmpfs = check }(checkTmpfs)
+	checkTmpfs = func(string) error { return nil }
+
+	summaries := []image.Summary{{ID: "sha256:aaaa", RepoTags: []string{"my-image:my-tag"}}}
+	opts := func(data string) []options.Option {
+		return []options.Option{
+			options.WithInstanceName("my-instance"),
+			options.WithSecrets([]options.Secret{{Name: "token", Data: []byte(data)}}),
+			options.WithIdempotentStart(),
+		}
+	}
+
+	secretsDir := filepath.Join(t.TempDir(), "secrets")
+	fsd := &fakeStartingDocker{summaries: summaries}
+	if _, err := New(fsd, WithSecretsDir(secretsDir)).ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts("s3cr3t")...); err != nil {
+		t.Fatalf("ContainerStart() returned error: %v", err)
+	}
+	other := &fakeStartingDocker{summaries: summaries}
+	if _, err := New(other, WithSecretsDir(secretsDir)).ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts("other")...); err != nil {
+		t.Fatalf("ContainerStart() returned error: %v", err)
+	}
+	if fsd.rawLabels[configLabel] != other.rawLabels[configLabel] {
+		t.Errorf("ContainerStart() labelled containers differing only by the content of their secrets with different digests")
+	}
+	running := []types.Container{{
+		ID:      "some-id",
+		Names:   []string{"/my-instance"},
+		ImageID: "sha256:aaaa",
+		Labels:  fsd.rawLabels,
+	}}
+
+	tests := []struct {
+		name    string
+		inData  string
+		wantErr error
+	}{
+		{
+			name:   "identical-secrets",
+			inData: "s3cr3t",
+		},
+		{
+			name:    "different-secrets",
+			inData:  "other",
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "my-instance", "mismatch": "secrets"}, "instance name my-instance already in use by a container with different secrets"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{summaries: summaries, cnts: running}
+			mgr := New(fsd, WithSecretsDir(secretsDir))
+
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts(tc.inData)...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if fsd.ContainerID != "" {
+				t.Errorf("ContainerStart() created a container, want none")
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/moby/moby/pkg/jsonmessage"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxStepOutput bounds the output of a failed build step reported in the error details.
+const maxStepOutput = 4096
+
+// ImageBuild builds an image from buildContext, a tar archive holding a Dockerfile at its root,
+// and tags it with the name and tag set by WithTarget, which are returned. Build-time variables
+// are set by WithBuildArgs, and the output of the build steps is written to the writer set by
+// WithBuildOutput as they run.
+func (m *Manager) ImageBuild(ctx context.Context, buildContext io.Reader, opts ...options.Option) (name, tag string, err error) {
+	if buildContext == nil {
+		return "", "", status.Error(codes.InvalidArgument, "a build context must be supplied")
+	}
+
+	optionz := options.ApplyOptions(opts...)
+	if optionz.TargetName == "" {
+		return "", "", status.Error(codes.InvalidArgument, "a target image name must be supplied")
+	}
+	ref, err := normalizeReference(optionz.TargetName, optionz.TargetTag)
+	if err != nil {
+		return "", "", err
+	}
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationImageBuild, start, "", ref, err) }()
+
+	args := make(map[string]*string, len(optionz.BuildArgs))
+	for k, v := range optionz.BuildArgs {
+		args[k] = &v
+	}
+
+	// The cache is invalidated once the build completes, as it tags an image.
+	defer m.images.invalidate()
+
+	resp, err := m.client.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        []string{ref},
+		BuildArgs:   args,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		if errdefs.IsInvalidParameter(err) {
+			return "", "", status.Errorf(codes.InvalidArgument, "unable to build image %s: %v", ref, err)
+		}
+		return "", "", contextError(ctx, status.Errorf(codes.Internal, "unable to build image %s: %v", ref, err))
+	}
+	defer resp.Body.Close()
+	defer closeOnCancel(ctx, resp.Body)()
+
+	// As with a pull, the build only completes once its output has been consumed, and a failed
+	// step is reported in the output rather than by the call itself.
+	if err := buildOutput(optionz.BuildOutput, ref, resp.Body); err != nil {
+		return "", "", contextError(ctx, err)
+	}
+	return optionz.TargetName, optionz.TargetTag, nil
+}
+
+// buildOutput reads the JSON messages of the build of ref, writing the output of its steps to w,
+// if set. A failed build is returned as an Internal error whose details hold the failing step
+// and the end of its output.
+func buildOutput(w io.Writer, ref string, body io.Reader) error {
+	dec := json.NewDecoder(body)
+
+	// The current step, and its output so far, bounded to maxStepOutput.
+	var step string
+	var output []byte
+
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return status.Errorf(codes.Internal, "unable to decode build output: %v", err)
+		}
+
+		if jm.Error != nil {
+			metadata := map[string]string{"image": ref}
+			if step != "" {
+				metadata["step"] = step
+			}
+			if len(output) > 0 {
+				metadata["output"] = string(output)
+			}
+			return detailedError(codes.Internal, reasonBuildFailed, metadata, "unable to build image %s: %s", ref, jm.Error.Message)
+		}
+		if jm.Stream == "" {
+			continue
+		}
+
+		if w != nil {
+			if _, err := io.WriteString(w, jm.Stream); err != nil {
+				return status.Errorf(codes.Internal, "unable to write build output: %v", err)
+			}
+		}
+		if strings.HasPrefix(jm.Stream, "Step ") {
+			step, output = strings.TrimSpace(jm.Stream), nil
+			continue
+		}
+		output = append(output, jm.Stream...)
+		if len(output) > maxStepOutput {
+			output = output[len(output)-maxStepOutput:]
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/moby/moby/pkg/jsonmessage"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeBuildingDocker struct {
+	fakeDocker
+	buildErr error
+	msgs     []*jsonmessage.JSONMessage
+
+	Context   string
+	Tags      []string
+	BuildArgs map[string]string
+}
+
+func (f *fakeBuildingDocker) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	b, err := io.ReadAll(buildContext)
+	if err != nil {
+		return types.ImageBuildResponse{}, err
+	}
+	f.Context = string(b)
+	f.Tags = options.Tags
+	for k, v := range options.BuildArgs {
+		if f.BuildArgs == nil {
+			f.BuildArgs = map[string]string{}
+		}
+		f.BuildArgs[k] = *v
+	}
+	if f.buildErr != nil {
+		return types.ImageBuildResponse{}, f.buildErr
+	}
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, jm := range f.msgs {
+		if err := enc.Encode(jm); err != nil {
+			return types.ImageBuildResponse{}, err
+		}
+	}
+	return types.ImageBuildResponse{Body: io.NopCloser(buf)}, nil
+}
+
+func TestImageBuild(t *testing.T) {
+	steps := []*jsonmessage.JSONMessage{
+		{Stream: "Step 1/2 : FROM alpine\n"},
+		{Stream: " ---> 0123456789ab\n"},
+		{Stream: "Step 2/2 : RUN make VERSION=${VERSION}\n"},
+		{Stream: "make: building 1.2\n"},
+	}
+
+	tests := []struct {
+		name       string
+		inOpts     []options.Option
+		inMsgs     []*jsonmessage.JSONMessage
+		inBuildErr error
+		wantName   string
+		wantTag    string
+		wantState  *fakeBuildingDocker
+		wantOutput string
+		wantErr    error
+	}{
+		{
+			name: "build",
+			inOpts: []options.Option{
+				options.WithTarget("my-image", "v1"),
+				options.WithBuildArgs(map[string]string{"VERSION": "1.2", "EMPTY": ""}),
+			},
+			inMsgs:   append(steps, &jsonmessage.JSONMessage{Stream: "Successfully tagged my-image:v1\n"}),
+			wantName: "my-image",
+			wantTag:  "v1",
+			wantState: &fakeBuildingDocker{
+				Context:   "build-context",
+				Tags:      []string{"my-image:v1"},
+				BuildArgs: map[string]string{"VERSION": "1.2", "EMPTY": ""},
+			},
+			wantOutput: "Step 1/2 : FROM alpine\n ---> 0123456789ab\nStep 2/2 : RUN make VERSION=${VERSION}\nmake: building 1.2\nSuccessfully tagged my-image:v1\n",
+		},
+		{
+			name:     "default-tag",
+			inOpts:   []options.Option{options.WithTarget("my-image", "")},
+			wantName: "my-image",
+			wantTag:  "latest",
+			wantState: &fakeBuildingDocker{
+				Context: "build-context",
+				Tags:    []string{"my-image:latest"},
+			},
+		},
+		{
+			name:    "no-target",
+			wantErr: status.Error(codes.InvalidArgument, "a target image name must be supplied"),
+		},
+		{
+			name:   "failed-step",
+			inOpts: []options.Option{options.WithTarget("my-image", "v1")},
+			inMsgs: append(steps,
+				&jsonmessage.JSONMessage{Stream: "make: *** [all] Error 2\n"},
+				&jsonmessage.JSONMessage{Error: &jsonmessage.JSONError{Code: 2, Message: "The command '/bin/sh -c make VERSION=${VERSION}' returned a non-zero code: 2"}},
+			),
+			wantState: &fakeBuildingDocker{
+				Context: "build-context",
+				Tags:    []string{"my-image:v1"},
+			},
+			wantOutput: "Step 1/2 : FROM alpine\n ---> 0123456789ab\nStep 2/2 : RUN make VERSION=${VERSION}\nmake: building 1.2\nmake: *** [all] Error 2\n",
+			wantErr: detailedError(codes.Internal, reasonBuildFailed, map[string]string{
+				"image":  "my-image:v1",
+				"step":   "Step 2/2 : RUN make VERSION=${VERSION}",
+				"output": "make: building 1.2\nmake: *** [all] Error 2\n",
+			}, "unable to build image my-image:v1: The command '/bin/sh -c make VERSION=${VERSION}' returned a non-zero code: 2"),
+		},
+		{
+			name:       "invalid-context",
+			inOpts:     []options.Option{options.WithTarget("my-image", "v1")},
+			inBuildErr: errdefs.InvalidParameter(errors.New("Cannot locate specified Dockerfile: Dockerfile")),
+			wantState: &fakeBuildingDocker{
+				Context: "build-context",
+				Tags:    []string{"my-image:v1"},
+			},
+			wantErr: status.Error(codes.InvalidArgument, "unable to build image my-image:v1: Cannot locate specified Dockerfile: Dockerfile"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fbd := &fakeBuildingDocker{msgs: tc.inMsgs, buildErr: tc.inBuildErr}
+			mgr := New(fbd)
+			out := &bytes.Buffer{}
+
+			opts := append([]options.Option{options.WithBuildOutput(out)}, tc.inOpts...)
+			name, tag, err := mgr.ImageBuild(context.Background(), bytes.NewBufferString("build-context"), opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ImageBuild() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if name != tc.wantName || tag != tc.wantTag {
+				t.Errorf("ImageBuild() returned %s:%s, want %s:%s", name, tag, tc.wantName, tc.wantTag)
+			}
+
+			if tc.wantState == nil {
+				tc.wantState = &fakeBuildingDocker{}
+			}
+			if diff := cmp.Diff(tc.wantState, fbd, cmpopts.IgnoreUnexported(fakeBuildingDocker{})); diff != "" {
+				t.Errorf("ImageBuild() returned diff(-want, +got):\n%s", diff)
+			}
+			if got := out.String(); got != tc.wantOutput {
+				t.Errorf("ImageBuild() wrote output %q, want %q", got, tc.wantOutput)
+			}
+		})
+	}
+}
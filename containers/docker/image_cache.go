@@ -0,0 +1,97 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	// imageCacheTTL bounds how long an image lookup is trusted. Image changes made through the
+	// manager invalidate the cache immediately; the TTL covers changes made to the runtime directly.
+	imageCacheTTL = 10 * time.Second
+)
+
+type imageCacheEntry struct {
+	summary image.Summary
+	expires time.Time
+}
+
+// imageCache remembers the images recently listed on the target, keyed by image:tag and by
+// image@digest, so that a batch of container starts does not enumerate every image for each
+// container. Only images that were found are cached.
+type imageCache struct {
+	mu      sync.Mutex
+	entries map[string]imageCacheEntry
+	// generation is bumped on every invalidation so that a listing which raced with an image
+	// change is not cached.
+	generation uint64
+}
+
+func newImageCache() *imageCache {
+	return &imageCache{entries: make(map[string]imageCacheEntry)}
+}
+
+// get returns the cached image tagged ref, if any, along with the current cache generation.
+func (c *imageCache) get(ref string) (image.Summary, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[ref]
+	if !ok || time.Now().After(entry.expires) {
+		return image.Summary{}, c.generation, false
+	}
+	return entry.summary, c.generation, true
+}
+
+// fill caches the listed images, unless the cache was invalidated since generation.
+func (c *imageCache) fill(generation uint64, summaries []image.Summary) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	expires := time.Now().Add(imageCacheTTL)
+	for _, summary := range summaries {
+		for _, ref := range slices.Concat(summary.RepoTags, summary.RepoDigests) {
+			c.entries[ref] = imageCacheEntry{summary: summary, expires: expires}
+		}
+	}
+}
+
+// invalidate drops every cached image. It must be called whenever images are added, removed or
+// retagged.
+func (c *imageCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.generation++
+}
+
+// lookupImage returns the image tagged, or pinned by digest, as ref, listing the images on the
+// target only if it has not recently been seen.
+func (m *Manager) lookupImage(ctx context.Context, ref string) (image.Summary, error) {
+	summary, generation, ok := m.images.get(ref)
+	if ok {
+		return summary, nil
+	}
+
+	images, err := m.listImages(ctx, image.ListOptions{})
+	if err != nil {
+		return image.Summary{}, err
+	}
+	m.images.fill(generation, images)
+
+	for _, summary := range images {
+		if slices.Contains(summary.RepoTags, ref) || slices.Contains(summary.RepoDigests, ref) {
+			return summary, nil
+		}
+	}
+	return image.Summary{}, detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": ref}, "image %s not found", ref)
+}
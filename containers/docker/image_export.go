@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/image"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImageExport writes the image identified by imageName and tag to w as a tar archive. The tag
+// defaults to latest if it is not specified. The export stream from the runtime is closed once
+// the copy completes or ctx is cancelled.
+func (m *Manager) ImageExport(ctx context.Context, imageName, tag string, w io.Writer) error {
+	switch {
+	case imageName == "":
+		return status.Error(codes.InvalidArgument, "an image name must be supplied.")
+	case tag == "":
+		tag = "latest"
+	}
+	ref := fmt.Sprintf("%s:%s", imageName, tag)
+
+	images, err := m.listImages(ctx, image.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if err := findImage(ref, images); err != nil {
+		return err
+	}
+
+	resp, err := m.client.ImageSave(ctx, []string{ref})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to export image %s: %v", ref, err)
+	}
+	defer resp.Close()
+
+	// Unblock the copy below if the caller goes away mid-stream.
+	defer closeOnCancel(ctx, resp)()
+
+	if _, err := io.Copy(w, resp); err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to export image %s: %v", ref, err))
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeSaveReader struct {
+	io.Reader
+	mu     sync.Mutex
+	closed bool
+}
+
+func (f *fakeSaveReader) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.Reader.(io.Closer); ok && !f.closed {
+		c.Close()
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSaveReader) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+type fakeImageExportingDocker struct {
+	fakeDocker
+	summaries []image.Summary
+	reader    *fakeSaveReader
+
+	ImageIDs []string
+}
+
+func (f *fakeImageExportingDocker) ImageList(context.Context, image.ListOptions) ([]image.Summary, error) {
+	return f.summaries, nil
+}
+
+func (f *fakeImageExportingDocker) ImageSave(_ context.Context, imageIDs []string, _ ...client.ImageSaveOption) (io.ReadCloser, error) {
+	f.ImageIDs = imageIDs
+	return f.reader, nil
+}
+
+func TestImageExport(t *testing.T) {
+	tarball := bytes.Repeat([]byte("some-layer-data"), 4096)
+
+	tests := []struct {
+		name        string
+		inImage     string
+		inTag       string
+		inSummaries []image.Summary
+		wantState   *fakeImageExportingDocker
+		wantBytes   []byte
+		wantErr     error
+	}{
+		{
+			name:    "export",
+			inImage: "some-image",
+			inTag:   "some-tag",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:some-tag"}},
+			},
+			wantState: &fakeImageExportingDocker{
+				ImageIDs: []string{"some-image:some-tag"},
+			},
+			wantBytes: tarball,
+		},
+		{
+			name:    "default-tag",
+			inImage: "some-image",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:latest"}},
+			},
+			wantState: &fakeImageExportingDocker{
+				ImageIDs: []string{"some-image:latest"},
+			},
+			wantBytes: tarball,
+		},
+		{
+			name:    "not-found",
+			inImage: "some-image",
+			inTag:   "other-tag",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:some-tag"}},
+			},
+			wantErr: status.Error(codes.NotFound, "image some-image:other-tag not found"),
+		},
+		{
+			name:    "no-image",
+			wantErr: status.Error(codes.InvalidArgument, "an image name must be supplied."),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fed := &fakeImageExportingDocker{
+				summaries: tc.inSummaries,
+				reader:    &fakeSaveReader{Reader: bytes.NewReader(tarball)},
+			}
+			mgr := New(fed)
+
+			buf := &bytes.Buffer{}
+			if err := mgr.ImageExport(ctx, tc.inImage, tc.inTag, buf); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ImageExport(%q, %q) returned unexpected error(-want, got):\n %s", tc.inImage, tc.inTag, diff)
+					}
+					return
+				}
+				t.Errorf("ImageExport(%q, %q) returned error: %v", tc.inImage, tc.inTag, err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ImageExport(%q, %q) did not return an error, want %v", tc.inImage, tc.inTag, tc.wantErr)
+			}
+
+			if !bytes.Equal(buf.Bytes(), tc.wantBytes) {
+				t.Errorf("ImageExport(%q, %q) wrote %d bytes, want %d", tc.inImage, tc.inTag, buf.Len(), len(tc.wantBytes))
+			}
+
+			if !fed.reader.isClosed() {
+				t.Errorf("ImageExport(%q, %q) did not close the export stream", tc.inImage, tc.inTag)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fed, cmpopts.IgnoreUnexported(fakeImageExportingDocker{})); diff != "" {
+					t.Errorf("ImageExport(%q, %q) returned diff(-want, +got):\n%s", tc.inImage, tc.inTag, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestImageExportCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pr, pw := io.Pipe()
+	go func() {
+		// Write part of the archive and then stall until the reader is closed.
+		if _, err := pw.Write([]byte("partial")); err != nil {
+			return
+		}
+		cancel()
+	}()
+
+	fed := &fakeImageExportingDocker{
+		summaries: []image.Summary{{RepoTags: []string{"some-image:some-tag"}}},
+		reader:    &fakeSaveReader{Reader: pr},
+	}
+	mgr := New(fed)
+
+	err := mgr.ImageExport(ctx, "some-image", "some-tag", &bytes.Buffer{})
+	if got, want := status.Code(err), codes.Canceled; got != want {
+		t.Errorf("ImageExport() returned code %v, want %v (err: %v)", got, want, err)
+	}
+	if !fed.reader.isClosed() {
+		t.Errorf("ImageExport() did not close the export stream after cancellation")
+	}
+}
@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/moby/moby/pkg/jsonmessage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// ImageImport loads an image archive from r and returns the references of the images it
+// contained. The archive is either a docker archive, as produced by ImageExport, or an OCI image
+// layout, optionally compressed. Images that already exist on the target are replaced.
+func (m *Manager) ImageImport(ctx context.Context, r io.Reader) (refs []string, err error) {
+	if r == nil {
+		return nil, status.Error(codes.InvalidArgument, "reader must be supplied")
+	}
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationImageImport, start, "", strings.Join(refs, ","), err) }()
+
+	defer m.images.invalidate()
+
+	// The archive is checked as it streams to the runtime, which only sees its end if it is in a
+	// recognized format. A rejected archive fails the load, and its error takes precedence.
+	pr, pw := io.Pipe()
+	checked := make(chan error, 1)
+	go func() {
+		err := checkArchive(io.TeeReader(r, pw))
+		pw.CloseWithError(err)
+		checked <- err
+	}()
+	defer func() {
+		pr.Close()
+		if checkErr := <-checked; status.Code(checkErr) == codes.InvalidArgument {
+			refs, err = nil, checkErr
+		}
+	}()
+
+	resp, err := m.client.ImageLoad(ctx, pr, client.ImageLoadWithQuiet(true))
+	if err != nil {
+		if errdefs.IsInvalidParameter(err) {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to load image: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to load image: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if !resp.JSON {
+		return nil, status.Error(codes.Internal, "unable to load image: unexpected non-JSON response")
+	}
+
+	refs = []string{}
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, status.Errorf(codes.Internal, "unable to decode load response: %v", err)
+		}
+		if jm.Error != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to load image: %s", jm.Error.Message)
+		}
+
+		for _, line := range strings.Split(jm.Stream, "\n") {
+			switch {
+			case strings.HasPrefix(line, "Loaded image: "):
+				refs = append(refs, strings.TrimPrefix(line, "Loaded image: "))
+			case strings.HasPrefix(line, "Loaded image ID: "):
+				refs = append(refs, strings.TrimPrefix(line, "Loaded image ID: "))
+			case strings.Contains(line, "already exists"):
+				klog.Infof("image import replaced an existing image: %s", line)
+			}
+		}
+	}
+
+	if len(refs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no images found in archive")
+	}
+	return refs, nil
+}
+
+// Image archive formats.
+const (
+	formatDockerArchive = "docker-archive"
+	formatOCILayout     = "oci-layout"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// checkArchive reads the whole image archive from r and ensures that it is a docker archive
+// (a manifest.json, or a repositories file for archives predating it) or an OCI image layout (an
+// oci-layout file and an index.json). Archives written by recent runtimes are both. Format errors
+// are InvalidArgument; any other error is a failure to read r.
+func checkArchive(r io.Reader) (err error) {
+	br := bufio.NewReader(r)
+	// Whatever the check did not read must still be read for it to reach the runtime. A rejected
+	// archive is cut short instead.
+	defer func() {
+		if err == nil {
+			_, err = io.Copy(io.Discard, br)
+		}
+	}()
+
+	magic, _ := br.Peek(len(xzMagic))
+	var tr *tar.Reader
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return archiveError(err, "image archive is not valid gzip: %v")
+		}
+		tr = tar.NewReader(zr)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		tr = tar.NewReader(bzip2.NewReader(br))
+	case bytes.HasPrefix(magic, xzMagic), bytes.HasPrefix(magic, zstdMagic):
+		// There is no decompressor for these in the standard library: leave it to the runtime.
+		klog.Infof("image archive is xz or zstd compressed, not checking its format")
+		return nil
+	default:
+		tr = tar.NewReader(br)
+	}
+
+	files := map[string]bool{}
+	entries := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return archiveError(err, "image archive is not a valid tar archive: %v")
+		}
+		entries++
+		files[path.Clean(hdr.Name)] = true
+	}
+	if entries == 0 {
+		return status.Error(codes.InvalidArgument, "no images found in archive")
+	}
+
+	docker := files["manifest.json"] || files["repositories"]
+	switch oci := files["oci-layout"]; {
+	case oci && !files["index.json"]:
+		return status.Errorf(codes.InvalidArgument, "image archive looks like an %s but has no index.json", formatOCILayout)
+	case oci && docker:
+		klog.Infof("image archive is both a %s and an %s", formatDockerArchive, formatOCILayout)
+	case oci:
+		klog.Infof("image archive is an %s", formatOCILayout)
+	case docker:
+		klog.Infof("image archive is a %s", formatDockerArchive)
+	case files["index.json"]:
+		return status.Errorf(codes.InvalidArgument, "image archive has an index.json but no oci-layout file, it is not a complete %s", formatOCILayout)
+	default:
+		return status.Errorf(codes.InvalidArgument, "unrecognized image archive: expected a %s (manifest.json) or an %s (oci-layout and index.json)", formatDockerArchive, formatOCILayout)
+	}
+	return nil
+}
+
+// archiveError returns an InvalidArgument error formatted from format and err if err reports a
+// malformed archive, and err itself if it is a failure to read or pass on the archive.
+func archiveError(err error, format string) error {
+	var structural bzip2.StructuralError
+	if errors.Is(err, tar.ErrHeader) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.As(err, &structural) {
+		return status.Errorf(codes.InvalidArgument, format, err)
+	}
+	return err
+}
@@ -0,0 +1,246 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/moby/moby/pkg/jsonmessage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeImportingDocker struct {
+	fakeDocker
+	msgs []*jsonmessage.JSONMessage
+
+	Archive string
+}
+
+func (f *fakeImportingDocker) ImageLoad(_ context.Context, input io.Reader, _ ...client.ImageLoadOption) (image.LoadResponse, error) {
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return image.LoadResponse{}, err
+	}
+	f.Archive = string(data)
+
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, msg := range f.msgs {
+		if err := enc.Encode(msg); err != nil {
+			return image.LoadResponse{}, err
+		}
+	}
+
+	return image.LoadResponse{
+		Body: io.NopCloser(buf),
+		JSON: true,
+	}, nil
+}
+
+// tarArchive returns a tar archive holding an empty JSON document at each of the paths.
+func tarArchive(t *testing.T, paths ...string) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, p := range paths {
+		if err := tw.WriteHeader(&tar.Header{Name: p, Mode: 0o644, Size: 2}); err != nil {
+			t.Fatalf("unable to write tar header: %v", err)
+		}
+		if _, err := tw.Write([]byte("{}")); err != nil {
+			t.Fatalf("unable to write tar entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("unable to close tar archive: %v", err)
+	}
+	return buf.String()
+}
+
+// gzipped returns data compressed with gzip.
+func gzipped(t *testing.T, data string) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatalf("unable to compress archive: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("unable to compress archive: %v", err)
+	}
+	return buf.String()
+}
+
+func TestImageImport(t *testing.T) {
+	dockerArchive := tarArchive(t, "manifest.json", "repositories", "sha256/layer.tar")
+	ociLayout := tarArchive(t, "oci-layout", "index.json", "blobs/sha256/aaaa")
+	loaded := []*jsonmessage.JSONMessage{{Stream: "Loaded image: some-image:some-tag\n"}}
+
+	tests := []struct {
+		name      string
+		inArchive string
+		inMsgs    []*jsonmessage.JSONMessage
+		wantState *fakeImportingDocker
+		wantRefs  []string
+		wantErr   error
+	}{
+		{
+			name:      "single-image",
+			inArchive: dockerArchive,
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Stream: "Loaded image: some-image:some-tag\n"},
+			},
+			wantState: &fakeImportingDocker{
+				Archive: dockerArchive,
+			},
+			wantRefs: []string{"some-image:some-tag"},
+		},
+		{
+			name:      "multiple-images",
+			inArchive: dockerArchive,
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Stream: "Loaded image: some-image:some-tag\n"},
+				{Stream: "Loaded image: other-image:other-tag\n"},
+			},
+			wantRefs: []string{"some-image:some-tag", "other-image:other-tag"},
+		},
+		{
+			name:      "untagged-image",
+			inArchive: dockerArchive,
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Stream: "Loaded image ID: sha256:aaaa\n"},
+			},
+			wantRefs: []string{"sha256:aaaa"},
+		},
+		{
+			name:      "existing-image",
+			inArchive: dockerArchive,
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Stream: "The image some-image:some-tag already exists, renaming the old one with ID sha256:aaaa to empty string\n"},
+				{Stream: "Loaded image: some-image:some-tag\n"},
+			},
+			wantRefs: []string{"some-image:some-tag"},
+		},
+		{
+			name:      "load-error",
+			inArchive: dockerArchive,
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Error: &jsonmessage.JSONError{Message: "unexpected EOF"}},
+			},
+			wantErr: status.Error(codes.InvalidArgument, "unable to load image: unexpected EOF"),
+		},
+		{
+			name:      "oci-layout",
+			inArchive: ociLayout,
+			inMsgs:    loaded,
+			wantState: &fakeImportingDocker{
+				Archive: ociLayout,
+			},
+			wantRefs: []string{"some-image:some-tag"},
+		},
+		{
+			name:      "docker-archive-and-oci-layout",
+			inArchive: tarArchive(t, "oci-layout", "index.json", "manifest.json", "blobs/sha256/aaaa"),
+			inMsgs:    loaded,
+			wantRefs:  []string{"some-image:some-tag"},
+		},
+		{
+			name:      "legacy-docker-archive",
+			inArchive: tarArchive(t, "repositories", "aaaa/layer.tar", "aaaa/json"),
+			inMsgs:    loaded,
+			wantRefs:  []string{"some-image:some-tag"},
+		},
+		{
+			name:      "gzipped-oci-layout",
+			inArchive: gzipped(t, ociLayout),
+			inMsgs:    loaded,
+			wantState: &fakeImportingDocker{
+				Archive: gzipped(t, ociLayout),
+			},
+			wantRefs: []string{"some-image:some-tag"},
+		},
+		{
+			name:      "oci-layout-without-index",
+			inArchive: tarArchive(t, "oci-layout", "blobs/sha256/aaaa"),
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "image archive looks like an oci-layout but has no index.json"),
+		},
+		{
+			name:      "index-without-oci-layout",
+			inArchive: tarArchive(t, "index.json", "blobs/sha256/aaaa"),
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "image archive has an index.json but no oci-layout file, it is not a complete oci-layout"),
+		},
+		{
+			name:      "unrecognized-tar",
+			inArchive: tarArchive(t, "etc/hostname"),
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "unrecognized image archive: expected a docker-archive (manifest.json) or an oci-layout (oci-layout and index.json)"),
+		},
+		{
+			name:      "not-a-tar",
+			inArchive: "some-archive",
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "image archive is not a valid tar archive: unexpected EOF"),
+		},
+		{
+			name:      "truncated-archive",
+			inArchive: dockerArchive[:1100],
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "image archive is not a valid tar archive: unexpected EOF"),
+		},
+		{
+			name:      "corrupt-gzip",
+			inArchive: gzipped(t, dockerArchive)[:40],
+			inMsgs:    loaded,
+			wantErr:   status.Error(codes.InvalidArgument, "image archive is not a valid tar archive: unexpected EOF"),
+		},
+		{
+			name:      "empty-archive",
+			inArchive: "",
+			wantErr:   status.Error(codes.InvalidArgument, "no images found in archive"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fd := &fakeImportingDocker{
+				msgs: tc.inMsgs,
+			}
+			mgr := New(fd)
+
+			refs, err := mgr.ImageImport(context.Background(), strings.NewReader(tc.inArchive))
+			if err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ImageImport(archive) returned unexpected error(-want, got):\n %s", diff)
+					}
+					return
+				}
+				t.Errorf("ImageImport(archive) returned error: %v", err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ImageImport(archive) did not return an error, want %v", tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantRefs, refs); diff != "" {
+				t.Errorf("ImageImport(archive) returned diff(-want, +got):\n%s", diff)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fd, cmpopts.IgnoreUnexported(fakeImportingDocker{})); diff != "" {
+					t.Errorf("ImageImport(archive) returned diff(-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
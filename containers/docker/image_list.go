@@ -3,11 +3,16 @@ package docker
 import (
 	"context"
 	"io"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/distribution/reference"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	cpb "github.com/openconfig/gnoi/containerz"
 )
@@ -26,7 +31,7 @@ func (m *Manager) ImageList(ctx context.Context, all bool, limit int32, srv opti
 	}
 	imgOpts.Filters = filters.NewArgs(kvPairs...)
 
-	images, err := m.client.ImageList(ctx, imgOpts)
+	images, err := m.listImages(ctx, imgOpts)
 	if err != nil {
 		return err
 	}
@@ -67,3 +72,90 @@ func imageToResponse(image image.Summary) *cpb.ListImageResponse {
 		Tag:       strings.Join(tags, ","),
 	}
 }
+
+// noneRef stands in for the repository and tag of a dangling image, as the runtime reports them.
+const noneRef = "<none>"
+
+// Images describes every image present on the target, with one entry per reference it is tagged
+// as. Dangling images, which are not tagged, are reported once as <none>:<none>. Entries are
+// ordered by repository, tag and then ID.
+func (m *Manager) Images(ctx context.Context) ([]*options.ImageInfo, error) {
+	images, err := m.listImages(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list images: %v", contextError(ctx, err))
+	}
+
+	var infos []*options.ImageInfo
+	for _, img := range images {
+		tagged := false
+		for _, tag := range img.RepoTags {
+			repo, t, ok := splitTag(tag)
+			if !ok {
+				continue
+			}
+			tagged = true
+			infos = append(infos, &options.ImageInfo{
+				ID:         img.ID,
+				Repository: repo,
+				Tag:        t,
+				Digest:     repoDigest(img.RepoDigests, repo),
+				SizeBytes:  img.Size,
+				Created:    time.Unix(img.Created, 0).UTC(),
+			})
+		}
+		if !tagged {
+			infos = append(infos, &options.ImageInfo{
+				ID:         img.ID,
+				Repository: noneRef,
+				Tag:        noneRef,
+				Digest:     repoDigest(img.RepoDigests, ""),
+				SizeBytes:  img.Size,
+				Created:    time.Unix(img.Created, 0).UTC(),
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		a, b := infos[i], infos[j]
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.Tag != b.Tag {
+			return a.Tag < b.Tag
+		}
+		return a.ID < b.ID
+	})
+	return infos, nil
+}
+
+// splitTag splits a tagged reference reported by the runtime into its repository and tag. The
+// <none>:<none> placeholder, and references without a tag, are reported as not tagged.
+func splitTag(ref string) (string, string, bool) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", "", false
+	}
+	tagged, ok := named.(reference.Tagged)
+	if !ok {
+		return "", "", false
+	}
+	return reference.FamiliarName(named), tagged.Tag(), true
+}
+
+// repoDigest returns the digest of the image in repo among digests, the name@digest references
+// reported by the runtime. If repo is empty, the first valid digest is returned.
+func repoDigest(digests []string, repo string) string {
+	for _, d := range digests {
+		named, err := reference.ParseNormalizedNamed(d)
+		if err != nil {
+			continue
+		}
+		canonical, ok := named.(reference.Canonical)
+		if !ok {
+			continue
+		}
+		if repo == "" || reference.FamiliarName(named) == repo {
+			return canonical.Digest().String()
+		}
+	}
+	return ""
+}
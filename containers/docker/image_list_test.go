@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -183,3 +184,58 @@ func TestImageList(t *testing.T) {
 		})
 	}
 }
+
+func TestImages(t *testing.T) {
+	const (
+		digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	fd := &fakeImageListingDocker{
+		imgs: []image.Summary{
+			{
+				ID:          "sha256:web",
+				RepoTags:    []string{"registry.example.com/web:v2", "registry.example.com/web:latest"},
+				RepoDigests: []string{"registry.example.com/web@" + digestA},
+				Size:        2048,
+				Created:     created.Unix(),
+			},
+			{
+				ID:       "sha256:dangling-2",
+				RepoTags: []string{"<none>:<none>"},
+				Size:     512,
+				Created:  created.Unix(),
+			},
+			{
+				ID:       "sha256:db",
+				RepoTags: []string{"db:15"},
+				Size:     4096,
+				Created:  created.Unix(),
+			},
+			{
+				ID:          "sha256:dangling-1",
+				RepoDigests: []string{"registry.example.com/web@" + digestB},
+				Size:        1024,
+				Created:     created.Unix(),
+			},
+		},
+	}
+	mgr := New(fd)
+
+	got, err := mgr.Images(context.Background())
+	if err != nil {
+		t.Fatalf("Images() returned error: %v", err)
+	}
+
+	want := []*options.ImageInfo{
+		{ID: "sha256:dangling-1", Repository: "<none>", Tag: "<none>", Digest: digestB, SizeBytes: 1024, Created: created},
+		{ID: "sha256:dangling-2", Repository: "<none>", Tag: "<none>", SizeBytes: 512, Created: created},
+		{ID: "sha256:db", Repository: "db", Tag: "15", SizeBytes: 4096, Created: created},
+		{ID: "sha256:web", Repository: "registry.example.com/web", Tag: "latest", Digest: digestA, SizeBytes: 2048, Created: created},
+		{ID: "sha256:web", Repository: "registry.example.com/web", Tag: "v2", Digest: digestA, SizeBytes: 2048, Created: created},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Images() returned diff(-want, +got):\n%s", diff)
+	}
+}
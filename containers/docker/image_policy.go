@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// WithRequiredImageLabels refuses to start containers from images whose configuration lacks any
+// of labels, or carries one with a different value, e.g. to only run approved images. An empty
+// value only requires the label to be present. Without labels, images are not checked.
+func WithRequiredImageLabels(labels map[string]string) Option {
+	return func(m *Manager) {
+		m.requiredImageLabels = labels
+	}
+}
+
+// checkImageLabels ensures that the labels of the image identified by ref satisfy the required
+// labels, returning a FailedPrecondition error naming the labels missing or mismatched.
+func checkImageLabels(ref string, required, labels map[string]string) error {
+	var missing, mismatched []string
+	for _, key := range slices.Sorted(maps.Keys(required)) {
+		value, ok := labels[key]
+		switch {
+		case !ok:
+			missing = append(missing, key)
+		case required[key] != "" && value != required[key]:
+			mismatched = append(mismatched, key)
+		}
+	}
+	if len(missing) == 0 && len(mismatched) == 0 {
+		return nil
+	}
+
+	metadata := map[string]string{"image": ref}
+	var problems []string
+	if len(missing) > 0 {
+		metadata["missing"] = strings.Join(missing, ",")
+		problems = append(problems, "missing labels "+strings.Join(missing, ", "))
+	}
+	if len(mismatched) > 0 {
+		metadata["mismatched"] = strings.Join(mismatched, ",")
+		problems = append(problems, "mismatched labels "+strings.Join(mismatched, ", "))
+	}
+	return detailedError(codes.FailedPrecondition, reasonImagePolicy, metadata,
+		"image %s does not satisfy the label policy: %s", ref, strings.Join(problems, "; "))
+}
@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+)
+
+func TestContainerStartImagePolicy(t *testing.T) {
+	policy := map[string]string{"approved": "true", "signed-by": ""}
+
+	tests := []struct {
+		name     string
+		inPolicy map[string]string
+		inLabels map[string]string
+		wantErr  error
+	}{
+		{
+			name:     "satisfied",
+			inPolicy: policy,
+			inLabels: map[string]string{"approved": "true", "signed-by": "release-team", "version": "1.2"},
+		},
+		{
+			name:     "missing",
+			inPolicy: policy,
+			inLabels: map[string]string{"approved": "true"},
+			wantErr:  detailedError(codes.FailedPrecondition, reasonImagePolicy, map[string]string{"image": "my-image:my-tag", "missing": "signed-by"}, "image my-image:my-tag does not satisfy the label policy: missing labels signed-by"),
+		},
+		{
+			name:     "missing-and-mismatched",
+			inPolicy: policy,
+			inLabels: map[string]string{"approved": "false"},
+			wantErr: detailedError(codes.FailedPrecondition, reasonImagePolicy, map[string]string{"image": "my-image:my-tag", "missing": "signed-by", "mismatched": "approved"},
+				"image my-image:my-tag does not satisfy the label policy: missing labels signed-by; mismatched labels approved"),
+		},
+		{
+			name:    "no-policy",
+			wantErr: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}, Labels: tc.inLabels}}}
+			mgr := New(fsd, WithRequiredImageLabels(tc.inPolicy))
+
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("my-instance"))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if created := fsd.ContainerID != ""; created != (tc.wantErr == nil) {
+				t.Errorf("ContainerStart() created a container: %t, want %t", created, tc.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package docker
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ImagePrune removes images that are not used by any container, whether running or stopped. If
+// danglingOnly is set only untagged images are removed, otherwise all unused images are. It
+// returns the IDs of the deleted images and the number of bytes reclaimed.
+func (m *Manager) ImagePrune(ctx context.Context, danglingOnly bool) ([]string, uint64, error) {
+	defer m.images.invalidate()
+
+	report, err := m.client.ImagesPrune(ctx, filters.NewArgs(filters.Arg("dangling", strconv.FormatBool(danglingOnly))))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deleted := []string{}
+	for _, resp := range report.ImagesDeleted {
+		if resp.Deleted != "" {
+			deleted = append(deleted, resp.Deleted)
+		}
+	}
+	return deleted, report.SpaceReclaimed, nil
+}
@@ -0,0 +1,102 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type fakeImagePruningDocker struct {
+	fakeDocker
+	report image.PruneReport
+
+	Dangling []string
+}
+
+func (f *fakeImagePruningDocker) ImagesPrune(_ context.Context, args filters.Args) (image.PruneReport, error) {
+	f.Dangling = args.Get("dangling")
+	return f.report, nil
+}
+
+func TestImagePrune(t *testing.T) {
+	tests := []struct {
+		name           string
+		inDanglingOnly bool
+		inReport       image.PruneReport
+		wantState      *fakeImagePruningDocker
+		wantDeleted    []string
+		wantReclaimed  uint64
+	}{
+		{
+			name:           "dangling-only",
+			inDanglingOnly: true,
+			inReport: image.PruneReport{
+				ImagesDeleted: []image.DeleteResponse{
+					{Deleted: "sha256:aaaa"},
+				},
+				SpaceReclaimed: 1024,
+			},
+			wantState: &fakeImagePruningDocker{
+				Dangling: []string{"true"},
+			},
+			wantDeleted:   []string{"sha256:aaaa"},
+			wantReclaimed: 1024,
+		},
+		{
+			name: "all-unused",
+			inReport: image.PruneReport{
+				ImagesDeleted: []image.DeleteResponse{
+					{Untagged: "some-image:latest"},
+					{Deleted: "sha256:aaaa"},
+					{Deleted: "sha256:bbbb"},
+				},
+				SpaceReclaimed: 4096,
+			},
+			wantState: &fakeImagePruningDocker{
+				Dangling: []string{"false"},
+			},
+			wantDeleted:   []string{"sha256:aaaa", "sha256:bbbb"},
+			wantReclaimed: 4096,
+		},
+		{
+			name:           "nothing-to-prune",
+			inDanglingOnly: true,
+			wantState: &fakeImagePruningDocker{
+				Dangling: []string{"true"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			fpd := &fakeImagePruningDocker{
+				report: tc.inReport,
+			}
+			mgr := New(fpd)
+
+			deleted, reclaimed, err := mgr.ImagePrune(ctx, tc.inDanglingOnly)
+			if err != nil {
+				t.Errorf("ImagePrune(%t) returned error: %v", tc.inDanglingOnly, err)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, fpd, cmpopts.IgnoreUnexported(fakeImagePruningDocker{})); diff != "" {
+					t.Errorf("ImagePrune(%t) returned diff(-want, +got):\n%s", tc.inDanglingOnly, diff)
+				}
+			}
+
+			if diff := cmp.Diff(tc.wantDeleted, deleted, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("ImagePrune(%t) returned diff(-want, +got):\n%s", tc.inDanglingOnly, diff)
+			}
+
+			if reclaimed != tc.wantReclaimed {
+				t.Errorf("ImagePrune(%t) reclaimed %d bytes, want %d", tc.inDanglingOnly, reclaimed, tc.wantReclaimed)
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
 	"github.com/moby/moby/pkg/jsonmessage"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,38 +22,50 @@ import (
 // ImagePull pull a container from a registry to this containerz server. Based on the options
 // specified  it can tag the container, stream responses to the client, and perform registry
 // authentication.
-func (m *Manager) ImagePull(ctx context.Context, imageName, tag string, opts ...options.Option) error {
-	switch {
-	case imageName == "":
+func (m *Manager) ImagePull(ctx context.Context, imageName, tag string, opts ...options.Option) (err error) {
+	if imageName == "" {
 		return status.Error(codes.InvalidArgument, "an image name must be supplied.")
-	case tag == "":
-		tag = "latest"
 	}
+	ref, err := normalizeReference(imageName, tag)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	defer func() { m.emit(ctx, OperationImagePull, start, "", ref, err) }()
 
 	options := options.ApplyOptions(opts...)
 
-	auth, err := m.registryLogin(options.Credentials)
+	auth, err := registryAuth(options.Credentials)
+	if err != nil {
+		return err
+	}
+
+	platform, err := ociPlatform(options.Platform)
 	if err != nil {
 		return err
 	}
 
-	resp, err := m.client.ImagePull(ctx, fmt.Sprintf("%s:%s", imageName, tag), image.PullOptions{
-		RegistryAuth: auth.IdentityToken,
+	// The cache is invalidated once the pull, and any retag, completes.
+	defer m.images.invalidate()
+
+	resp, err := m.client.ImagePull(ctx, ref, image.PullOptions{
+		RegistryAuth: auth,
+		Platform:     platformString(platform),
 	})
 	if err != nil {
-		return status.Errorf(codes.Internal, "unable to pull container: %v", err)
+		return pullError(ref, err)
 	}
 	defer resp.Close()
+	defer closeOnCancel(ctx, resp)()
 
-	if options.StreamClient != nil {
-		if err := streamOutput(options.StreamClient, resp); err != nil {
-			// TODO(alshabib): should we ignore this error and only log it?
-			return err
-		}
+	// The pull only completes once its output has been consumed, and errors are reported in
+	// the output rather than by the call itself, so always read it to the end.
+	if err := streamOutput(options.StreamClient, ref, resp); err != nil {
+		return contextError(ctx, err)
 	}
 
 	if options.TargetName != "" && options.TargetTag != "" {
-		if err := m.client.ImageTag(ctx, fmt.Sprintf("%s:%s", imageName, tag), fmt.Sprintf("%s:%s", options.TargetName, options.TargetTag)); err != nil {
+		if err := m.client.ImageTag(ctx, ref, fmt.Sprintf("%s:%s", options.TargetName, options.TargetTag)); err != nil {
 			return status.Errorf(codes.Internal, "unable to tag container: %v", err)
 		}
 	}
@@ -58,34 +73,118 @@ func (m *Manager) ImagePull(ctx context.Context, imageName, tag string, opts ...
 	return nil
 }
 
-func (m *Manager) registryLogin(creds *tpb.Credentials) (registry.AuthenticateOKBody, error) {
-	if creds == nil {
-		return registry.AuthenticateOKBody{}, nil
+// registryAuth encodes the provided credentials into the form expected by the registry. If no
+// credentials are provided the pull is anonymous. A password supplied without a username is
+// treated as an identity token.
+func registryAuth(creds *tpb.Credentials) (string, error) {
+	if creds == nil || (creds.GetUsername() == "" && creds.GetPassword() == nil) {
+		return "", nil
+	}
+
+	if creds.GetHashed() != nil {
+		return "", status.Error(codes.InvalidArgument, "hashed passwords cannot be used for registry authentication")
+	}
+
+	authConfig := registry.AuthConfig{Username: creds.GetUsername()}
+	switch {
+	case creds.GetUsername() == "":
+		authConfig.IdentityToken = creds.GetCleartext()
+	default:
+		authConfig.Password = creds.GetCleartext()
+	}
+
+	auth, err := registry.EncodeAuthConfig(authConfig)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "unable to encode registry credentials: %v", err)
+	}
+	return auth, nil
+}
+
+// isAuthError reports whether err was caused by the registry rejecting the credentials. The
+// daemon does not always preserve the error type, so the message is also checked.
+func isAuthError(err error) bool {
+	return errdefs.IsUnauthorized(err) || strings.Contains(err.Error(), "unauthorized")
+}
+
+// isNotFoundError reports whether err was caused by the registry not knowing the repository or
+// the tag being pulled. As with isAuthError, the message is also checked.
+func isNotFoundError(err error) bool {
+	if errdefs.IsNotFound(err) {
+		return true
 	}
+	msg := err.Error()
+	return strings.Contains(msg, "manifest unknown") || strings.Contains(msg, "repository does not exist") || strings.Contains(msg, "not found")
+}
 
-	return registry.AuthenticateOKBody{}, status.Error(codes.Unimplemented, "registry auth not yet implemented")
+// pullError converts an error reported while pulling ref, either by the pull itself or in its
+// progress stream, to a status error. Authentication failures are checked first as registries
+// commonly report a private repository as missing to anonymous clients.
+func pullError(ref string, err error) error {
+	switch {
+	case isAuthError(err):
+		return status.Errorf(codes.Unauthenticated, "unable to authenticate with registry: %v", err)
+	case isNotFoundError(err):
+		return detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": ref}, "image %s not found in registry: %v", ref, err)
+	default:
+		return status.Errorf(codes.Internal, "unable to pull container: %v", err)
+	}
 }
 
-func streamOutput(srv options.Stream, resp io.ReadCloser) error {
+// streamOutput reads the JSON progress messages of a pull of ref and, if srv is set, reports the
+// total number of bytes downloaded across all layers to it. Errors embedded in the progress
+// stream are returned as RPC errors.
+func streamOutput(srv options.Stream, ref string, resp io.ReadCloser) error {
 	dec := json.NewDecoder(resp)
 
+	// Bytes downloaded, and the expected size, of each layer, keyed by layer ID.
+	downloaded := map[string]int64{}
+	sizes := map[string]int64{}
+	var reported int64
+
 	for {
 		var jm jsonmessage.JSONMessage
 		if err := dec.Decode(&jm); err != nil {
 			if err == io.EOF {
 				break
 			}
-			return err
+			return status.Errorf(codes.Internal, "unable to decode pull progress: %v", err)
+		}
+
+		if jm.Error != nil {
+			return pullError(ref, jm.Error)
+		}
+
+		switch {
+		case jm.Status == "Download complete" || jm.Status == "Pull complete":
+			// The final progress update for a layer may not reach its full size.
+			if size, ok := sizes[jm.ID]; ok {
+				downloaded[jm.ID] = size
+			}
+		case jm.Status == "Extracting":
+			// Extraction progress covers bytes that were already downloaded.
+			continue
+		case jm.Progress != nil && jm.Progress.Current != 0:
+			downloaded[jm.ID] = jm.Progress.Current
+			if jm.Progress.Total > 0 {
+				sizes[jm.ID] = jm.Progress.Total
+			}
+		default:
+			continue
 		}
 
-		if jm.Progress == nil || jm.Progress.Current == 0 {
+		var total int64
+		for _, n := range downloaded {
+			total += n
+		}
+		if srv == nil || total == reported {
 			continue
 		}
+		reported = total
 
 		if err := srv.Send(&cpb.DeployResponse{
 			Response: &cpb.DeployResponse_ImageTransferProgress{
 				ImageTransferProgress: &cpb.ImageTransferProgress{
-					BytesReceived: uint64(jm.Progress.Current),
+					BytesReceived: uint64(total),
 				},
 			},
 		}); err != nil {
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
 	"github.com/moby/moby/pkg/jsonmessage"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -21,25 +24,49 @@ import (
 
 type fakePullingDocker struct {
 	fakeDocker
+	pullErr error
+	msgs    []*jsonmessage.JSONMessage
+
 	ImageRef  string
 	SourceRef string
 	TargetRef string
+	// Auth is the decoded registry auth passed to the pull, if any.
+	Auth *registry.AuthConfig
+	// Platform is the platform passed to the pull, if any.
+	Platform string
 }
 
 func (f *fakePullingDocker) ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
 	f.ImageRef = ref
-	jm := &jsonmessage.JSONMessage{
-		Progress: &jsonmessage.JSONProgress{
-			Current: 10,
-		},
+	f.Platform = options.Platform
+	if options.RegistryAuth != "" {
+		auth, err := registry.DecodeAuthConfig(options.RegistryAuth)
+		if err != nil {
+			return nil, err
+		}
+		f.Auth = auth
+	}
+	if f.pullErr != nil {
+		return nil, f.pullErr
+	}
+	msgs := f.msgs
+	if msgs == nil {
+		msgs = []*jsonmessage.JSONMessage{{
+			Progress: &jsonmessage.JSONProgress{
+				Current: 10,
+			},
+		}}
 	}
 
-	buf, err := json.Marshal(jm)
-	if err != nil {
-		return nil, err
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, jm := range msgs {
+		if err := enc.Encode(jm); err != nil {
+			return nil, err
+		}
 	}
 
-	return io.NopCloser(bytes.NewReader(buf)), nil
+	return io.NopCloser(buf), nil
 
 }
 
@@ -65,6 +92,7 @@ func TestImagePull(t *testing.T) {
 		inImage   string
 		inTag     string
 		inOpts    []options.Option
+		inPullErr error
 		wantState *fakePullingDocker
 		wantResp  []*cpb.DeployResponse
 		wantErr   error
@@ -81,10 +109,114 @@ func TestImagePull(t *testing.T) {
 			},
 		},
 		{
-			name:    "non-nil-creds",
+			name:    "fully-qualified",
+			inImage: "docker.io/library/some-image",
+			inTag:   "v1",
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image:v1",
+			},
+		},
+		{
+			name:    "digest-pinned",
+			inImage: "some-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+		},
+		{
+			name:    "malformed-reference",
+			inImage: "some-image:v1",
+			inTag:   "extra",
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "some-image:v1:extra": invalid reference format`),
+		},
+		{
+			name:    "platform",
+			inImage: "some-image",
+			inOpts:  []options.Option{options.WithPlatform("linux", "arm", "v7")},
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image:latest",
+				Platform: "linux/arm/v7",
+			},
+		},
+		{
+			name:    "unknown-platform",
+			inImage: "some-image",
+			inOpts:  []options.Option{options.WithPlatform("linux", "sparc", "")},
+			wantErr: status.Error(codes.InvalidArgument, `unknown architecture "sparc" for platform operating system linux`),
+		},
+		{
+			name:    "empty-creds",
 			inImage: "some-image",
 			inOpts:  []options.Option{options.WithRegistryAuth(&tpb.Credentials{})},
-			wantErr: status.Error(codes.Unimplemented, "registry auth not yet implemented"),
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image:latest",
+			},
+		},
+		{
+			name:    "username-and-password",
+			inImage: "some-image",
+			inOpts: []options.Option{options.WithRegistryAuth(&tpb.Credentials{
+				Username: "some-user",
+				Password: &tpb.Credentials_Cleartext{Cleartext: "some-password"},
+			})},
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image:latest",
+				Auth: &registry.AuthConfig{
+					Username: "some-user",
+					Password: "some-password",
+				},
+			},
+		},
+		{
+			name:    "identity-token",
+			inImage: "some-image",
+			inOpts: []options.Option{options.WithRegistryAuth(&tpb.Credentials{
+				Password: &tpb.Credentials_Cleartext{Cleartext: "some-token"},
+			})},
+			wantState: &fakePullingDocker{
+				ImageRef: "some-image:latest",
+				Auth: &registry.AuthConfig{
+					IdentityToken: "some-token",
+				},
+			},
+		},
+		{
+			name:    "hashed-password",
+			inImage: "some-image",
+			inOpts: []options.Option{options.WithRegistryAuth(&tpb.Credentials{
+				Username: "some-user",
+				Password: &tpb.Credentials_Hashed{Hashed: &tpb.HashType{}},
+			})},
+			wantErr: status.Error(codes.InvalidArgument, "hashed passwords cannot be used for registry authentication"),
+		},
+		{
+			name:    "rejected-creds",
+			inImage: "some-image",
+			inOpts: []options.Option{options.WithRegistryAuth(&tpb.Credentials{
+				Username: "some-user",
+				Password: &tpb.Credentials_Cleartext{Cleartext: "expired-password"},
+			})},
+			inPullErr: errdefs.Unauthorized(errors.New("authentication required")),
+			wantErr:   status.Error(codes.Unauthenticated, "unable to authenticate with registry: authentication required"),
+		},
+		{
+			name:      "no-such-repository",
+			inImage:   "registry.example.com/no-such-image",
+			inTag:     "v1",
+			inPullErr: errdefs.NotFound(errors.New("repository registry.example.com/no-such-image not found: name unknown")),
+			wantErr:   detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "registry.example.com/no-such-image:v1"}, "image registry.example.com/no-such-image:v1 not found in registry: repository registry.example.com/no-such-image not found: name unknown"),
+		},
+		{
+			name:      "auth-required-without-creds",
+			inImage:   "registry.example.com/private-image",
+			inPullErr: errors.New("Head \"https://registry.example.com/v2/private-image/manifests/latest\": unauthorized: authentication required"),
+			wantErr:   status.Error(codes.Unauthenticated, "unable to authenticate with registry: Head \"https://registry.example.com/v2/private-image/manifests/latest\": unauthorized: authentication required"),
+		},
+		{
+			name:      "pull-failure",
+			inImage:   "some-image",
+			inPullErr: errors.New("connection refused"),
+			wantErr:   status.Error(codes.Internal, "unable to pull container: connection refused"),
 		},
 		{
 			name: "pull-with-tag",
@@ -144,7 +276,7 @@ func TestImagePull(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			fakeStream.resps = nil
-			fd := &fakePullingDocker{}
+			fd := &fakePullingDocker{pullErr: tc.inPullErr}
 			mgr := New(fd)
 
 			if err := mgr.ImagePull(context.Background(), tc.inImage, tc.inTag, tc.inOpts...); err != nil {
@@ -167,3 +299,102 @@ func TestImagePull(t *testing.T) {
 		})
 	}
 }
+
+func TestImagePullProgress(t *testing.T) {
+	progress := func(n uint64) *cpb.DeployResponse {
+		return &cpb.DeployResponse{
+			Response: &cpb.DeployResponse_ImageTransferProgress{
+				ImageTransferProgress: &cpb.ImageTransferProgress{
+					BytesReceived: n,
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		inMsgs   []*jsonmessage.JSONMessage
+		wantResp []*cpb.DeployResponse
+		wantErr  error
+	}{
+		{
+			name: "multiple-layers",
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Status: "Pulling from library/some-image", ID: "latest"},
+				{Status: "Pulling fs layer", ID: "layer-a"},
+				{Status: "Pulling fs layer", ID: "layer-b"},
+				{Status: "Downloading", ID: "layer-a", Progress: &jsonmessage.JSONProgress{Current: 100, Total: 300}},
+				{Status: "Downloading", ID: "layer-b", Progress: &jsonmessage.JSONProgress{Current: 50, Total: 100}},
+				{Status: "Downloading", ID: "layer-a", Progress: &jsonmessage.JSONProgress{Current: 250, Total: 300}},
+				{Status: "Download complete", ID: "layer-a"},
+				{Status: "Extracting", ID: "layer-a", Progress: &jsonmessage.JSONProgress{Current: 300, Total: 300}},
+				{Status: "Pull complete", ID: "layer-a"},
+				{Status: "Download complete", ID: "layer-b"},
+				{Status: "Pull complete", ID: "layer-b"},
+				{Status: "Digest: sha256:aaaa"},
+				{Status: "Status: Downloaded newer image for some-image:latest"},
+			},
+			wantResp: []*cpb.DeployResponse{
+				progress(100),
+				progress(150),
+				progress(300),
+				progress(350),
+				progress(400),
+			},
+		},
+		{
+			name: "error-in-stream",
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Status: "Downloading", ID: "layer-a", Progress: &jsonmessage.JSONProgress{Current: 100, Total: 300}},
+				{Error: &jsonmessage.JSONError{Message: "read: connection reset by peer"}},
+			},
+			wantResp: []*cpb.DeployResponse{
+				progress(100),
+			},
+			wantErr: status.Error(codes.Internal, "unable to pull container: read: connection reset by peer"),
+		},
+		{
+			name: "unauthorized-in-stream",
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Error: &jsonmessage.JSONError{Message: "unauthorized: authentication required"}},
+			},
+			wantErr: status.Error(codes.Unauthenticated, "unable to authenticate with registry: unauthorized: authentication required"),
+		},
+		{
+			name: "manifest-unknown-in-stream",
+			inMsgs: []*jsonmessage.JSONMessage{
+				{Error: &jsonmessage.JSONError{Message: "manifest unknown: manifest unknown"}},
+			},
+			wantErr: detailedError(codes.NotFound, reasonImageNotFound, map[string]string{"image": "some-image:latest"}, "image some-image:latest not found in registry: manifest unknown: manifest unknown"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stream := &fakeStream{}
+			fd := &fakePullingDocker{msgs: tc.inMsgs}
+			mgr := New(fd)
+
+			err := mgr.ImagePull(context.Background(), "some-image", "latest", options.WithStream(stream))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ImagePull() returned unexpected error(-want, got):\n %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.wantResp, stream.resps, cmpopts.EquateEmpty(), protocmp.Transform()); diff != "" {
+				t.Errorf("ImagePull() returned diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestImagePullErrorWithoutStream(t *testing.T) {
+	fd := &fakePullingDocker{msgs: []*jsonmessage.JSONMessage{
+		{Error: &jsonmessage.JSONError{Message: "read: connection reset by peer"}},
+	}}
+	mgr := New(fd)
+
+	want := status.Error(codes.Internal, "unable to pull container: read: connection reset by peer")
+	if diff := cmp.Diff(want, mgr.ImagePull(context.Background(), "some-image", "latest"), cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("ImagePull() returned unexpected error(-want, got):\n %s", diff)
+	}
+}
@@ -3,7 +3,6 @@ package docker
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"os"
 	"github.com/docker/docker/client"
 	"strings"
@@ -23,6 +22,17 @@ func (m *Manager) ImagePush(ctx context.Context, file *os.File, opts ...options.
 
 	options := options.ApplyOptions(opts...)
 
+	var target string
+	if options.TargetName != "" {
+		ref, err := normalizeReference(options.TargetName, options.TargetTag)
+		if err != nil {
+			return "", "", err
+		}
+		target = ref
+	}
+
+	defer m.images.invalidate()
+
 	resp, err := m.client.ImageLoad(ctx, file, client.ImageLoadWithQuiet(true))
 	if err != nil {
 		return "", "", status.Errorf(codes.Internal, "unable to load image: %v", err)
@@ -38,7 +48,7 @@ func (m *Manager) ImagePush(ctx context.Context, file *os.File, opts ...options.
 
 		imageNameAndTag := extractImageNameFromStream(jm.Stream)
 		if options.TargetName != "" && imageNameAndTag != "" {
-			if err := m.client.ImageTag(ctx, imageNameAndTag, fmt.Sprintf("%s:%s", options.TargetName, options.TargetTag)); err != nil {
+			if err := m.client.ImageTag(ctx, imageNameAndTag, target); err != nil {
 				return "", "", status.Convert(err).Err()
 			}
 			return options.TargetName, options.TargetTag, nil
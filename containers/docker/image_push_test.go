@@ -87,6 +87,15 @@ func TestImagePush(t *testing.T) {
 			wantImage: "another-image",
 			wantTag:   "another-tag",
 		},
+		{
+			name:    "invalid-target",
+			isJSON:  true,
+			inFile:  os.Stdin,
+			inImage: "some-image",
+			inTag:   "some-tag",
+			inOpts:  []options.Option{options.WithTarget("Another-Image", "another-tag")},
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "Another-Image:another-tag": invalid reference format: repository name (library/Another-Image) must be lowercase`),
+		},
 	}
 
 	for _, tc := range tests {
@@ -18,7 +18,7 @@ import (
 func (m *Manager) ImageRemove(ctx context.Context, imageName, tag string, opts ...options.Option) error {
 	option := options.ApplyOptions(opts...)
 
-	images, err := m.client.ImageList(ctx, image.ListOptions{
+	images, err := m.listImages(ctx, image.ListOptions{
 		// TODO(alshabib): consider filtering for the image we care about
 	})
 	if err != nil {
@@ -29,8 +29,9 @@ func (m *Manager) ImageRemove(ctx context.Context, imageName, tag string, opts .
 	if err := findImage(ref, images); err != nil {
 		return err
 	}
+	defer m.images.invalidate()
 
-	cnts, err := m.client.ContainerList(ctx, container.ListOptions{
+	cnts, err := m.listContainers(ctx, container.ListOptions{
 		// TODO(alshabib): consider filtering for the image we care about
 	})
 	if err != nil {
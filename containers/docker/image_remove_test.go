@@ -20,6 +20,8 @@ type fakeRemovingDocker struct {
 	cnts      []types.Container
 
 	Name string
+	// Opts are the options of the last container removal.
+	Opts container.RemoveOptions
 }
 
 func (f fakeRemovingDocker) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
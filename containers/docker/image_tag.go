@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/image"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ImageTag tags the source image as targetRepo:targetTag. The target tag defaults to latest if it
+// is not specified, as does the tag of the source. An existing image with the same target
+// reference is silently retagged.
+func (m *Manager) ImageTag(ctx context.Context, source, targetRepo, targetTag string) error {
+	source, err := normalizeReference(source, "")
+	if err != nil {
+		return err
+	}
+
+	if targetTag == "" {
+		targetTag = "latest"
+	}
+	target := fmt.Sprintf("%s:%s", targetRepo, targetTag)
+	named, err := reference.ParseNormalizedNamed(target)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid target reference %s: %v", target, err)
+	}
+	if _, ok := named.(reference.Canonical); ok {
+		return status.Errorf(codes.InvalidArgument, "invalid target reference %s: digests cannot be tagged", target)
+	}
+
+	images, err := m.listImages(ctx, image.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if err := findImage(source, images); err != nil {
+		return err
+	}
+
+	defer m.images.invalidate()
+	return m.client.ImageTag(ctx, source, reference.FamiliarString(named))
+}
@@ -0,0 +1,168 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeImageTaggingDocker struct {
+	fakeDocker
+	summaries []image.Summary
+
+	Source string
+	Target string
+}
+
+func (f *fakeImageTaggingDocker) ImageList(context.Context, image.ListOptions) ([]image.Summary, error) {
+	return f.summaries, nil
+}
+
+func (f *fakeImageTaggingDocker) ImageTag(_ context.Context, source, target string) error {
+	f.Source = source
+	f.Target = target
+	return nil
+}
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		name         string
+		inSource     string
+		inTargetRepo string
+		inTargetTag  string
+		inSummaries  []image.Summary
+		wantState    *fakeImageTaggingDocker
+		wantErr      error
+	}{
+		{
+			name:         "simple-tag",
+			inSource:     "some-image:v1",
+			inTargetRepo: "some-image",
+			inTargetTag:  "stable",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:v1"}},
+			},
+			wantState: &fakeImageTaggingDocker{
+				Source: "some-image:v1",
+				Target: "some-image:stable",
+			},
+		},
+		{
+			name:         "default-tag",
+			inSource:     "some-image:v1",
+			inTargetRepo: "registry.example.com/other-image",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:v1"}},
+			},
+			wantState: &fakeImageTaggingDocker{
+				Source: "some-image:v1",
+				Target: "registry.example.com/other-image:latest",
+			},
+		},
+		{
+			name:         "overwrite-existing-tag",
+			inSource:     "some-image:v2",
+			inTargetRepo: "some-image",
+			inTargetTag:  "stable",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:v1", "some-image:stable"}},
+				{RepoTags: []string{"some-image:v2"}},
+			},
+			wantState: &fakeImageTaggingDocker{
+				Source: "some-image:v2",
+				Target: "some-image:stable",
+			},
+		},
+		{
+			name:         "source-not-found",
+			inSource:     "some-image:v3",
+			inTargetRepo: "some-image",
+			inTargetTag:  "stable",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:v1"}},
+			},
+			wantErr: status.Error(codes.NotFound, "image some-image:v3 not found"),
+		},
+		{
+			name:         "invalid-target-repo",
+			inSource:     "some-image:v1",
+			inTargetRepo: "Some-Image",
+			inTargetTag:  "stable",
+			wantErr:      status.Error(codes.InvalidArgument, "invalid target reference Some-Image:stable: invalid reference format: repository name (library/Some-Image) must be lowercase"),
+		},
+		{
+			name:         "untagged-source",
+			inSource:     "some-image",
+			inTargetRepo: "some-image",
+			inTargetTag:  "stable",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:latest"}},
+			},
+			wantState: &fakeImageTaggingDocker{
+				Source: "some-image:latest",
+				Target: "some-image:stable",
+			},
+		},
+		{
+			name:         "fully-qualified-target",
+			inSource:     "some-image:v1",
+			inTargetRepo: "docker.io/library/some-image",
+			inTargetTag:  "stable",
+			inSummaries: []image.Summary{
+				{RepoTags: []string{"some-image:v1"}},
+			},
+			wantState: &fakeImageTaggingDocker{
+				Source: "some-image:v1",
+				Target: "some-image:stable",
+			},
+		},
+		{
+			name:         "invalid-source",
+			inSource:     "some-image:v1:extra",
+			inTargetRepo: "some-image",
+			inTargetTag:  "stable",
+			wantErr:      status.Error(codes.InvalidArgument, `invalid image reference "some-image:v1:extra": invalid reference format`),
+		},
+		{
+			name:         "invalid-target-tag",
+			inSource:     "some-image:v1",
+			inTargetRepo: "some-image",
+			inTargetTag:  "-stable",
+			wantErr:      status.Error(codes.InvalidArgument, "invalid target reference some-image:-stable: invalid reference format"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			ftd := &fakeImageTaggingDocker{
+				summaries: tc.inSummaries,
+			}
+			mgr := New(ftd)
+
+			if err := mgr.ImageTag(ctx, tc.inSource, tc.inTargetRepo, tc.inTargetTag); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ImageTag(%q, %q, %q) returned unexpected error(-want, got):\n %s", tc.inSource, tc.inTargetRepo, tc.inTargetTag, diff)
+					}
+					return
+				}
+				t.Errorf("ImageTag(%q, %q, %q) returned error: %v", tc.inSource, tc.inTargetRepo, tc.inTargetTag, err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ImageTag(%q, %q, %q) did not return an error, want %v", tc.inSource, tc.inTargetRepo, tc.inTargetTag, tc.wantErr)
+			}
+
+			if tc.wantState != nil {
+				if diff := cmp.Diff(tc.wantState, ftd, cmpopts.IgnoreUnexported(fakeImageTaggingDocker{})); diff != "" {
+					t.Errorf("ImageTag(%q, %q, %q) returned diff(-want, +got):\n%s", tc.inSource, tc.inTargetRepo, tc.inTargetTag, diff)
+				}
+			}
+		})
+	}
+}
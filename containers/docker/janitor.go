@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"k8s.io/klog/v2"
 )
@@ -15,13 +16,16 @@ var (
 
 // Vacuum cleans a docker container runtime.
 type Vacuum struct {
-	cli  docker
+	cli  Client
 	quit chan struct{}
 	wg   sync.WaitGroup
+
+	// removeSecrets, if set, removes the secrets directory of a pruned container.
+	removeSecrets func(dir string)
 }
 
 // NewJanitor creates a new docker janitor.
-func NewJanitor(cli docker) *Vacuum {
+func NewJanitor(cli Client) *Vacuum {
 	return &Vacuum{
 		cli:  cli,
 		quit: make(chan struct{}),
@@ -58,7 +62,7 @@ func (j *Vacuum) vacuum(ctx context.Context) {
 			klog.Info("janitor was told to quit so it is")
 			return
 		case <-tick.C:
-			cntReport, err := j.cli.ContainersPrune(ctx, filters.NewArgs())
+			cntReport, err := j.pruneContainers(ctx)
 			if err != nil {
 				klog.Errorf("unable to vacuum containers %v", err)
 			}
@@ -73,3 +77,33 @@ func (j *Vacuum) vacuum(ctx context.Context) {
 		}
 	}
 }
+
+// pruneContainers removes the stopped containers along with their secrets directories. The
+// directories are only known from the labels of the containers before they are pruned; if these
+// can not be listed, containers with secrets are left for a later run.
+func (j *Vacuum) pruneContainers(ctx context.Context) (container.PruneReport, error) {
+	if j.removeSecrets == nil {
+		return j.cli.ContainersPrune(ctx, filters.NewArgs())
+	}
+
+	cnts, err := j.cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", secretsLabel)),
+	})
+	if err != nil {
+		klog.Warningf("unable to list the containers with secrets, not pruning them: %v", err)
+		return j.cli.ContainersPrune(ctx, filters.NewArgs(filters.Arg("label!", secretsLabel)))
+	}
+	secrets := make(map[string]string, len(cnts))
+	for _, cnt := range cnts {
+		secrets[cnt.ID] = cnt.Labels[secretsLabel]
+	}
+
+	report, err := j.cli.ContainersPrune(ctx, filters.NewArgs())
+	for _, id := range report.ContainersDeleted {
+		if dir, ok := secrets[id]; ok {
+			j.removeSecrets(dir)
+		}
+	}
+	return report, err
+}
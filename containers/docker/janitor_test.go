@@ -2,12 +2,15 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
 )
 
 type fakeVacuumingDocker struct {
@@ -42,3 +45,67 @@ func TestVacuum(t *testing.T) {
 		t.Errorf("Vacuum did not call the correct api: containers: %t, images: %t", fvd.cntCalled, fvd.imgCalled)
 	}
 }
+
+type fakePruningDocker struct {
+	fakeDocker
+	cnts    []types.Container
+	listErr error
+	deleted []string
+
+	Filters filters.Args
+}
+
+func (f *fakePruningDocker) ContainerList(_ context.Context, _ container.ListOptions) ([]types.Container, error) {
+	return f.cnts, f.listErr
+}
+
+func (f *fakePruningDocker) ContainersPrune(_ context.Context, args filters.Args) (container.PruneReport, error) {
+	f.Filters = args
+	return container.PruneReport{ContainersDeleted: f.deleted}, nil
+}
+
+func TestVacuumSecrets(t *testing.T) {
+	tests := []struct {
+		name        string
+		inListErr   error
+		wantRemoved []string
+		wantFilters filters.Args
+	}{
+		{
+			name:        "pruned",
+			wantRemoved: []string{"/secrets/pruned"},
+			wantFilters: filters.NewArgs(),
+		},
+		{
+			name:        "list-failed",
+			inListErr:   fmt.Errorf("daemon unavailable"),
+			wantFilters: filters.NewArgs(filters.Arg("label!", secretsLabel)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fpd := &fakePruningDocker{
+				cnts: []types.Container{
+					{ID: "pruned-id", Labels: map[string]string{secretsLabel: "/secrets/pruned"}},
+					{ID: "running-id", Labels: map[string]string{secretsLabel: "/secrets/running"}},
+				},
+				listErr: tc.inListErr,
+				deleted: []string{"pruned-id", "no-secrets-id"},
+			}
+			var removed []string
+			jani := NewJanitor(fpd)
+			jani.removeSecrets = func(dir string) { removed = append(removed, dir) }
+
+			if _, err := jani.pruneContainers(context.Background()); err != nil {
+				t.Fatalf("pruneContainers() returned error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantRemoved, removed); diff != "" {
+				t.Errorf("pruneContainers() removed secrets diff(-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantFilters, fpd.Filters, cmp.AllowUnexported(filters.Args{})); diff != "" {
+				t.Errorf("pruneContainers() pruned with filters diff(-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
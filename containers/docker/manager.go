@@ -6,38 +6,68 @@ import (
 	"io"
 	"sync"
 
+	"github.com/docker/docker/api/types/checkpoint"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/volume"
 
+	options "github.com/openconfig/containerz/containers"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
 )
 
-type docker interface {
+// Client is the subset of the docker API client used by the manager. Any runtime exposing a
+// docker-compatible API can be managed through it.
+type Client interface {
+	CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error
+	CheckpointList(ctx context.Context, container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error)
+	ClientVersion() string
 	Close() error
+	ContainerAttach(ctx context.Context, container string, options container.AttachOptions) (types.HijackedResponse, error)
 	ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error)
+	ContainerExecAttach(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error)
+	ContainerExecCreate(ctx context.Context, container string, options container.ExecOptions) (container.ExecCreateResponse, error)
+	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
 	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerPause(ctx context.Context, container string) error
 	ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error
+	ContainerRename(ctx context.Context, container, newContainerName string) error
+	ContainerRestart(ctx context.Context, container string, options container.StopOptions) error
 	ContainerStart(ctx context.Context, container string, options container.StartOptions) error
+	ContainerStats(ctx context.Context, container string, stream bool) (container.StatsResponseReader, error)
 	ContainerStop(ctx context.Context, container string, options container.StopOptions) error
+	ContainerUnpause(ctx context.Context, container string) error
+	ContainerUpdate(ctx context.Context, container string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error)
+	CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, container.PathStat, error)
+	CopyToContainer(ctx context.Context, container, path string, content io.Reader, options container.CopyToContainerOptions) error
+	DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
 	ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
 	ImageLoad(ctx context.Context, input io.Reader, options ...client.ImageLoadOption) (image.LoadResponse, error)
 	ImagePull(ctx context.Context, ref string, options image.PullOptions) (io.ReadCloser, error)
 	ImageRemove(ctx context.Context, image string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	ImageSave(ctx context.Context, imageIDs []string, options ...client.ImageSaveOption) (io.ReadCloser, error)
 	ImageTag(ctx context.Context, source, target string) error
+	Info(ctx context.Context) (system.Info, error)
 	PluginCreate(ctx context.Context, createContext io.Reader, createOptions types.PluginCreateOptions) error
 	PluginEnable(ctx context.Context, name string, options types.PluginEnableOptions) error
 	PluginDisable(ctx context.Context, name string, options types.PluginDisableOptions) error
+	PluginInstall(ctx context.Context, name string, options types.PluginInstallOptions) (io.ReadCloser, error)
 	PluginRemove(ctx context.Context, name string, options types.PluginRemoveOptions) error
 	PluginList(ctx context.Context, filter filters.Args) (types.PluginsListResponse, error)
 	RegistryLogin(ctx context.Context, auth registry.AuthConfig) (registry.AuthenticateOKBody, error)
+	ServerVersion(ctx context.Context) (types.Version, error)
 	VolumeCreate(ctx context.Context, options volume.CreateOptions) (volume.Volume, error)
 	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
@@ -48,29 +78,148 @@ type docker interface {
 
 // Manager is a docker container orchestration manager.
 type Manager struct {
-	client           docker
+	client           Client
 	janitor          *Vacuum
+	images           *imageCache
 	updateInProgress map[string]struct{}
 	mu               sync.Mutex
+
+	// allowPrivileged is the operator's opt-in to start containers in privileged mode.
+	allowPrivileged bool
+	// allowDockerSocket is the operator's opt-in to let containers bind mount the docker socket.
+	allowDockerSocket bool
+	// checkCapacity is the operator's opt-in to reject containers requesting more CPU or memory
+	// than the host has.
+	checkCapacity bool
+	// events, if set, receives an event for every lifecycle operation.
+	events EventSink
+	// metrics, if set, records the outcome and latency of every lifecycle operation.
+	metrics Metrics
+	// tenantLabel, if set, is the label scoping the uniqueness of instance names to a tenant.
+	tenantLabel string
+	// namePrefix, if set, prefixes the names of the containers of every instance.
+	namePrefix string
+	// requiredImageLabels, if set, are the labels images must carry to start containers.
+	requiredImageLabels map[string]string
+	// secretsDir is the tmpfs directory of the host holding the files of container secrets.
+	secretsDir string
+
+	// runtimes is the set of OCI runtimes the daemon is configured with, once known.
+	runtimes   map[string]bool
+	runtimesMu sync.Mutex
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+var _ options.ContainerManager = (*Manager)(nil)
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithAllowPrivileged allows containers to be started in privileged mode. Without it, requests
+// for privileged containers are denied.
+func WithAllowPrivileged() Option {
+	return func(m *Manager) {
+		m.allowPrivileged = true
+	}
 }
 
-// New builds a new docker manager given a docker client.
-func New(cli docker) *Manager {
-	return &Manager{
+// WithAllowDockerSocket allows containers to bind mount the docker socket, provided each request
+// also acknowledges it with WithAcknowledgeDockerSocket. Without it, such mounts are denied.
+func WithAllowDockerSocket() Option {
+	return func(m *Manager) {
+		m.allowDockerSocket = true
+	}
+}
+
+// WithCapacityCheck rejects containers whose CPU or hard memory limit exceeds the total capacity
+// of the host. It is off by default so that hosts deliberately overcommitting are not affected.
+func WithCapacityCheck() Option {
+	return func(m *Manager) {
+		m.checkCapacity = true
+	}
+}
+
+// WithSecretsDir sets the directory of the host in which the files of container secrets are
+// kept. It must be on a tmpfs so that secrets are never written to disk. It defaults to
+// /run/containerz/secrets.
+func WithSecretsDir(dir string) Option {
+	return func(m *Manager) {
+		m.secretsDir = dir
+	}
+}
+
+// WithTenantLabel scopes the uniqueness of instance names to the tenants identified by the
+// value of label. A container started with the label is named <tenant>.<instance> and only
+// conflicts with the containers of its tenant. Containers without the label keep names unique
+// among all containers.
+func WithTenantLabel(label string) Option {
+	return func(m *Manager) {
+		m.tenantLabel = label
+	}
+}
+
+// New builds a new docker manager given a docker client. The client is shared by every
+// operation of the manager, and therefore by concurrent RPCs, so it must be safe for concurrent
+// use; the docker API client is.
+func New(cli Client, opts ...Option) *Manager {
+	m := &Manager{
 		client:           cli,
 		janitor:          NewJanitor(cli),
+		images:           newImageCache(),
 		updateInProgress: make(map[string]struct{}),
+		secretsDir:       defaultSecretsDir,
+	}
+	m.janitor.removeSecrets = m.removeSecrets
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Start starts a docker session to the host
 func (m *Manager) Start(ctx context.Context) error {
 	m.janitor.Start(ctx)
+	// The runtimes are otherwise queried by the first start requesting one.
+	if _, err := m.knownRuntimes(ctx); err != nil {
+		klog.Warningf("unable to query the runtimes of the daemon: %v", err)
+	}
 	return nil
 }
 
-// Stop closes the connection to the docker server.
+// Stop stops the janitor and closes the connection to the docker server.
 func (m *Manager) Stop(ctx context.Context) error {
 	m.janitor.Stop(ctx)
-	return m.client.Close()
+	return m.Close()
+}
+
+// Close releases the connection to the docker server. It is safe to call more than once.
+func (m *Manager) Close() error {
+	m.closeOnce.Do(func() {
+		m.closeErr = m.client.Close()
+	})
+	return m.closeErr
+}
+
+// closeOnCancel closes rc if ctx is cancelled before the returned function is called. It unblocks
+// reads from response streams whose producer has stopped responding.
+func closeOnCancel(ctx context.Context, rc io.Closer) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			rc.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// contextError returns the RPC error matching ctx if it is done, and err otherwise.
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return err
 }
@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/docker/docker/api/types/checkpoint"
 	"github.com/docker/docker/client"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/volume"
 
@@ -24,6 +29,18 @@ type fakeDocker struct {
 	CloseCalled bool
 }
 
+func (fakeDocker) CheckpointCreate(ctx context.Context, container string, options checkpoint.CreateOptions) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) CheckpointList(ctx context.Context, container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ClientVersion() string {
+	return ""
+}
+
 func (f *fakeDocker) Close() error {
 	f.CloseCalled = true
 	return nil
@@ -33,6 +50,22 @@ func (fakeDocker) ContainerCreate(ctx context.Context, config *container.Config,
 	return container.CreateResponse{}, fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ContainerAttach(ctx context.Context, container string, options container.AttachOptions) (types.HijackedResponse, error) {
+	return types.HijackedResponse{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ContainerExecAttach(ctx context.Context, execID string, config container.ExecAttachOptions) (types.HijackedResponse, error) {
+	return types.HijackedResponse{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ContainerExecCreate(ctx context.Context, _ string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	return container.ExecCreateResponse{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	return container.ExecInspect{}, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error) {
 	return types.ContainerJSON{}, fmt.Errorf("not implemented")
 }
@@ -45,18 +78,64 @@ func (fakeDocker) ContainerList(ctx context.Context, options container.ListOptio
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ContainerPause(ctx context.Context, container string) error {
+	return fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ContainerRename(ctx context.Context, container, newContainerName string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ContainerRestart(ctx context.Context, container string, _ container.StopOptions) error {
+	return fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ContainerStart(ctx context.Context, container string, options container.StartOptions) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ContainerStats(ctx context.Context, _ string, stream bool) (container.StatsResponseReader, error) {
+	return container.StatsResponseReader{}, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ContainerStop(ctx context.Context, container string, _ container.StopOptions) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ContainerUnpause(ctx context.Context, container string) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) ContainerUpdate(ctx context.Context, id string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	return container.ContainerUpdateOKBody{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) CopyFromContainer(ctx context.Context, id, srcPath string) (io.ReadCloser, container.PathStat, error) {
+	return nil, container.PathStat{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) CopyToContainer(ctx context.Context, id, path string, content io.Reader, options container.CopyToContainerOptions) error {
+	return fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	return types.DiskUsage{}, fmt.Errorf("not implemented")
+}
+
+func (fakeDocker) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	errs := make(chan error, 1)
+	errs <- fmt.Errorf("not implemented")
+	return nil, errs
+}
+
+func (fakeDocker) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	return types.ImageBuildResponse{}, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -73,14 +152,26 @@ func (fakeDocker) ImageRemove(ctx context.Context, image string, options image.R
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ImageSave(ctx context.Context, imageIDs []string, options ...client.ImageSaveOption) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) ImageTag(ctx context.Context, source, target string) error {
 	return fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) Info(ctx context.Context) (system.Info, error) {
+	return system.Info{}, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) RegistryLogin(ctx context.Context, auth registry.AuthConfig) (registry.AuthenticateOKBody, error) {
 	return registry.AuthenticateOKBody{}, fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) ServerVersion(ctx context.Context) (types.Version, error) {
+	return types.Version{}, fmt.Errorf("not implemented")
+}
+
 func (fakeDocker) VolumeCreate(ctx context.Context, options volume.CreateOptions) (volume.Volume, error) {
 	return volume.Volume{}, fmt.Errorf("not implemented")
 }
@@ -121,16 +212,21 @@ func (fakeDocker) PluginList(ctx context.Context, filter filters.Args) (types.Pl
 	return types.PluginsListResponse{}, fmt.Errorf("not implemented")
 }
 
+func (fakeDocker) PluginInstall(ctx context.Context, name string, options types.PluginInstallOptions) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func TestNew(t *testing.T) {
 	want := &Manager{
-		client: &fakeDocker{},
+		client:     &fakeDocker{},
+		secretsDir: defaultSecretsDir,
 	}
 
 	got := New(&fakeDocker{})
 
 	opts := []cmp.Option{
 		cmp.AllowUnexported(Manager{}),
-		cmpopts.IgnoreFields(Manager{}, "janitor", "mu"),
+		cmpopts.IgnoreFields(Manager{}, "janitor", "images", "mu", "runtimesMu", "closeOnce"),
 		cmpopts.EquateEmpty(),
 	}
 	if diff := cmp.Diff(want, got, opts...); diff != "" {
@@ -153,3 +249,53 @@ func TestStop(t *testing.T) {
 		t.Errorf("Stop() did not close the underlying docker session.")
 	}
 }
+
+type fakeCountingDocker struct {
+	fakeDocker
+	lists  atomic.Int32
+	closes int
+}
+
+func (f *fakeCountingDocker) Close() error {
+	f.closes++
+	return nil
+}
+
+func (f *fakeCountingDocker) ContainerList(context.Context, container.ListOptions) ([]types.Container, error) {
+	f.lists.Add(1)
+	return nil, nil
+}
+
+func TestManagerReusesClient(t *testing.T) {
+	d := &fakeCountingDocker{}
+	mgr := New(d)
+
+	const calls = 16
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := mgr.ContainerList(context.Background(), true, 0, &fakeListContainerStreamer{}); err != nil {
+				t.Errorf("ContainerList() returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := d.lists.Load(); got != calls {
+		t.Errorf("ContainerList() reached the docker client %d times, want %d", got, calls)
+	}
+	if mgr.client != Client(d) {
+		t.Errorf("Manager client is %p, want the client provided to New() %p", mgr.client, d)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := mgr.Close(); err != nil {
+			t.Errorf("Close() returned error: %v", err)
+		}
+	}
+	if d.closes != 1 {
+		t.Errorf("Close() closed the docker client %d times, want 1", d.closes)
+	}
+}
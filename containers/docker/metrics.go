@@ -0,0 +1,31 @@
+package docker
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records every lifecycle operation performed by the manager, from which counts, error
+// counts by code and latency histograms can be derived, e.g. by Prometheus collectors. Observe is
+// called synchronously as each operation completes, possibly concurrently, so it must be safe for
+// concurrent use and cheap; codes.OK is reported for operations that succeeded.
+type Metrics interface {
+	Observe(op Operation, code codes.Code, latency time.Duration)
+}
+
+// WithMetrics records the count, outcome and latency of lifecycle operations to metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(m *Manager) {
+		m.metrics = metrics
+	}
+}
+
+// observe records the outcome of op, started at start, to the metrics, if any.
+func (m *Manager) observe(op Operation, start time.Time, err error) {
+	if m.metrics == nil {
+		return
+	}
+	m.metrics.Observe(op, status.Code(err), time.Since(start))
+}
@@ -0,0 +1,78 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type observation struct {
+	Operation Operation
+	Code      codes.Code
+}
+
+type recordingMetrics struct {
+	mu           sync.Mutex
+	observations []observation
+	latencies    []time.Duration
+}
+
+func (r *recordingMetrics) Observe(op Operation, code codes.Code, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observations = append(r.observations, observation{Operation: op, Code: code})
+	r.latencies = append(r.latencies, latency)
+}
+
+func TestContainerStartMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		inSummaries []image.Summary
+		inOpts      []options.Option
+		want        []observation
+	}{
+		{
+			name:        "success",
+			inSummaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			want:        []observation{{Operation: OperationContainerStart, Code: codes.OK}},
+		},
+		{
+			name: "failure",
+			want: []observation{{Operation: OperationContainerStart, Code: codes.NotFound}},
+		},
+		{
+			name:        "dry-run",
+			inSummaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			inOpts:      []options.Option{options.WithDryRun(&structpb.Struct{})},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := &recordingMetrics{}
+			mgr := New(&fakeStartingDocker{summaries: tc.inSummaries}, WithMetrics(metrics))
+
+			opts := append([]options.Option{options.WithInstanceName("my-name")}, tc.inOpts...)
+			mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+
+			if diff := cmp.Diff(tc.want, metrics.observations); diff != "" {
+				t.Errorf("ContainerStart() recorded diff(-want, +got):\n%s", diff)
+			}
+			if len(metrics.latencies) != len(tc.want) {
+				t.Fatalf("ContainerStart() recorded %d latency samples, want %d", len(metrics.latencies), len(tc.want))
+			}
+			for _, latency := range metrics.latencies {
+				if latency <= 0 {
+					t.Errorf("ContainerStart() recorded latency %v, want a positive duration", latency)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// validNamePrefix matches the prefixes of container names. Docker requires names to start with a
+// letter or digit, followed by letters, digits, underscores, periods or hyphens.
+var validNamePrefix = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// CheckNamePrefix returns an error if prefix can not start the name of a docker container.
+func CheckNamePrefix(prefix string) error {
+	if !validNamePrefix.MatchString(prefix) {
+		return fmt.Errorf("invalid container name prefix %q: must start with a letter or digit followed by letters, digits, underscores, periods or hyphens", prefix)
+	}
+	return nil
+}
+
+// WithNamePrefix names the containers created by the manager <prefix><instance>, so that they
+// do not collide with the containers of other tools sharing the host. Instance names are
+// unchanged for callers: the prefix is added when addressing containers and stripped from the
+// names reported. Containers whose names lack the prefix can no longer be addressed by name nor
+// are they listed, although their ports are still considered in use. The prefix must be valid
+// according to CheckNamePrefix.
+func WithNamePrefix(prefix string) Option {
+	return func(m *Manager) {
+		m.namePrefix = prefix
+	}
+}
+
+// containerName returns the name of the container of instance. An empty instance, for which the
+// runtime generates a name, is left empty.
+func (m *Manager) containerName(instance string) string {
+	if instance == "" {
+		return ""
+	}
+	return m.namePrefix + instance
+}
+
+// instanceOf returns the instance name of the container named name, with or without the leading
+// slash reported by the runtime.
+func (m *Manager) instanceOf(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	return strings.TrimPrefix(name, m.namePrefix)
+}
+
+// scopeNames returns cnts with their names replaced by their instance names, dropping the names
+// outside of the prefix. The leading slash reported by the runtime is kept so that names are
+// matched alike with or without a prefix.
+func (m *Manager) scopeNames(cnts []types.Container) []types.Container {
+	if m.namePrefix == "" {
+		return cnts
+	}
+	scoped := make([]types.Container, 0, len(cnts))
+	for _, cnt := range cnts {
+		names := make([]string, 0, len(cnt.Names))
+		for _, name := range cnt.Names {
+			if instance, ok := strings.CutPrefix(strings.TrimPrefix(name, "/"), m.namePrefix); ok {
+				names = append(names, "/"+instance)
+			}
+		}
+		cnt.Names = names
+		scoped = append(scoped, cnt)
+	}
+	return scoped
+}
+
+// namespaceMode returns the mode of a namespace shared with the container named in a
+// "container:<name>" mode, as checked by checkNamespaceMode against cnts, naming the container as
+// the runtime knows it. Other modes, and containers referred to by ID, are returned as is.
+func (m *Manager) namespaceMode(mode string, cnts []types.Container) string {
+	target, ok := strings.CutPrefix(mode, "container:")
+	if !ok || m.namePrefix == "" {
+		return mode
+	}
+	for _, cnt := range cnts {
+		if cnt.ID == target {
+			return mode
+		}
+	}
+	return "container:" + m.containerName(target)
+}
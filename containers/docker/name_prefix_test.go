@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	cpb "github.com/openconfig/gnoi/containerz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestCheckNamePrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		inPrefix string
+		wantErr  bool
+	}{
+		{name: "underscore", inPrefix: "cz_"},
+		{name: "period-and-hyphen", inPrefix: "cz.edge-"},
+		{name: "empty", inPrefix: "", wantErr: true},
+		{name: "leading-underscore", inPrefix: "_cz", wantErr: true},
+		{name: "slash", inPrefix: "cz/", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := CheckNamePrefix(tc.inPrefix); (err != nil) != tc.wantErr {
+				t.Errorf("CheckNamePrefix(%q) returned error %v, want error: %t", tc.inPrefix, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestContainerStartNamePrefix(t *testing.T) {
+	tests := []struct {
+		name        string
+		inCnts      []types.Container
+		wantCreated string
+		wantErr     error
+	}{
+		{
+			name:        "prefixed",
+			wantCreated: "cz_web",
+		},
+		{
+			name:        "other-tool-same-name",
+			inCnts:      []types.Container{{ID: "other-id", Names: []string{"/web"}}},
+			wantCreated: "cz_web",
+		},
+		{
+			name:    "instance-in-use",
+			inCnts:  []types.Container{{ID: "some-id", Names: []string{"/cz_web"}}},
+			wantErr: detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "web"}, "instance name web already in use"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				cnts:      tc.inCnts,
+			}
+			mgr := New(fsd, WithNamePrefix("cz_"))
+
+			name, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithInstanceName("web"))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("ContainerStart() returned unexpected error(-want, got):\n %s", diff)
+			}
+			if fsd.ContainerID != tc.wantCreated {
+				t.Errorf("ContainerStart() created container %q, want %q", fsd.ContainerID, tc.wantCreated)
+			}
+			if err == nil && name != "web" {
+				t.Errorf("ContainerStart() returned name %q, want web", name)
+			}
+		})
+	}
+}
+
+func TestContainerListNamePrefix(t *testing.T) {
+	fld := &fakeListingDocker{
+		cnts: []types.Container{
+			{ID: "some-id", Image: "some-image", Names: []string{"/cz_web"}},
+			{ID: "other-id", Image: "other-image", Names: []string{"/web"}},
+		},
+	}
+	mgr := New(fld, WithNamePrefix("cz_"))
+
+	srv := &fakeListContainerStreamer{}
+	if err := mgr.ContainerList(context.Background(), true, 0, srv); err != nil {
+		t.Fatalf("ContainerList() returned error: %v", err)
+	}
+	want := []*cpb.ListContainerResponse{{Id: "some-id", Name: "/web", ImageName: "some-image", Status: cpb.ListContainerResponse_STOPPED}}
+	if diff := cmp.Diff(want, srv.msgs, protocmp.Transform()); diff != "" {
+		t.Errorf("ContainerList() returned diff(-want, +got):\n%s", diff)
+	}
+}
+
+func TestReconcileNamePrefix(t *testing.T) {
+	managed := map[string]string{managedByLabel: managedByValue}
+	frd := &fakeReconcilingDocker{
+		cnts: []types.Container{
+			{ID: "orphan-id", Names: []string{"/cz_orphan"}, State: "exited", Labels: managed},
+			{ID: "other-id", Names: []string{"/orphan"}, State: "exited", Labels: managed},
+		},
+	}
+	mgr := New(frd, WithNamePrefix("cz_"))
+
+	removed, err := mgr.Reconcile(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Reconcile() returned error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"orphan"}, removed); diff != "" {
+		t.Errorf("Reconcile() returned diff(-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"orphan-id"}, frd.Removed); diff != "" {
+		t.Errorf("Reconcile() removed containers diff(-want, +got):\n%s", diff)
+	}
+}
+
+func TestStopAllNamePrefix(t *testing.T) {
+	fsd := &fakeStoppingAllDocker{
+		cnts: []types.Container{
+			{ID: "web-id", Names: []string{"/cz_web"}, State: "running"},
+			{ID: "other-id", Names: []string{"/web"}, State: "running"},
+		},
+	}
+	mgr := New(fsd, WithNamePrefix("cz_"))
+
+	got, err := mgr.StopAll(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatalf("StopAll() returned error: %v", err)
+	}
+	if diff := cmp.Diff([]*options.StopResult{{Instance: "web"}}, got, cmpopts.EquateErrors()); diff != "" {
+		t.Errorf("StopAll() returned diff(-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"web-id"}, fsd.Stopped); diff != "" {
+		t.Errorf("StopAll() stopped diff(-want, +got):\n%s", diff)
+	}
+}
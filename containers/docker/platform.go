@@ -0,0 +1,54 @@
+package docker
+
+import (
+	"path"
+	"slices"
+
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// platformArchitectures is the set of architectures, by operating system, that images are
+// published for.
+var platformArchitectures = map[string][]string{
+	"linux":   {"386", "amd64", "arm", "arm64", "mips64le", "ppc64le", "riscv64", "s390x"},
+	"windows": {"amd64", "arm64"},
+}
+
+// platformVariants is the set of variants of the architectures that have them.
+var platformVariants = map[string][]string{
+	"amd64": {"v1", "v2", "v3", "v4"},
+	"arm":   {"v5", "v6", "v7"},
+	"arm64": {"v8"},
+}
+
+// ociPlatform validates p and converts it to its OCI representation. A nil platform selects the
+// platform of the target and is returned as nil.
+func ociPlatform(p *options.Platform) (*ocispec.Platform, error) {
+	if p == nil {
+		return nil, nil
+	}
+	archs, ok := platformArchitectures[p.OS]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown platform operating system %q", p.OS)
+	}
+	if !slices.Contains(archs, p.Architecture) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown architecture %q for platform operating system %s", p.Architecture, p.OS)
+	}
+	if p.Variant != "" && !slices.Contains(platformVariants[p.Architecture], p.Variant) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown variant %q for platform architecture %s", p.Variant, p.Architecture)
+	}
+	return &ocispec.Platform{OS: p.OS, Architecture: p.Architecture, Variant: p.Variant}, nil
+}
+
+// platformString formats p as os/arch[/variant], the form expected when pulling an image. A nil
+// platform is formatted as the empty string.
+func platformString(p *ocispec.Platform) string {
+	if p == nil {
+		return ""
+	}
+	return path.Join(p.OS, p.Architecture, p.Variant)
+}
@@ -0,0 +1,86 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PluginInstall pulls the managed plugin name from a registry, e.g. "vieux/sshfs:latest", and
+// enables it. A plugin requesting privileges, such as access to host devices or networking, is
+// only installed if they are granted with the GrantPluginPrivileges option.
+func (m *Manager) PluginInstall(ctx context.Context, name string, opts ...options.Option) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "a plugin name must be supplied")
+	}
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid plugin reference %q: %v", name, err)
+	}
+	ref := reference.FamiliarString(reference.TagNameOnly(named))
+
+	optionz := options.ApplyOptions(opts...)
+	auth, err := registryAuth(optionz.Credentials)
+	if err != nil {
+		return err
+	}
+
+	plugins, err := m.client.PluginList(ctx, filters.Args{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list plugins: %v", err)
+	}
+	for _, plugin := range plugins {
+		if plugin.Name == ref {
+			return status.Errorf(codes.AlreadyExists, "plugin %s is already installed", ref)
+		}
+	}
+
+	var denied types.PluginPrivileges
+	resp, err := m.client.PluginInstall(ctx, ref, types.PluginInstallOptions{
+		RemoteRef:    ref,
+		RegistryAuth: auth,
+		// The plugin is enabled once it has been pulled, so that a failure to enable it is
+		// reported as such.
+		Disabled: true,
+		AcceptPermissionsFunc: func(_ context.Context, privileges types.PluginPrivileges) (bool, error) {
+			if !optionz.GrantPluginPrivileges {
+				denied = privileges
+				return false, nil
+			}
+			return true, nil
+		},
+	})
+	if err != nil {
+		switch {
+		case denied != nil:
+			names := make([]string, 0, len(denied))
+			for _, privilege := range denied {
+				names = append(names, privilege.Name)
+			}
+			return status.Errorf(codes.PermissionDenied, "plugin %s requires privileges that were not granted: %s", ref, strings.Join(names, ", "))
+		case isAuthError(err):
+			return status.Errorf(codes.Unauthenticated, "unable to authenticate with registry: %v", err)
+		case errdefs.IsConflict(err):
+			return status.Errorf(codes.AlreadyExists, "plugin %s is already installed", ref)
+		}
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to install plugin %s: %v", ref, err))
+	}
+	defer resp.Close()
+	defer closeOnCancel(ctx, resp)()
+
+	if err := streamOutput(nil, ref, resp); err != nil {
+		return contextError(ctx, err)
+	}
+
+	if err := m.client.PluginEnable(ctx, ref, types.PluginEnableOptions{}); err != nil {
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to enable plugin %s: %v", ref, err))
+	}
+	return nil
+}
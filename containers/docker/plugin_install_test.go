@@ -0,0 +1,155 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePluginInstallingDocker struct {
+	fakeDocker
+	plugins    types.PluginsListResponse
+	privileges types.PluginPrivileges
+	installErr error
+
+	Installed string
+	Disabled  bool
+	Enabled   string
+}
+
+func (f *fakePluginInstallingDocker) PluginList(ctx context.Context, filter filters.Args) (types.PluginsListResponse, error) {
+	return f.plugins, nil
+}
+
+// PluginInstall mimics the docker client, which asks for the plugin's privileges to be accepted
+// before pulling it.
+func (f *fakePluginInstallingDocker) PluginInstall(ctx context.Context, name string, options types.PluginInstallOptions) (io.ReadCloser, error) {
+	if len(f.privileges) > 0 && !options.AcceptAllPermissions {
+		accept, err := options.AcceptPermissionsFunc(ctx, f.privileges)
+		if err != nil {
+			return nil, err
+		}
+		if !accept {
+			return nil, errors.New("permission denied")
+		}
+	}
+	if f.installErr != nil {
+		return nil, f.installErr
+	}
+	f.Installed = options.RemoteRef
+	f.Disabled = options.Disabled
+	return io.NopCloser(strings.NewReader(`{"status":"Download complete"}`)), nil
+}
+
+func (f *fakePluginInstallingDocker) PluginEnable(ctx context.Context, name string, options types.PluginEnableOptions) error {
+	f.Enabled = name
+	return nil
+}
+
+func TestPluginInstall(t *testing.T) {
+	privileges := types.PluginPrivileges{
+		{Name: "network", Value: []string{"host"}},
+		{Name: "mount", Value: []string{"/var/lib/docker/plugins/"}},
+	}
+
+	tests := []struct {
+		name         string
+		inName       string
+		inOpts       []options.Option
+		inPlugins    types.PluginsListResponse
+		inPrivileges types.PluginPrivileges
+		inInstallErr error
+		wantState    *fakePluginInstallingDocker
+		wantErr      error
+	}{
+		{
+			name:   "install",
+			inName: "vieux/sshfs",
+			wantState: &fakePluginInstallingDocker{
+				Installed: "vieux/sshfs:latest",
+				Disabled:  true,
+				Enabled:   "vieux/sshfs:latest",
+			},
+		},
+		{
+			name:    "empty-name",
+			wantErr: status.Error(codes.InvalidArgument, "a plugin name must be supplied"),
+		},
+		{
+			name:    "invalid-name",
+			inName:  "vieux/SSHFS",
+			wantErr: status.Error(codes.InvalidArgument, `invalid plugin reference "vieux/SSHFS": invalid reference format: repository name (vieux/SSHFS) must be lowercase`),
+		},
+		{
+			name:      "already-installed",
+			inName:    "vieux/sshfs:latest",
+			inPlugins: types.PluginsListResponse{{Name: "vieux/sshfs:latest"}},
+			wantErr:   status.Error(codes.AlreadyExists, "plugin vieux/sshfs:latest is already installed"),
+		},
+		{
+			name:         "already-installed-conflict",
+			inName:       "vieux/sshfs",
+			inInstallErr: errdefs.Conflict(errors.New("plugin vieux/sshfs:latest already exists")),
+			wantErr:      status.Error(codes.AlreadyExists, "plugin vieux/sshfs:latest is already installed"),
+		},
+		{
+			name:         "privileges-not-granted",
+			inName:       "vieux/sshfs",
+			inPrivileges: privileges,
+			wantErr:      status.Error(codes.PermissionDenied, "plugin vieux/sshfs:latest requires privileges that were not granted: network, mount"),
+		},
+		{
+			name:         "privileges-granted",
+			inName:       "vieux/sshfs",
+			inOpts:       []options.Option{options.WithGrantPluginPrivileges()},
+			inPrivileges: privileges,
+			wantState: &fakePluginInstallingDocker{
+				Installed: "vieux/sshfs:latest",
+				Disabled:  true,
+				Enabled:   "vieux/sshfs:latest",
+			},
+		},
+		{
+			name:         "install-failed",
+			inName:       "vieux/sshfs",
+			inInstallErr: errors.New("registry unreachable"),
+			wantErr:      status.Error(codes.Internal, "unable to install plugin vieux/sshfs:latest: registry unreachable"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fpd := &fakePluginInstallingDocker{
+				plugins:    tc.inPlugins,
+				privileges: tc.inPrivileges,
+				installErr: tc.inInstallErr,
+			}
+			mgr := New(fpd)
+
+			err := mgr.PluginInstall(context.Background(), tc.inName, tc.inOpts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("PluginInstall(%q) returned unexpected error(-want, got):\n %s", tc.inName, diff)
+			}
+			if tc.wantErr != nil {
+				if fpd.Installed != "" || fpd.Enabled != "" {
+					t.Errorf("PluginInstall(%q) installed %q and enabled %q, want neither", tc.inName, fpd.Installed, fpd.Enabled)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.wantState, fpd, cmpopts.IgnoreUnexported(fakePluginInstallingDocker{})); diff != "" {
+				t.Errorf("PluginInstall(%q) returned diff(-want, +got):\n%s", tc.inName, diff)
+			}
+		})
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types/filters"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	cpb "github.com/openconfig/gnoi/containerz"
 )
@@ -43,3 +47,24 @@ func (m *Manager) PluginList(ctx context.Context, instance string) (*cpb.ListPlu
 
 	return res, nil
 }
+
+// Plugins describes every plugin installed on the target, ordered by name, including whether it
+// is enabled and the reference it was pulled from.
+func (m *Manager) Plugins(ctx context.Context) ([]*options.PluginInfo, error) {
+	plugins, err := m.client.PluginList(ctx, filters.Args{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list plugins: %v", err)
+	}
+
+	infos := make([]*options.PluginInfo, 0, len(plugins))
+	for _, plugin := range plugins {
+		infos = append(infos, &options.PluginInfo{
+			ID:        plugin.ID,
+			Name:      plugin.Name,
+			Reference: plugin.PluginReference,
+			Enabled:   plugin.Enabled,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
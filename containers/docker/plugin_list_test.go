@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types"
 	"google.golang.org/protobuf/testing/protocmp"
+	options "github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
 )
 
@@ -169,3 +170,34 @@ func TestPluginList(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugins(t *testing.T) {
+	fpd := &fakePluginListingDocker{
+		plugins: []*types.Plugin{
+			{
+				ID:              "id-2",
+				Name:            "vieux/sshfs:latest",
+				PluginReference: "docker.io/vieux/sshfs:latest",
+				Enabled:         true,
+			},
+			{
+				ID:   "id-1",
+				Name: "local-plugin:latest",
+			},
+		},
+	}
+	mgr := New(fpd)
+
+	got, err := mgr.Plugins(context.Background())
+	if err != nil {
+		t.Fatalf("Plugins() returned error: %v", err)
+	}
+
+	want := []*options.PluginInfo{
+		{ID: "id-1", Name: "local-plugin:latest"},
+		{ID: "id-2", Name: "vieux/sshfs:latest", Reference: "docker.io/vieux/sshfs:latest", Enabled: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Plugins() returned diff(-want, +got):\n%s", diff)
+	}
+}
@@ -2,13 +2,50 @@ package docker
 
 import (
 	"context"
+	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/errdefs"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// PluginRemove removes a plugin named `instance` from the target system.
-func (m *Manager) PluginRemove(ctx context.Context, instance string) error {
-	return m.client.PluginRemove(ctx, instance, types.PluginRemoveOptions{
-		Force: true,
-	})
+// PluginRemove removes a plugin named `instance` from the target system. An enabled plugin is
+// only removed if the Force option is set.
+func (m *Manager) PluginRemove(ctx context.Context, instance string, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+
+	plugins, err := m.client.PluginList(ctx, filters.Args{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "unable to list plugins: %v", err)
+	}
+	plugin := findPlugin(instance, plugins)
+	if plugin == nil {
+		return status.Errorf(codes.NotFound, "plugin %s not found", instance)
+	}
+	if plugin.Enabled && !optionz.Force {
+		return status.Errorf(codes.FailedPrecondition, "plugin %s is enabled; disable it or use force", instance)
+	}
+
+	if err := m.client.PluginRemove(ctx, plugin.Name, types.PluginRemoveOptions{
+		Force: optionz.Force,
+	}); err != nil {
+		if errdefs.IsNotFound(err) {
+			return status.Errorf(codes.NotFound, "plugin %s not found", instance)
+		}
+		return contextError(ctx, status.Errorf(codes.Internal, "unable to remove plugin %s: %v", instance, err))
+	}
+	return nil
+}
+
+// findPlugin returns the plugin named instance, with or without its tag, if there is one.
+func findPlugin(instance string, plugins types.PluginsListResponse) *types.Plugin {
+	for _, plugin := range plugins {
+		if name, _, _ := strings.Cut(plugin.Name, ":"); plugin.Name == instance || name == instance {
+			return plugin
+		}
+	}
+	return nil
 }
@@ -5,16 +5,26 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type fakeRemovingPluginDocker struct {
 	fakeDocker
+	plugins types.PluginsListResponse
 
 	instance string
 	options  types.PluginRemoveOptions
 }
 
+func (f *fakeRemovingPluginDocker) PluginList(ctx context.Context, args filters.Args) (types.PluginsListResponse, error) {
+	return f.plugins, nil
+}
+
 func (f *fakeRemovingPluginDocker) PluginRemove(ctx context.Context, instance string, options types.PluginRemoveOptions) error {
 	f.instance = instance
 	f.options = options
@@ -22,33 +32,67 @@ func (f *fakeRemovingPluginDocker) PluginRemove(ctx context.Context, instance st
 }
 
 func TestPluginRemove(t *testing.T) {
+	plugins := types.PluginsListResponse{
+		{Name: "some-instance:latest", Enabled: true},
+		{Name: "disabled-instance:latest"},
+	}
+
 	tests := []struct {
 		name       string
 		inInstance string
+		inOpts     []options.Option
 		wantState  *fakeRemovingPluginDocker
+		wantErr    error
 	}{
 		{
-			name:       "success",
+			name:       "force",
 			inInstance: "some-instance",
+			inOpts:     []options.Option{options.Force()},
 			wantState: &fakeRemovingPluginDocker{
-				instance: "some-instance",
+				instance: "some-instance:latest",
 				options: types.PluginRemoveOptions{
 					Force: true,
 				},
 			},
 		},
+		{
+			name:       "disabled",
+			inInstance: "disabled-instance:latest",
+			wantState: &fakeRemovingPluginDocker{
+				instance: "disabled-instance:latest",
+			},
+		},
+		{
+			name:       "enabled-without-force",
+			inInstance: "some-instance",
+			wantErr:    status.Error(codes.FailedPrecondition, "plugin some-instance is enabled; disable it or use force"),
+		},
+		{
+			name:       "missing",
+			inInstance: "no-such-instance",
+			inOpts:     []options.Option{options.Force()},
+			wantErr:    status.Error(codes.NotFound, "plugin no-such-instance not found"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
+			fake := &fakeRemovingPluginDocker{plugins: plugins}
 			m := &Manager{
-				client: &fakeRemovingPluginDocker{},
+				client: fake,
+			}
+			err := m.PluginRemove(ctx, tt.inInstance, tt.inOpts...)
+			if diff := cmp.Diff(tt.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Fatalf("PluginRemove() returned unexpected error(-want, got):\n %s", diff)
 			}
-			if err := m.PluginRemove(ctx, tt.inInstance); err != nil {
-				t.Fatalf("PluginRemove() failed: %v", err)
+			if tt.wantState == nil {
+				if fake.instance != "" {
+					t.Errorf("PluginRemove() removed plugin %q, want none removed", fake.instance)
+				}
+				return
 			}
-			if diff := cmp.Diff(tt.wantState, m.client, cmp.AllowUnexported(fakeRemovingPluginDocker{})); diff != "" {
+			if diff := cmp.Diff(tt.wantState, fake, cmp.AllowUnexported(fakeRemovingPluginDocker{}), cmpopts.IgnoreFields(fakeRemovingPluginDocker{}, "plugins")); diff != "" {
 				t.Errorf("PluginRemove() returned diff (-want +got):\n%s", diff)
 			}
 		})
@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/distribution/reference"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// normalizeReference validates the image reference formed by imageName and, if set, tag. The
+// reference is normalized, so that "my-image" is read as "docker.io/library/my-image:latest",
+// and returned in the familiar form the runtime reports in RepoTags, e.g. "my-image:latest".
+// References pinned by digest are returned as name@digest, without a tag being added, which is
+// the form reported in RepoDigests.
+func normalizeReference(imageName, tag string) (string, error) {
+	ref := imageName
+	if tag != "" {
+		ref = fmt.Sprintf("%s:%s", imageName, tag)
+	}
+
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid image reference %q: %v", ref, err)
+	}
+
+	if canonical, ok := named.(reference.Canonical); ok {
+		pinned, err := reference.WithDigest(reference.TrimNamed(canonical), canonical.Digest())
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid image reference %q: %v", ref, err)
+		}
+		return reference.FamiliarString(pinned), nil
+	}
+	return reference.FamiliarString(reference.TagNameOnly(named)), nil
+}
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNormalizeReference(t *testing.T) {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		inImage string
+		inTag   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "bare-name",
+			inImage: "my-image",
+			want:    "my-image:latest",
+		},
+		{
+			name:    "tagged",
+			inImage: "my-image",
+			inTag:   "v1",
+			want:    "my-image:v1",
+		},
+		{
+			name:    "tagged-name",
+			inImage: "my-image:v1",
+			want:    "my-image:v1",
+		},
+		{
+			name:    "fully-qualified",
+			inImage: "docker.io/library/my-image",
+			inTag:   "v1",
+			want:    "my-image:v1",
+		},
+		{
+			name:    "registry",
+			inImage: "registry.example.com:5000/team/my-image",
+			inTag:   "v1",
+			want:    "registry.example.com:5000/team/my-image:v1",
+		},
+		{
+			name:    "digest-pinned",
+			inImage: "my-image@" + digest,
+			want:    "my-image@" + digest,
+		},
+		{
+			name:    "tagged-and-digest-pinned",
+			inImage: "my-image:v1@" + digest,
+			want:    "my-image@" + digest,
+		},
+		{
+			name:    "extra-tag",
+			inImage: "my-image:v1",
+			inTag:   "extra",
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "my-image:v1:extra": invalid reference format`),
+		},
+		{
+			name:    "uppercase",
+			inImage: "My-Image",
+			inTag:   "v1",
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "My-Image:v1": invalid reference format: repository name (library/My-Image) must be lowercase`),
+		},
+		{
+			name:    "tag-after-digest",
+			inImage: "my-image@" + digest,
+			inTag:   "v1",
+			wantErr: status.Error(codes.InvalidArgument, `invalid image reference "my-image@`+digest+`:v1": invalid reference format`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := normalizeReference(tc.inImage, tc.inTag)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("normalizeReference(%q, %q) returned unexpected error(-want, got):\n %s", tc.inImage, tc.inTag, diff)
+			}
+			if got != tc.want {
+				t.Errorf("normalizeReference(%q, %q) = %q, want %q", tc.inImage, tc.inTag, got, tc.want)
+			}
+		})
+	}
+}
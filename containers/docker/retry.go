@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"k8s.io/klog/v2"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var (
+	// retryAttempts is the number of times a docker call failing with a transient error is
+	// retried.
+	retryAttempts = 3
+
+	// retryBackoff is the wait before the first retry. It doubles with every further retry.
+	retryBackoff = 200 * time.Millisecond
+)
+
+// isTransient reports whether err is a failure of the docker daemon, rather than of the request,
+// which may go away if the request is repeated.
+func isTransient(err error) bool {
+	return client.IsErrConnectionFailed(err) || errdefs.IsSystem(err) || errdefs.IsUnavailable(err)
+}
+
+// retry calls fn until it succeeds, fails with an error for which retryable is false or runs out
+// of attempts. No retry is attempted if its backoff would outlast the deadline of ctx.
+func retry[T any](ctx context.Context, retryable func(error) bool, fn func() (T, error)) (T, error) {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := fn()
+		if err == nil || attempt == retryAttempts || !retryable(err) || ctx.Err() != nil {
+			return res, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return res, err
+		}
+
+		klog.Warningf("retrying docker call in %s after transient error: %v", backoff, err)
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return res, err
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+// listContainers lists the containers on the target, retrying transient failures. Containers are
+// named by their instance names.
+func (m *Manager) listContainers(ctx context.Context, opts container.ListOptions) ([]types.Container, error) {
+	cnts, err := retry(ctx, isTransient, func() ([]types.Container, error) {
+		return m.client.ContainerList(ctx, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.scopeNames(cnts), nil
+}
+
+// inspectContainer inspects a container, retrying transient failures.
+func (m *Manager) inspectContainer(ctx context.Context, id string) (types.ContainerJSON, error) {
+	return retry(ctx, isTransient, func() (types.ContainerJSON, error) {
+		return m.client.ContainerInspect(ctx, id)
+	})
+}
+
+// listImages lists the images on the target, retrying transient failures.
+func (m *Manager) listImages(ctx context.Context, opts image.ListOptions) ([]image.Summary, error) {
+	return retry(ctx, isTransient, func() ([]image.Summary, error) {
+		return m.client.ImageList(ctx, opts)
+	})
+}
+
+// createContainer creates the container of the instance name. Creating is not idempotent: a
+// daemon failing mid-request may have created the container, so only failures to reach the daemon
+// are retried.
+func (m *Manager) createContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, name string) (container.CreateResponse, error) {
+	return retry(ctx, client.IsErrConnectionFailed, func() (container.CreateResponse, error) {
+		return m.client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, m.containerName(name))
+	})
+}
@@ -0,0 +1,154 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+type fakeFlakyDocker struct {
+	fakeDocker
+	errs  []error
+	calls int
+}
+
+func (f *fakeFlakyDocker) fail() error {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return f.errs[f.calls-1]
+	}
+	return nil
+}
+
+func (f *fakeFlakyDocker) ContainerList(context.Context, container.ListOptions) ([]types.Container, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	return []types.Container{{ID: "some-id"}}, nil
+}
+
+func (f *fakeFlakyDocker) ContainerCreate(context.Context, *container.Config, *container.HostConfig, *network.NetworkingConfig, *ocispec.Platform, string) (container.CreateResponse, error) {
+	if err := f.fail(); err != nil {
+		return container.CreateResponse{}, err
+	}
+	return container.CreateResponse{ID: "some-id"}, nil
+}
+
+func TestRetry(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	system := errdefs.System(errors.New("internal server error"))
+	refused := client.ErrorConnectionFailed("unix:///var/run/docker.sock")
+
+	tests := []struct {
+		name      string
+		inErrs    []error
+		inCreate  bool
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			wantCalls: 1,
+		},
+		{
+			name:      "transient-then-success",
+			inErrs:    []error{system, refused},
+			wantCalls: 3,
+		},
+		{
+			name:      "unavailable-then-success",
+			inErrs:    []error{errdefs.Unavailable(errors.New("busy"))},
+			wantCalls: 2,
+		},
+		{
+			name:      "always-failing",
+			inErrs:    []error{system, system, system, system, system},
+			wantCalls: retryAttempts + 1,
+			wantErr:   true,
+		},
+		{
+			name:      "not-found",
+			inErrs:    []error{errdefs.NotFound(errors.New("no such container"))},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "conflict",
+			inErrs:    []error{errdefs.Conflict(errors.New("name in use"))},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "invalid-parameter",
+			inErrs:    []error{errdefs.InvalidParameter(errors.New("bad filter"))},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "create-connection-refused-then-success",
+			inErrs:    []error{refused},
+			inCreate:  true,
+			wantCalls: 2,
+		},
+		{
+			name:      "create-server-error",
+			inErrs:    []error{system},
+			inCreate:  true,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeFlakyDocker{errs: tc.inErrs}
+			mgr := New(f)
+
+			var err error
+			if tc.inCreate {
+				_, err = mgr.createContainer(context.Background(), &container.Config{}, &container.HostConfig{}, &network.NetworkingConfig{}, nil, "my-instance")
+			} else {
+				var cnts []types.Container
+				cnts, err = mgr.listContainers(context.Background(), container.ListOptions{})
+				if err == nil && len(cnts) != 1 {
+					t.Errorf("listContainers() returned %d containers, want 1", len(cnts))
+				}
+			}
+
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Errorf("returned error %v, want error: %t", err, tc.wantErr)
+			}
+			if f.calls != tc.wantCalls {
+				t.Errorf("docker client called %d times, want %d", f.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Hour
+
+	f := &fakeFlakyDocker{errs: []error{errdefs.System(errors.New("internal server error"))}}
+	mgr := New(f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if _, err := mgr.listContainers(ctx, container.ListOptions{}); err == nil {
+		t.Errorf("listContainers() returned no error, want the transient error")
+	}
+	if f.calls != 1 {
+		t.Errorf("docker client called %d times, want 1: the backoff outlasts the deadline", f.calls)
+	}
+}
@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// knownRuntimes returns the set of OCI runtimes the daemon is configured with. The daemon is
+// only queried until it answers, as its runtimes can only change when it is restarted.
+func (m *Manager) knownRuntimes(ctx context.Context) (map[string]bool, error) {
+	m.runtimesMu.Lock()
+	defer m.runtimesMu.Unlock()
+	if m.runtimes != nil {
+		return m.runtimes, nil
+	}
+
+	info, err := m.client.Info(ctx)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+	runtimes := make(map[string]bool, len(info.Runtimes))
+	for name := range info.Runtimes {
+		runtimes[name] = true
+	}
+	m.runtimes = runtimes
+	return runtimes, nil
+}
+
+// checkRuntime ensures that runtime, if set, is one the daemon is configured with.
+func (m *Manager) checkRuntime(ctx context.Context, runtime string) error {
+	if runtime == "" {
+		return nil
+	}
+	runtimes, err := m.knownRuntimes(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "unable to query the runtimes of the daemon: %v", err)
+	}
+	if !runtimes[runtime] {
+		return status.Errorf(codes.InvalidArgument, "unknown runtime %q: the daemon supports %s", runtime, strings.Join(slices.Sorted(maps.Keys(runtimes)), ", "))
+	}
+	return nil
+}
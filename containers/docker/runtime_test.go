@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeRuntimeDocker struct {
+	fakeStartingDocker
+	infoErr error
+	infos   int
+}
+
+func (f *fakeRuntimeDocker) Info(ctx context.Context) (system.Info, error) {
+	f.infos++
+	return system.Info{
+		DefaultRuntime: "runc",
+		Runtimes: map[string]system.RuntimeWithStatus{
+			"runc":         {},
+			"runsc":        {},
+			"kata-runtime": {},
+		},
+	}, f.infoErr
+}
+
+func TestContainerStartRuntime(t *testing.T) {
+	tests := []struct {
+		name        string
+		inRuntime   string
+		inInfoErr   error
+		wantRuntime string
+		wantInfos   int
+		wantErr     error
+	}{
+		{
+			name:        "gvisor",
+			inRuntime:   "runsc",
+			wantRuntime: "runsc",
+			wantInfos:   1,
+		},
+		{
+			name:        "kata",
+			inRuntime:   "kata-runtime",
+			wantRuntime: "kata-runtime",
+			wantInfos:   1,
+		},
+		{
+			name: "daemon-default",
+		},
+		{
+			name:      "unknown-runtime",
+			inRuntime: "crun",
+			wantInfos: 1,
+			wantErr:   status.Error(codes.InvalidArgument, `unknown runtime "crun": the daemon supports kata-runtime, runc, runsc`),
+		},
+		{
+			name:      "info-unavailable",
+			inRuntime: "runsc",
+			inInfoErr: errors.New("connection refused"),
+			wantInfos: 1,
+			wantErr:   status.Error(codes.Unavailable, "unable to query the runtimes of the daemon: connection refused"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			frd := &fakeRuntimeDocker{
+				fakeStartingDocker: fakeStartingDocker{
+					summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				},
+				infoErr: tc.inInfoErr,
+			}
+			mgr := New(frd)
+
+			_, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", options.WithRuntime(tc.inRuntime))
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%q) returned unexpected error(-want, got):\n %s", tc.inRuntime, diff)
+			}
+			if frd.Runtime != tc.wantRuntime {
+				t.Errorf("ContainerStart(%q) created a container with runtime %q, want %q", tc.inRuntime, frd.Runtime, tc.wantRuntime)
+			}
+			if frd.infos != tc.wantInfos {
+				t.Errorf("ContainerStart(%q) queried the daemon info %d times, want %d", tc.inRuntime, frd.infos, tc.wantInfos)
+			}
+		})
+	}
+}
+
+func TestKnownRuntimesCached(t *testing.T) {
+	frd := &fakeRuntimeDocker{}
+	mgr := New(frd)
+
+	if err := mgr.Start(context.Background()); err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+	defer mgr.janitor.Stop(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := mgr.checkRuntime(context.Background(), "runsc"); err != nil {
+			t.Fatalf("checkRuntime(runsc) returned error: %v", err)
+		}
+	}
+	if frd.infos != 1 {
+		t.Errorf("the daemon info was queried %d times, want once at start", frd.infos)
+	}
+}
@@ -0,0 +1,152 @@
+package docker
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+	options "github.com/openconfig/containerz/containers"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultSecretsDir is the directory in which secrets are kept unless the manager is
+	// configured otherwise. /run is a tmpfs on most distributions.
+	defaultSecretsDir = "/run/containerz/secrets"
+	// defaultSecretsTarget is the directory mounting secrets without a target in a container.
+	defaultSecretsTarget = "/run/secrets"
+	// secretMode is the mode of secret files: readable by their owner only.
+	secretMode os.FileMode = 0400
+	// secretsLabel is set to the directory of the secrets of a container, so that they can be
+	// removed along with it.
+	secretsLabel = "containerz.secrets"
+)
+
+// checkTmpfs ensures that dir is on a tmpfs. It is a variable so that tests, whose temporary
+// directories may be on disk, can replace it.
+var checkTmpfs = func(dir string) error {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return status.Errorf(codes.Internal, "unable to stat secrets directory %s: %v", dir, err)
+	}
+	if stat.Type != unix.TMPFS_MAGIC {
+		return status.Errorf(codes.FailedPrecondition, "secrets directory %s is not on a tmpfs", dir)
+	}
+	return nil
+}
+
+// newSecretsDir returns a fresh directory, under the secrets directory of the manager, for the
+// secrets of a container. The directory is not created.
+func (m *Manager) newSecretsDir() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", status.Errorf(codes.Internal, "unable to name secrets directory: %v", err)
+	}
+	return filepath.Join(m.secretsDir, hex.EncodeToString(b)), nil
+}
+
+// secretMounts validates secrets and returns the read-only mounts of their files, kept in dir.
+// The target of a secret may not be used by another secret, by mounts or by a tmpfs mount.
+func secretMounts(secrets []options.Secret, dir string, mounts []mount.Mount, tmpfs map[string]string) ([]mount.Mount, error) {
+	used := make(map[string]string, len(mounts)+len(tmpfs)+len(secrets))
+	for _, m := range mounts {
+		used[path.Clean(m.Target)] = fmt.Sprintf("%s mount %s", m.Type, m.Source)
+	}
+	for target := range tmpfs {
+		used[path.Clean(target)] = "a tmpfs mount"
+	}
+
+	names := make(map[string]bool, len(secrets))
+	secretMounts := make([]mount.Mount, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret.Name == "" || secret.Name == "." || secret.Name == ".." || strings.Contains(secret.Name, "/") {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid secret name %q", secret.Name)
+		}
+		if names[secret.Name] {
+			return nil, status.Errorf(codes.InvalidArgument, "secret %s is set more than once", secret.Name)
+		}
+		names[secret.Name] = true
+
+		target := secret.Target
+		if target == "" {
+			target = path.Join(defaultSecretsTarget, secret.Name)
+		}
+		if !path.IsAbs(target) {
+			return nil, status.Errorf(codes.InvalidArgument, "secret %s target %q must be an absolute path", secret.Name, target)
+		}
+		target = path.Clean(target)
+		if user, ok := used[target]; ok {
+			return nil, status.Errorf(codes.AlreadyExists, "secret %s target %s is already used by %s", secret.Name, target, user)
+		}
+		used[target] = "secret " + secret.Name
+
+		secretMounts = append(secretMounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   filepath.Join(dir, secret.Name),
+			Target:   target,
+			ReadOnly: true,
+		})
+	}
+	return secretMounts, nil
+}
+
+// writeSecrets writes the files of secrets to dir, which is created and must be on a tmpfs so
+// that the secrets never reach the disk.
+func (m *Manager) writeSecrets(dir string, secrets []options.Secret) error {
+	if err := os.MkdirAll(m.secretsDir, 0700); err != nil {
+		return status.Errorf(codes.Internal, "unable to create secrets directory %s: %v", m.secretsDir, err)
+	}
+	if err := checkTmpfs(m.secretsDir); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return status.Errorf(codes.Internal, "unable to create secrets directory %s: %v", dir, err)
+	}
+	for _, secret := range secrets {
+		file := filepath.Join(dir, secret.Name)
+		if err := os.WriteFile(file, secret.Data, secretMode); err != nil {
+			m.removeSecrets(dir)
+			return status.Errorf(codes.Internal, "unable to write secret %s: %v", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// sameSecrets reports whether dir, the secrets directory of a container, holds the files of
+// secrets. Their names and targets are part of the configuration digest, so only their content is
+// compared.
+func (m *Manager) sameSecrets(dir string, secrets []options.Secret) bool {
+	if len(secrets) == 0 {
+		return true
+	}
+	if dir == "" || filepath.Dir(dir) != filepath.Clean(m.secretsDir) {
+		return false
+	}
+	for _, secret := range secrets {
+		data, err := os.ReadFile(filepath.Join(dir, secret.Name))
+		if err != nil || subtle.ConstantTimeCompare(data, secret.Data) != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// removeSecrets removes the secrets directory dir of a container. Directories outside of the
+// secrets directory of the manager are left alone, as dir may come from a container label.
+func (m *Manager) removeSecrets(dir string) {
+	if dir == "" || filepath.Dir(dir) != filepath.Clean(m.secretsDir) {
+		return
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		klog.Warningf("unable to remove secrets directory %s: %v", dir, err)
+	}
+}
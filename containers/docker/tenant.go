@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"regexp"
+
+	"github.com/docker/docker/api/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validTenant matches the tenants that can qualify a container name. Dots are excluded so that
+// the tenant of a qualified name is unambiguous.
+var validTenant = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// tenantInstance returns the name of the container for instance, given the labels of the
+// container, along with the containers its name must not conflict with.
+//
+// If the manager has a tenant label and the container carries it, instance names are unique
+// within that tenant only: the container is named <tenant>.<instance>, as the runtime requires
+// names to be unique, and only the containers of the tenant are considered. Otherwise names are
+// unique among all containers.
+func (m *Manager) tenantInstance(instance string, labels map[string]string, cnts []types.Container) (string, []types.Container, error) {
+	if m.tenantLabel == "" || instance == "" {
+		return instance, cnts, nil
+	}
+	tenant := labels[m.tenantLabel]
+	if tenant == "" {
+		return instance, cnts, nil
+	}
+	if !validTenant.MatchString(tenant) {
+		return "", nil, status.Errorf(codes.InvalidArgument, "invalid tenant %q: must be letters, digits, underscores or hyphens", tenant)
+	}
+
+	scoped := make([]types.Container, 0, len(cnts))
+	for _, cnt := range cnts {
+		if cnt.Labels[m.tenantLabel] == tenant {
+			scoped = append(scoped, cnt)
+		}
+	}
+	return tenant + "." + instance, scoped, nil
+}
@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	options "github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestContainerStartTenant(t *testing.T) {
+	tenantA := []types.Container{
+		{
+			Names:  []string{"/a.web"},
+			Labels: map[string]string{"tenant": "a"},
+			Ports:  []types.Port{{PublicPort: 8080, Type: "tcp"}},
+		},
+	}
+	global := []types.Container{{Names: []string{"/web"}}}
+
+	tests := []struct {
+		name      string
+		inMgrOpts []Option
+		inCnts    []types.Container
+		inLabels  map[string]string
+		inOpts    []options.Option
+		wantName  string
+		wantErr   error
+	}{
+		{
+			name:      "same-name-other-tenant",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inCnts:    tenantA,
+			inLabels:  map[string]string{"tenant": "b"},
+			wantName:  "b.web",
+		},
+		{
+			name:      "same-name-same-tenant",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inCnts:    tenantA,
+			inLabels:  map[string]string{"tenant": "a"},
+			wantErr:   detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "a.web"}, "instance name a.web already in use"),
+		},
+		{
+			name:      "tenant-ignores-global-name",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inCnts:    global,
+			inLabels:  map[string]string{"tenant": "b"},
+			wantName:  "b.web",
+		},
+		{
+			name:      "no-tenant-is-global",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inCnts:    global,
+			wantErr:   detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "web"}, "instance name web already in use"),
+		},
+		{
+			name:     "no-tenant-label-configured",
+			inCnts:   global,
+			inLabels: map[string]string{"tenant": "b"},
+			wantErr:  detailedError(codes.AlreadyExists, reasonInstanceInUse, map[string]string{"instance": "web"}, "instance name web already in use"),
+		},
+		{
+			name:      "ports-are-global",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inCnts:    tenantA,
+			inLabels:  map[string]string{"tenant": "b"},
+			inOpts:    []options.Option{options.WithPorts(map[uint32]uint32{80: 8080})},
+			wantErr:   detailedError(codes.Unavailable, reasonPortInUse, map[string]string{"port": "8080", "protocol": "tcp"}, "port 8080 already in use"),
+		},
+		{
+			name:      "invalid-tenant",
+			inMgrOpts: []Option{WithTenantLabel("tenant")},
+			inLabels:  map[string]string{"tenant": "a.b"},
+			wantErr:   status.Error(codes.InvalidArgument, `invalid tenant "a.b": must be letters, digits, underscores or hyphens`),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsd := &fakeStartingDocker{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+				cnts:      tc.inCnts,
+			}
+			mgr := New(fsd, tc.inMgrOpts...)
+
+			opts := append([]options.Option{options.WithInstanceName("web"), options.WithLabels(tc.inLabels)}, tc.inOpts...)
+			name, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", opts...)
+			if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("ContainerStart(%v) returned unexpected error(-want, got):\n %s", tc.inLabels, diff)
+			}
+			if name != tc.wantName {
+				t.Errorf("ContainerStart(%v) returned name %q, want %q", tc.inLabels, name, tc.wantName)
+			}
+			if fsd.ContainerID != tc.wantName {
+				t.Errorf("ContainerStart(%v) created container %q, want %q", tc.inLabels, fsd.ContainerID, tc.wantName)
+			}
+		})
+	}
+}
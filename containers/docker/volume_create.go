@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/openconfig/containerz/containers"
 	"google.golang.org/grpc/codes"
@@ -14,6 +15,8 @@ import (
 
 // VolumeCreate creates a volume with the provided name using the driver specified. The driver
 // default to LOCAL if it is not specified. The name is autogenerated by the target if it is empty.
+// If a volume with the same name already exists but uses a different driver, AlreadyExists is
+// returned.
 func (m *Manager) VolumeCreate(ctx context.Context, name string, driver cpb.Driver, opts ...options.Option) (string, error) {
 	optionz := options.ApplyOptions(opts...)
 
@@ -46,6 +49,21 @@ func (m *Manager) VolumeCreate(ctx context.Context, name string, driver cpb.Driv
 		}
 	}
 
+	if name != "" {
+		resp, err := m.client.VolumeList(ctx, volume.ListOptions{
+			Filters: filters.NewArgs(filters.Arg("name", name)),
+		})
+		if err != nil {
+			return "", err
+		}
+		// The name filter is a substring match, so only consider exact matches.
+		for _, vol := range resp.Volumes {
+			if vol.Name == name && vol.Driver != kind {
+				return "", status.Errorf(codes.AlreadyExists, "volume %s already exists with driver %s", name, vol.Driver)
+			}
+		}
+	}
+
 	create := volume.CreateOptions{
 		Name:       name,
 		Driver:     kind,
@@ -8,12 +8,27 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	cpb "github.com/openconfig/gnoi/containerz"
 )
 
 type fakeVolumeCreatingDocker struct {
 	fakeDocker
-	V volume.Volume
+	V        volume.Volume
+	existing []*volume.Volume
+}
+
+func (f *fakeVolumeCreatingDocker) VolumeList(_ context.Context, opts volume.ListOptions) (volume.ListResponse, error) {
+	resp := volume.ListResponse{}
+	for _, vol := range f.existing {
+		if opts.Filters.Contains("name") && !opts.Filters.Match("name", vol.Name) {
+			continue
+		}
+		resp.Volumes = append(resp.Volumes, vol)
+	}
+	return resp, nil
 }
 
 func (f *fakeVolumeCreatingDocker) VolumeCreate(_ context.Context, opts volume.CreateOptions) (volume.Volume, error) {
@@ -37,8 +52,10 @@ func TestVolumeCreate(t *testing.T) {
 		inOpts    []options.Option
 		inName    string
 		inDriver  cpb.Driver
+		inVolumes []*volume.Volume
 		wantState *fakeVolumeCreatingDocker
 		wantResp  string
+		wantErr   error
 	}{
 		{
 			name:     "no-name",
@@ -111,15 +128,55 @@ func TestVolumeCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:      "existing-volume-same-driver",
+			inName:    "some-volume",
+			inVolumes: []*volume.Volume{{Name: "some-volume", Driver: "local"}},
+			wantResp:  "some-volume",
+			wantState: &fakeVolumeCreatingDocker{
+				V: volume.Volume{
+					Name:    "some-volume",
+					Driver:  "local",
+					Options: map[string]string{},
+				},
+			},
+		},
+		{
+			name:      "existing-volume-different-driver",
+			inName:    "some-volume",
+			inDriver:  cpb.Driver_DS_CUSTOM,
+			inVolumes: []*volume.Volume{{Name: "some-volume", Driver: "local"}},
+			wantErr:   status.Error(codes.AlreadyExists, "volume some-volume already exists with driver local"),
+		},
+		{
+			name:      "similarly-named-volume-different-driver",
+			inName:    "some-volume",
+			inDriver:  cpb.Driver_DS_CUSTOM,
+			inVolumes: []*volume.Volume{{Name: "some-volume-2", Driver: "local"}},
+			wantResp:  "some-volume",
+			wantState: &fakeVolumeCreatingDocker{
+				V: volume.Volume{
+					Name:    "some-volume",
+					Driver:  "custom:latest",
+					Options: map[string]string{},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
-			fcd := &fakeVolumeCreatingDocker{}
+			fcd := &fakeVolumeCreatingDocker{existing: tc.inVolumes}
 			mgr := New(fcd)
 
 			resp, err := mgr.VolumeCreate(ctx, tc.inName, tc.inDriver, tc.inOpts...)
+			if tc.wantErr != nil {
+				if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+					t.Errorf("VolumeCreate(%s, %v, %+v) returned unexpected error(-want, got):\n %s", tc.inName, tc.inDriver, tc.inOpts, diff)
+				}
+				return
+			}
 			if err != nil {
 				t.Errorf("VolumeCreate(%s, %v, %+v) returned error: %v", tc.inName, tc.inDriver, tc.inOpts, err)
 			}
@@ -27,13 +27,24 @@ import (
 	cpb "github.com/openconfig/gnoi/containerz"
 )
 
-// VolumeList lists the volumes present on the target.
+// VolumeList lists the volumes present on the target. Volumes may be filtered by name, driver
+// or label, where a label filter takes the form key or key=value.
 func (m *Manager) VolumeList(ctx context.Context, srv options.ListVolumeStreamer, opts ...options.Option) error {
 	optionz := options.ApplyOptions(opts...)
 	kvPairs := []filters.KeyValuePair{}
 	for key, values := range optionz.Filter {
+		dockerKey := string(key)
+		if key == options.Volume {
+			// docker filters volumes by name rather than by volume.
+			dockerKey = "name"
+		}
 		for _, value := range values {
-			kvPairs = append(kvPairs, filters.KeyValuePair{Key: string(key), Value: value})
+			if key == options.Label {
+				if err := checkLabelFilter(value); err != nil {
+					return err
+				}
+			}
+			kvPairs = append(kvPairs, filters.KeyValuePair{Key: dockerKey, Value: value})
 		}
 	}
 
@@ -24,6 +24,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 	"github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
@@ -53,6 +55,14 @@ func (f *fakeVolumeListingDocker) VolumeList(ctx context.Context, options volume
 	}, nil
 }
 
+func filterArgsToJSON(args filters.Args) string {
+	s, err := filters.ToJSON(args)
+	if err != nil {
+		return err.Error()
+	}
+	return s
+}
+
 func TestListVolume(t *testing.T) {
 	ts, err := time.Parse(time.RFC3339, "2024-02-09T13:07:31+01:00")
 	if err != nil {
@@ -64,6 +74,7 @@ func TestListVolume(t *testing.T) {
 		inVols    []*volume.Volume
 		wantState *fakeVolumeListingDocker
 		wantMsgs  []*cpb.ListVolumeResponse
+		wantErr   error
 	}{
 		{
 			name: "no-containers",
@@ -122,10 +133,62 @@ func TestListVolume(t *testing.T) {
 			},
 			wantState: &fakeVolumeListingDocker{
 				Opts: volume.ListOptions{
-					Filters: filters.NewArgs(filters.Arg("volme", "some-volume")),
+					Filters: filters.NewArgs(filters.Arg("name", "some-volume")),
+				},
+			},
+		},
+		{
+			name: "filter-label-and-driver",
+			inOpts: []options.Option{
+				options.WithFilter(map[options.FilterKey][]string{
+					options.Label:  []string{"some-label=some-value", "other-label"},
+					options.Driver: []string{"some-driver"},
+				}),
+			},
+			inVols: []*volume.Volume{
+				&volume.Volume{
+					Name:      "some-volume",
+					Driver:    "some-driver",
+					Labels:    map[string]string{"some-label": "some-value", "other-label": ""},
+					CreatedAt: "2024-02-09T13:07:31+01:00",
+				},
+			},
+			wantState: &fakeVolumeListingDocker{
+				Opts: volume.ListOptions{
+					Filters: filters.NewArgs(
+						filters.Arg("label", "some-label=some-value"),
+						filters.Arg("label", "other-label"),
+						filters.Arg("driver", "some-driver"),
+					),
+				},
+			},
+			wantMsgs: []*cpb.ListVolumeResponse{
+				&cpb.ListVolumeResponse{
+					Name:    "some-volume",
+					Driver:  "some-driver",
+					Labels:  map[string]string{"some-label": "some-value", "other-label": ""},
+					Created: tpb.New(ts),
 				},
 			},
 		},
+		{
+			name: "malformed-label-filter",
+			inOpts: []options.Option{
+				options.WithFilter(map[options.FilterKey][]string{
+					options.Label: []string{"=some-value"},
+				}),
+			},
+			wantErr: status.Error(codes.InvalidArgument, `malformed label filter "=some-value": expected key or key=value`),
+		},
+		{
+			name: "empty-label-filter",
+			inOpts: []options.Option{
+				options.WithFilter(map[options.FilterKey][]string{
+					options.Label: []string{""},
+				}),
+			},
+			wantErr: status.Error(codes.InvalidArgument, `malformed label filter "": expected key or key=value`),
+		},
 	}
 
 	for _, tc := range tests {
@@ -139,11 +202,20 @@ func TestListVolume(t *testing.T) {
 			stream := &fakeListVolumeStreamer{}
 
 			if err := mgr.VolumeList(ctx, stream, tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("VolumeList(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+					}
+					return
+				}
 				t.Errorf("VolumeList(%+v) returned error: %v", tc.inOpts, err)
 			}
+			if tc.wantErr != nil {
+				t.Fatalf("VolumeList(%+v) did not return an error, want %v", tc.inOpts, tc.wantErr)
+			}
 
 			if tc.wantState != nil {
-				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeVolumeListingDocker{}, filters.Args{})); diff != "" {
+				if diff := cmp.Diff(tc.wantState, fsd, cmpopts.IgnoreUnexported(fakeVolumeListingDocker{}), cmp.Transformer("filters", filterArgsToJSON)); diff != "" {
 					t.Errorf("VolumeList( %+v) returned diff(-want, +got):\n%s", tc.inOpts, diff)
 				}
 			}
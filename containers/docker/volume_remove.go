@@ -2,12 +2,62 @@ package docker
 
 import (
 	"context"
+	"strings"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
 )
 
-// VolumeRemove removes a volume.
+// VolumeRemove removes a volume. Unless the Force option is provided, a volume that is still
+// used by a container is not removed and FailedPrecondition is returned naming those containers.
 func (m *Manager) VolumeRemove(ctx context.Context, name string, opts ...options.Option) error {
 	optionz := options.ApplyOptions(opts...)
+
+	resp, err := m.client.VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", name)),
+	})
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, vol := range resp.Volumes {
+		if vol.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return detailedError(codes.NotFound, reasonVolumeNotFound, map[string]string{"volume": name}, "volume %s not found", name)
+	}
+
+	if !optionz.Force {
+		cnts, err := m.listContainers(ctx, container.ListOptions{
+			All:     true,
+			Filters: filters.NewArgs(filters.Arg("volume", name)),
+		})
+		if err != nil {
+			return err
+		}
+		users := []string{}
+		for _, cnt := range cnts {
+			if len(cnt.Names) == 0 {
+				// Containers outside of the name prefix are only known by ID.
+				users = append(users, cnt.ID)
+			}
+			for _, n := range cnt.Names {
+				users = append(users, strings.TrimPrefix(n, "/"))
+			}
+		}
+		if len(users) > 0 {
+			return detailedError(codes.FailedPrecondition, reasonVolumeInUse, map[string]string{
+				"volume":     name,
+				"containers": strings.Join(users, ","),
+			}, "volume %s is in use by containers: %s", name, strings.Join(users, ", "))
+		}
+	}
+
 	return m.client.VolumeRemove(ctx, name, optionz.Force)
 }
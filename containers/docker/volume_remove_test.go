@@ -4,17 +4,36 @@ import (
 	"context"
 	"testing"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/openconfig/containerz/containers"
+	"google.golang.org/grpc/codes"
 )
 
 type fakeVolumeRemovingDocker struct {
 	fakeDocker
+	volumes    []*volume.Volume
+	containers map[string][]types.Container
+
 	Name  string
 	Force bool
 }
 
+func (f *fakeVolumeRemovingDocker) VolumeList(context.Context, volume.ListOptions) (volume.ListResponse, error) {
+	return volume.ListResponse{Volumes: f.volumes}, nil
+}
+
+func (f *fakeVolumeRemovingDocker) ContainerList(_ context.Context, opts container.ListOptions) ([]types.Container, error) {
+	cnts := []types.Container{}
+	for _, vol := range opts.Filters.Get("volume") {
+		cnts = append(cnts, f.containers[vol]...)
+	}
+	return cnts, nil
+}
+
 func (f *fakeVolumeRemovingDocker) VolumeRemove(_ context.Context, id string, force bool) error {
 	f.Name = id
 	f.Force = force
@@ -26,11 +45,15 @@ func TestVolumeRemove(t *testing.T) {
 		name      string
 		inOpts    []options.Option
 		inName    string
+		inVols    []*volume.Volume
+		inCnts    map[string][]types.Container
 		wantState *fakeVolumeRemovingDocker
+		wantErr   error
 	}{
 		{
 			name:   "simple-name",
 			inName: "simple-name",
+			inVols: []*volume.Volume{{Name: "simple-name"}},
 			wantState: &fakeVolumeRemovingDocker{
 				Name:  "simple-name",
 				Force: false,
@@ -40,22 +63,66 @@ func TestVolumeRemove(t *testing.T) {
 			name:   "simple-name-forced",
 			inName: "simple-name",
 			inOpts: []options.Option{options.Force()},
+			inVols: []*volume.Volume{{Name: "simple-name"}},
+			wantState: &fakeVolumeRemovingDocker{
+				Name:  "simple-name",
+				Force: true,
+			},
+		},
+		{
+			name:   "in-use",
+			inName: "simple-name",
+			inVols: []*volume.Volume{{Name: "simple-name"}},
+			inCnts: map[string][]types.Container{
+				"simple-name": {{Names: []string{"/some-container"}}, {Names: []string{"/other-container"}}},
+			},
+			wantErr: detailedError(codes.FailedPrecondition, reasonVolumeInUse, map[string]string{
+				"volume":     "simple-name",
+				"containers": "some-container,other-container",
+			}, "volume simple-name is in use by containers: some-container, other-container"),
+		},
+		{
+			name:   "in-use-forced",
+			inName: "simple-name",
+			inOpts: []options.Option{options.Force()},
+			inVols: []*volume.Volume{{Name: "simple-name"}},
+			inCnts: map[string][]types.Container{
+				"simple-name": {{Names: []string{"/some-container"}}},
+			},
 			wantState: &fakeVolumeRemovingDocker{
 				Name:  "simple-name",
 				Force: true,
 			},
 		},
+		{
+			name:    "not-found",
+			inName:  "simple-name",
+			inVols:  []*volume.Volume{{Name: "simple-name-2"}},
+			wantErr: detailedError(codes.NotFound, reasonVolumeNotFound, map[string]string{"volume": "simple-name"}, "volume simple-name not found"),
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
-			fcd := &fakeVolumeRemovingDocker{}
+			fcd := &fakeVolumeRemovingDocker{
+				volumes:    tc.inVols,
+				containers: tc.inCnts,
+			}
 			mgr := New(fcd)
 
 			if err := mgr.VolumeRemove(ctx, tc.inName, tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("VolumeRemove(%s, %+v) returned unexpected error(-want, got):\n %s", tc.inName, tc.inOpts, diff)
+					}
+					return
+				}
 				t.Errorf("VolumeRemove(%s, %+v) returned error: %v", tc.inName, tc.inOpts, err)
 			}
+			if tc.wantErr != nil {
+				t.Fatalf("VolumeRemove(%s, %+v) did not return an error, want %v", tc.inName, tc.inOpts, tc.wantErr)
+			}
 
 			if tc.wantState != nil {
 				if diff := cmp.Diff(tc.wantState, fcd, cmpopts.IgnoreUnexported(fakeVolumeRemovingDocker{})); diff != "" {
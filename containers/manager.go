@@ -0,0 +1,68 @@
+// Copyright 2023 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"context"
+)
+
+// ContainerManager is the set of container lifecycle operations that a container runtime
+// backend provides. Each backend is responsible for translating the options it supports into
+// the form its runtime expects.
+type ContainerManager interface {
+	// Start starts the manager and any background tasks it runs.
+	Start(context.Context) error
+
+	// Stop stops the manager and releases its connection to the runtime.
+	Stop(context.Context) error
+
+	// ContainerList list the containers on the target.
+	//
+	// It takes:
+	// all (bool): return all containers regardless of state
+	// limit (int32): return limit number of results.
+	//
+	// It returns an error indicating the result of the operation.
+	ContainerList(context.Context, bool, int32, ListContainerStreamer, ...Option) error
+
+	// ContainerRemove removes an container provided that it is not running.
+	//
+	// It takes:
+	// - container (string): the container name to remove.
+	//
+	// It returns an error indicating if the remove operation succeeded.
+	ContainerRemove(context.Context, string, ...Option) error
+
+	// ContainerStart starts a container based on the supplied image and tag.
+	//
+	// It takes:
+	// - image (string): the image to use
+	// - tag (string): the tag to use
+	// - cmd (string): a command to run.
+	//
+	// It returns an error indicating if the start operation succeeded along with the ID of the
+	// started container.
+	ContainerStart(context.Context, string, string, string, ...Option) (string, error)
+
+	// ContainerStop stops a container. If the Force option is passed it will forcefully stop
+	// (kill) the container. A stop timeout can be provided via the context otherwise the
+	// system default will be used.
+	//
+	// It takes:
+	// - instance (string): the instance name of the running container.
+	//
+	// It returns an error indicating whether the result was successful
+	ContainerStop(context.Context, string, ...Option) error
+}
@@ -17,12 +17,14 @@ package options
 
 import (
 	"fmt"
+	"io"
 	"math/big"
 	"time"
 
 	cpb "github.com/openconfig/gnoi/containerz"
 	tpb "github.com/openconfig/gnoi/types"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // Stream represents an entity capable of sending responses to the client.
@@ -50,6 +52,251 @@ type ListVolumeStreamer interface {
 	Send(msg *cpb.ListVolumeResponse) error
 }
 
+// ContainerEventStreamer is an entity capable of streaming container events.
+type ContainerEventStreamer interface {
+	Send(event *ContainerEvent) error
+}
+
+// ContainerEvent is a normalized event reported by the runtime for a container.
+type ContainerEvent struct {
+	// Time is when the runtime reported the event.
+	Time time.Time
+
+	// ID is the runtime identifier of the container.
+	ID string
+
+	// Instance is the instance name of the container.
+	Instance string
+
+	// Action is the kind of event: die, oom or health_status.
+	Action string
+
+	// ExitCode is the exit code of the container, for die events.
+	ExitCode int
+
+	// Health is the new health status of the container, for health_status events.
+	Health string
+}
+
+// HostInfo describes the container runtime of the target and the resources available to it.
+// Fields the runtime does not report are left unset.
+type HostInfo struct {
+	// RuntimeVersion is the version of the container runtime.
+	RuntimeVersion string
+
+	// APIVersion is the API version served by the container runtime.
+	APIVersion string
+
+	// OS is the operating system of the host (e.g. "Debian GNU/Linux 12 (bookworm)").
+	OS string
+
+	// KernelVersion is the kernel version of the host.
+	KernelVersion string
+
+	// Architecture is the hardware architecture of the host (e.g. "x86_64").
+	Architecture string
+
+	// StorageDriver is the storage driver used by the container runtime.
+	StorageDriver string
+
+	// CPUs is the number of CPUs available to the container runtime.
+	CPUs int
+
+	// MemoryBytes is the total memory of the host.
+	MemoryBytes int64
+
+	// Containers is the number of containers on the host, in any state.
+	Containers int
+
+	// ContainersRunning is the number of running containers on the host.
+	ContainersRunning int
+
+	// Images is the number of images on the host.
+	Images int
+
+	// DiskUsageBytes is the disk space used by images, container filesystems and volumes.
+	DiskUsageBytes int64
+}
+
+// DiskUsage is the disk space used on the target by each category of runtime object.
+type DiskUsage struct {
+	// Images is the space used by image layers.
+	Images DiskUsageCategory
+
+	// Containers is the space used by the writable layers of containers.
+	Containers DiskUsageCategory
+
+	// Volumes is the space used by volumes.
+	Volumes DiskUsageCategory
+
+	// BuildCache is the space used by the build cache.
+	BuildCache DiskUsageCategory
+
+	// PerImage is the space used by each image, ordered by image ID. It is only set when
+	// requested with WithPerImageUsage.
+	PerImage []*ImageDiskUsage
+}
+
+// DiskUsageCategory is the disk space used by one category of runtime object.
+type DiskUsageCategory struct {
+	// Count is the number of objects in the category.
+	Count int
+
+	// SizeBytes is the space used by the objects.
+	SizeBytes int64
+
+	// ReclaimableBytes is the space that would be freed by removing the unused objects.
+	ReclaimableBytes int64
+}
+
+// ImageDiskUsage is the disk space used by an image.
+type ImageDiskUsage struct {
+	// ID is the runtime identifier of the image.
+	ID string
+
+	// Tags are the references the image is tagged as.
+	Tags []string
+
+	// SizeBytes is the total size of the image, including layers shared with other images.
+	SizeBytes int64
+
+	// SharedBytes is the size of the layers shared with other images, or -1 if unknown.
+	SharedBytes int64
+
+	// Containers is the number of containers using the image.
+	Containers int64
+}
+
+// ImageInfo describes a reference of an image present on the target.
+type ImageInfo struct {
+	// ID is the runtime identifier of the image.
+	ID string
+
+	// Repository is the repository of the reference, or "<none>" for a dangling image.
+	Repository string
+
+	// Tag is the tag of the reference, or "<none>" for a dangling image.
+	Tag string
+
+	// Digest is the digest of the image in its repository, if it was pulled from a registry.
+	Digest string
+
+	// SizeBytes is the total size of the image, including layers shared with other images.
+	SizeBytes int64
+
+	// Created is when the image was created.
+	Created time.Time
+}
+
+// PluginInfo describes a plugin installed on the target.
+type PluginInfo struct {
+	// ID is the runtime identifier of the plugin.
+	ID string
+
+	// Name is the name of the plugin, including its tag.
+	Name string
+
+	// Reference is the remote reference the plugin was pulled from, if any.
+	Reference string
+
+	// Enabled reports whether the plugin is running.
+	Enabled bool
+}
+
+// StopResult is the outcome of stopping one container of a batch.
+type StopResult struct {
+	// Instance is the instance name of the container.
+	Instance string
+
+	// Err is the error the container failed to stop with, or nil if it is stopped.
+	Err error
+}
+
+// ContainerStats is a normalized snapshot of the resource usage of a container.
+type ContainerStats struct {
+	// CPUPercent is the CPU usage of the container as a percentage of a single CPU.
+	CPUPercent float64
+
+	// MemoryUsage is the memory, in bytes, used by the container excluding the page cache.
+	MemoryUsage uint64
+
+	// MemoryLimit is the memory limit, in bytes, of the container.
+	MemoryLimit uint64
+
+	// NetworkRxBytes is the total number of bytes received by the container on all interfaces.
+	NetworkRxBytes uint64
+
+	// NetworkTxBytes is the total number of bytes sent by the container on all interfaces.
+	NetworkTxBytes uint64
+}
+
+// ContainerInfo is a normalized view of the configuration and state of a container. Slices are
+// sorted so that two ContainerInfo values can be compared directly.
+type ContainerInfo struct {
+	// ID is the runtime identifier of the container.
+	ID string
+
+	// Name is the instance name of the container.
+	Name string
+
+	// Image is the image reference the container was created from.
+	Image string
+
+	// Status is the runtime status of the container (e.g. "running" or "exited").
+	Status string
+
+	// Cmd is the command the container runs.
+	Cmd []string
+
+	// Entrypoint is the entrypoint the container runs.
+	Entrypoint []string
+
+	// Env is the set of environment variables, in key=value form, sorted by key.
+	Env []string
+
+	// Labels is the set of labels, in key=value form, sorted by key.
+	Labels []string
+
+	// Mounts is the set of mounts, sorted by destination.
+	Mounts []*ContainerMount
+
+	// RestartPolicy is the name of the restart policy of the container.
+	RestartPolicy string
+
+	// RestartAttempts is the maximum number of restart attempts for the on-failure policy.
+	RestartAttempts int
+
+	// NetworkMode is the network mode of the container.
+	NetworkMode string
+
+	// Networks is the set of networks the container is attached to, sorted by name.
+	Networks []string
+
+	// CPU is the CPU limit of the container.
+	CPU float64
+
+	// SoftMemory is the soft memory limit, in bytes, of the container.
+	SoftMemory int64
+
+	// HardMemory is the hard memory limit, in bytes, of the container.
+	HardMemory int64
+}
+
+// ContainerMount describes a mount attached to a container.
+type ContainerMount struct {
+	// Type is the type of the mount (e.g. "volume" or "bind").
+	Type string
+
+	// Source is the volume name or host path of the mount.
+	Source string
+
+	// Destination is the path of the mount inside the container.
+	Destination string
+
+	// ReadOnly is set if the mount is read-only.
+	ReadOnly bool
+}
+
 // FilterKey represents a key for a filter.
 type FilterKey string
 
@@ -66,8 +313,140 @@ const (
 
 	// Volume filters by volume name.
 	Volume = "volume"
+
+	// Label filters by label, either as key or key=value.
+	Label = "label"
+
+	// Driver filters volumes by the driver backing them.
+	Driver = "driver"
+)
+
+// PullPolicy controls whether the image of a container is pulled before the container is started.
+type PullPolicy string
+
+const (
+	// PullNever only starts containers from images already present on the target. It is the
+	// default.
+	PullNever PullPolicy = "Never"
+
+	// PullIfNotPresent pulls the image if it is not present on the target.
+	PullIfNotPresent PullPolicy = "IfNotPresent"
+
+	// PullAlways pulls the image before every start, even if it is present on the target.
+	PullAlways PullPolicy = "Always"
 )
 
+// HealthCheck describes how the container runtime should check that a container is healthy.
+type HealthCheck struct {
+	// Test is the command to run to check the health of the container. An empty command
+	// disables health checking.
+	Test string
+
+	// Interval is the time to wait between checks.
+	Interval time.Duration
+
+	// Timeout is the time to wait before considering a check to have hung.
+	Timeout time.Duration
+
+	// Retries is the number of consecutive failures needed to consider a container unhealthy.
+	Retries int
+
+	// StartPeriod is the initialization time during which failures are not counted.
+	StartPeriod time.Duration
+}
+
+// Port publishes a container port on a host port.
+type Port struct {
+	// Internal is the port exposed by the container.
+	Internal uint32
+
+	// External is the host port the container port is published on.
+	External uint32
+
+	// Protocol is the transport protocol of the port: tcp, udp or sctp. If unset, tcp is used.
+	Protocol string
+
+	// HostIP is the host address the port is published on. If unset, the port is published on
+	// every IPv4 and IPv6 address of the host.
+	HostIP string
+}
+
+// BindMount mounts a directory or file of the host into a container.
+type BindMount struct {
+	// Source is the absolute path on the host to mount.
+	Source string
+
+	// Target is the absolute path in the container the source is mounted on.
+	Target string
+
+	// ReadOnly indicates that the mount is read-only in the container.
+	ReadOnly bool
+
+	// Propagation is the bind propagation mode of the mount: private, rprivate, shared, rshared,
+	// slave or rslave. If unset, the runtime default (rprivate) is used.
+	Propagation string
+
+	// Consistency is the consistency requirement of the mount: consistent, cached, delegated or
+	// default. It only has an effect on runtimes sharing files with a VM (e.g. Docker Desktop).
+	Consistency string
+}
+
+// Secret is a named secret provided to a container as a read-only file.
+type Secret struct {
+	// Name identifies the secret and is the name of its file. It may not contain a slash.
+	Name string
+
+	// Data is the content of the secret.
+	Data []byte
+
+	// Target is the absolute path of the file in the container. If unset, the secret is
+	// mounted at /run/secrets/<Name>.
+	Target string
+}
+
+// BlkioDeviceLimit limits the rate at which a container may read from and write to a block
+// device. A zero rate leaves that direction unlimited.
+type BlkioDeviceLimit struct {
+	// Path is the absolute path of the block device (e.g. "/dev/sda").
+	Path string
+
+	// ReadBps is the maximum number of bytes read per second.
+	ReadBps uint64
+
+	// WriteBps is the maximum number of bytes written per second.
+	WriteBps uint64
+
+	// ReadIOps is the maximum number of read operations per second.
+	ReadIOps uint64
+
+	// WriteIOps is the maximum number of write operations per second.
+	WriteIOps uint64
+}
+
+// Platform identifies the variant of a multi-architecture image.
+type Platform struct {
+	// OS is the operating system of the platform (e.g. "linux").
+	OS string
+
+	// Architecture is the CPU architecture of the platform (e.g. "arm64").
+	Architecture string
+
+	// Variant is the variant of the CPU architecture (e.g. "v7"), if any.
+	Variant string
+}
+
+// Ulimit is a resource limit applied to the processes of a container.
+type Ulimit struct {
+	// Name is the name of the limit (e.g. "nofile").
+	Name string
+
+	// Soft is the soft limit.
+	Soft int64
+
+	// Hard is the hard limit.
+	Hard int64
+}
+
 // Option takes an option and applies it to the set of options when the function is called.
 type Option func(*options)
 
@@ -90,15 +469,25 @@ type options struct {
 	// Force indicates that the container implementation should attempt to force the operation.
 	Force bool
 
+	// RemoveVolumes indicates that the anonymous volumes of a container should be removed with it.
+	RemoveVolumes bool
+
 	// InstanceName is the name of a running container instance.
 	InstanceName string
 
 	// PortMapping is a mapping of internal to external port for a container.
 	PortMapping map[uint32]uint32
 
+	// Ports is a set of ports, of any protocol, to publish alongside the tcp ports of PortMapping.
+	Ports []Port
+
 	// EnvMapping is a set of environment variables to set in the container
 	EnvMapping map[string]string
 
+	// EnvFile is the path, on the host, of a file of KEY=VALUE environment variables to set in
+	// the container. Variables of EnvMapping take precedence over those of the file.
+	EnvFile string
+
 	// Follow indicates that logs should be streamed until cancelled.
 	Follow bool
 
@@ -108,6 +497,16 @@ type options struct {
 	// Since indicates until what time, relative to now, logs should be streamed.
 	Until time.Duration
 
+	// Tail is the number of lines to return from the end of the logs. If unset, all lines are
+	// returned.
+	Tail int
+
+	// NoStdout indicates that the stdout stream of the container should not be returned.
+	NoStdout bool
+
+	// NoStderr indicates that the stderr stream of the container should not be returned.
+	NoStderr bool
+
 	// All indicates that we should return all containers regardless of their state.
 	All bool
 
@@ -131,6 +530,23 @@ type options struct {
 	// other network available in the runtime.
 	Network string
 
+	// Networks is the set of networks to attach this container to. The first network is used as
+	// the primary network of the container.
+	Networks []string
+
+	// StaticIPs maps a network to the static IPv4 and/or IPv6 addresses to assign the container
+	// on that network.
+	StaticIPs map[string][]string
+
+	// DNS is the list of nameservers the container should use.
+	DNS []string
+
+	// DNSSearch is the list of DNS search domains the container should use.
+	DNSSearch []string
+
+	// ExtraHosts is a list of additional /etc/hosts entries in the form hostname:ip.
+	ExtraHosts []string
+
 	// Capabilities to be added/removed. Capabilities are first removed then added.
 	Capabilities proto.Message
 
@@ -150,18 +566,206 @@ type options struct {
 	// CPU is the CPU limit for the container.
 	CPU float64
 
+	// CPUShares is the relative weight of the container when competing for CPU time.
+	CPUShares int64
+
+	// CPUQuota is the CPU time, in microseconds, the container may use per CFS period.
+	CPUQuota int64
+
+	// CPUPeriod is the length, in microseconds, of the CFS period.
+	CPUPeriod int64
+
 	// SoftMemory is the soft memory limit for the container.
 	SoftMemory int64
 
 	// HardMemory is the hard memory limit for the container.
 	HardMemory int64
 
+	// MemorySwap is the combined memory and swap limit, in bytes, for the container. -1 allows
+	// unlimited swap while 0 keeps the runtime default.
+	MemorySwap int64
+
 	// Devices is the set of devices to attach to the container.
 	Devices []*cpb.Device
+
+	// ReadOnlyRootFS indicates that the container's root filesystem should be mounted read-only.
+	// Volumes attached to the container remain writable unless they are themselves read-only.
+	ReadOnlyRootFS bool
+
+	// BindMounts is the set of host paths to bind-mount into the container.
+	BindMounts []BindMount
+
+	// Secrets is the set of secrets to mount as read-only files in the container.
+	Secrets []Secret
+
+	// Tmpfs is a mapping of container paths to tmpfs mount options (e.g. "rw,size=64m").
+	Tmpfs map[string]string
+
+	// HealthCheck is the health check configuration for the container.
+	HealthCheck *HealthCheck
+
+	// WaitHealthy is how long to wait for a started container to report healthy. If unset, the
+	// start returns as soon as the container is started.
+	WaitHealthy time.Duration
+
+	// StopGracePeriod is the time, in seconds, to wait for a container to stop before it is
+	// forcefully terminated.
+	StopGracePeriod int
+
+	// StopSignal is the signal used to request that a container stops (e.g. "SIGTERM").
+	StopSignal string
+
+	// StopTimeout is the time, in seconds, the runtime waits for a container to stop before
+	// killing it when the stop is not initiated with a grace period, e.g. when the daemon shuts
+	// down. A nil value defers to the runtime default.
+	StopTimeout *int
+
+	// PidsLimit is the maximum number of processes the container may run. A value of 0 or -1
+	// means unlimited.
+	PidsLimit int64
+
+	// Ulimits is the set of resource limits to apply to the container.
+	Ulimits []*Ulimit
+
+	// BlkioWeight is the relative block IO weight of the container, from 10 to 1000. If unset,
+	// the runtime default is used.
+	BlkioWeight uint16
+
+	// BlkioDeviceLimits is the set of block IO rate limits to apply to the container.
+	BlkioDeviceLimits []*BlkioDeviceLimit
+
+	// CpusetCpus is the set of CPUs the container may run on (e.g. "0-2,4").
+	CpusetCpus string
+
+	// CpusetMems is the set of memory nodes the container may use (e.g. "0,1").
+	CpusetMems string
+
+	// GPUs is the number of GPUs to attach to the container, or "all".
+	GPUs string
+
+	// GPUCapabilities is an optional list of capabilities requested from the GPU driver.
+	GPUCapabilities []string
+
+	// ShmSize is the size, in bytes, of /dev/shm. If unset, the runtime default is used.
+	ShmSize int64
+
+	// OOMScoreAdj adjusts the likelihood of the container being killed when the host runs out of
+	// memory, from -1000 (never) to 1000.
+	OOMScoreAdj int
+
+	// OOMKillDisable disables the OOM killer for the container. It requires a hard memory limit.
+	OOMKillDisable bool
+
+	// SecurityOpts is the set of security options (e.g. seccomp or AppArmor profiles) to apply to
+	// the container.
+	SecurityOpts []string
+
+	// PidMode is the PID namespace of the container: "host" or "container:<name>". If unset, the
+	// container gets its own PID namespace.
+	PidMode string
+
+	// IpcMode is the IPC namespace of the container: "host", "container:<name>", "private",
+	// "shareable" or "none". If unset, the runtime default is used.
+	IpcMode string
+
+	// UsernsMode is the user namespace mode of the container: "host" or "private". If unset, the
+	// runtime default is used.
+	UsernsMode string
+
+	// GroupAdd is the set of supplementary groups, by name or GID, the container process joins.
+	GroupAdd []string
+
+	// Sysctls is the set of namespaced kernel parameters to set in the container.
+	Sysctls map[string]string
+
+	// LogDriver is the logging driver of the container. If unset, the runtime default is used.
+	LogDriver string
+
+	// LogOptions are the options of the logging driver, e.g. max-size and max-file.
+	LogOptions map[string]string
+
+	// WorkingDir is the absolute path in which the container command runs.
+	WorkingDir string
+
+	// Privileged indicates that the container should run in privileged mode.
+	Privileged bool
+
+	// Runtime is the OCI runtime the container is run with, e.g. "runsc" for gVisor. If unset,
+	// the daemon default is used.
+	Runtime string
+
+	// AcknowledgeDockerSocket acknowledges that a bind mount of the docker socket grants the
+	// container control of the host.
+	AcknowledgeDockerSocket bool
+
+	// Hostname is the hostname of the container.
+	Hostname string
+
+	// Domainname is the domain name of the container.
+	Domainname string
+
+	// Entrypoint overrides the image entrypoint. A nil value keeps the image entrypoint while an
+	// empty string clears it.
+	Entrypoint *string
+
+	// TTY controls whether a TTY is allocated for the container. A nil value keeps the default of
+	// allocating one.
+	TTY *bool
+
+	// StdinOpen keeps stdin open and attached for the container.
+	StdinOpen bool
+
+	// Init controls whether an init process is run as PID 1 of the container to reap zombie
+	// processes. A nil value defers to the runtime default.
+	Init *bool
+
+	// ExpectedDigest is the sha256 digest the image must match before a container is started.
+	ExpectedDigest string
+
+	// PullPolicy controls whether the image is pulled before a container is started.
+	PullPolicy PullPolicy
+
+	// Platform is the platform of the image variant to pull and run. If unset, the platform of
+	// the target is used.
+	Platform *Platform
+
+	// Addresses, if set, receives the IP addresses assigned to a started container, by network.
+	Addresses map[string][]string
+
+	// DryRun, if set, receives the configuration a container would be started with instead of
+	// the container being started.
+	DryRun *structpb.Struct
+
+	// IdempotentStart makes a start succeed, without creating a container, if an identical
+	// container is already running under the instance name.
+	IdempotentStart bool
+
+	// PrivilegedPortThreshold is the lowest host port a container may bind, unless
+	// AllowPrivilegedPorts is set. If unset, any valid host port may be bound.
+	PrivilegedPortThreshold uint32
+
+	// AllowPrivilegedPorts allows host ports below PrivilegedPortThreshold to be bound.
+	AllowPrivilegedPorts bool
+
+	// GrantPluginPrivileges grants a plugin the privileges it requests when it is installed.
+	GrantPluginPrivileges bool
+
+	// PerImageUsage includes the disk usage of every image in a disk usage report.
+	PerImageUsage bool
+
+	// Concurrency bounds the number of containers inspected, or stopped, in parallel. If unset, a
+	// default bound is used.
+	Concurrency int
+
+	// BuildArgs are the build-time variables of an image build, by name.
+	BuildArgs map[string]string
+
+	// BuildOutput, if set, receives the output of the steps of an image build.
+	BuildOutput io.Writer
 }
 
 // WithTarget sets the target image name and tag option for this pull operation.
-// Supported by: ImagePush, ImagePull
+// Supported by: ImageBuild, ImagePush, ImagePull
 func WithTarget(image, tag string) Option {
 	return func(p *options) {
 		p.TargetName = image
@@ -173,7 +777,7 @@ func WithTarget(image, tag string) Option {
 }
 
 // WithRegistryAuth sets the credentials to use for this pull operation.
-// Supported by: ImagePull
+// Supported by: ImagePull, PluginInstall
 func WithRegistryAuth(creds *tpb.Credentials) Option {
 	return func(p *options) {
 		p.Credentials = creds
@@ -189,13 +793,22 @@ func WithStream(client Stream) Option {
 }
 
 // Force sets the force operation field in the image options.
-// Supported by: ContainerRemove, ContainerStop
+// Supported by: ContainerRemove, ContainerRestart, ContainerStop, PluginRemove, Reconcile
 func Force() Option {
 	return func(p *options) {
 		p.Force = true
 	}
 }
 
+// WithRemoveVolumes removes the anonymous volumes of a container along with it. Named volumes are
+// kept.
+// Supported by: ContainerRemove
+func WithRemoveVolumes() Option {
+	return func(p *options) {
+		p.RemoveVolumes = true
+	}
+}
+
 // WithInstanceName sets the name of the instance of a container.
 // Supported by: ContainerStart
 func WithInstanceName(instance string) Option {
@@ -212,14 +825,34 @@ func WithPorts(portMappings map[uint32]uint32) Option {
 	}
 }
 
+// WithPortMappings specifies a set of exposed ports, each with its own protocol and optional host
+// address, for a container.
+// They are exposed in addition to those set by WithPorts.
+// Supported by: ContainerStart, ContainerUpdate
+func WithPortMappings(ports []Port) Option {
+	return func(p *options) {
+		p.Ports = ports
+	}
+}
+
 // WithEnv specifies the set environment variables to set in the container.
-// Supported by: ContainerStart
+// Supported by: ContainerStart, ContainerExec
 func WithEnv(envMapping map[string]string) Option {
 	return func(p *options) {
 		p.EnvMapping = envMapping
 	}
 }
 
+// WithEnvFile specifies a file on the host from which to read environment variables to set in
+// the container, one KEY=VALUE per line. Blank lines and lines starting with # are ignored.
+// Variables set by WithEnv take precedence over those read from the file.
+// Supported by: ContainerStart
+func WithEnvFile(path string) Option {
+	return func(p *options) {
+		p.EnvFile = path
+	}
+}
+
 // Follow specifies whether logs should be followed.
 func Follow() Option {
 	return func(p *options) {
@@ -241,6 +874,27 @@ func WithSince(t time.Duration) Option {
 	}
 }
 
+// WithTail specifies the number of lines to return from the end of the logs.
+func WithTail(lines int) Option {
+	return func(p *options) {
+		p.Tail = lines
+	}
+}
+
+// WithoutStdout specifies that the stdout stream should not be returned.
+func WithoutStdout() Option {
+	return func(p *options) {
+		p.NoStdout = true
+	}
+}
+
+// WithoutStderr specifies that the stderr stream should not be returned.
+func WithoutStderr() Option {
+	return func(p *options) {
+		p.NoStderr = true
+	}
+}
+
 // WithFilter provides the filter option.
 // Supported by: ContainerList, VolumeList
 func WithFilter(filter map[FilterKey][]string) Option {
@@ -281,6 +935,48 @@ func WithNetwork(network string) Option {
 	}
 }
 
+// WithNetworks provides the networks to attach this container to. The first network is the
+// primary network of the container.
+// Supported by: ContainerStart
+func WithNetworks(networks []string) Option {
+	return func(p *options) {
+		p.Networks = networks
+	}
+}
+
+// WithStaticIPs provides the static addresses to assign to the container on each network. Each
+// network may be given at most one IPv4 and one IPv6 address.
+// Supported by: ContainerStart
+func WithStaticIPs(ips map[string][]string) Option {
+	return func(p *options) {
+		p.StaticIPs = ips
+	}
+}
+
+// WithDNS provides the nameservers the container should use.
+// Supported by: ContainerStart
+func WithDNS(servers []string) Option {
+	return func(p *options) {
+		p.DNS = servers
+	}
+}
+
+// WithDNSSearch provides the DNS search domains the container should use.
+// Supported by: ContainerStart
+func WithDNSSearch(domains []string) Option {
+	return func(p *options) {
+		p.DNSSearch = domains
+	}
+}
+
+// WithExtraHosts provides additional /etc/hosts entries in the form hostname:ip.
+// Supported by: ContainerStart
+func WithExtraHosts(hosts []string) Option {
+	return func(p *options) {
+		p.ExtraHosts = hosts
+	}
+}
+
 // WithCapabilities provides optional lists of added/removed container capabilities.
 // Supported by: ContainerStart, ContainerUpdate
 func WithCapabilities(opts proto.Message) Option {
@@ -290,7 +986,7 @@ func WithCapabilities(opts proto.Message) Option {
 }
 
 // WithRestartPolicy provides an optional restart policy for the container.
-// Supported by: ContainerStart, ContainerUpdate
+// Supported by: ContainerStart, ContainerUpdate, ContainerUpdateResources
 func WithRestartPolicy(opts proto.Message) Option {
 	return func(p *options) {
 		p.RestartPolicy = opts
@@ -314,15 +1010,41 @@ func WithLabels(labels map[string]string) Option {
 }
 
 // WithCPUs provides the CPU limit for the container.
-// Supported by: ContainerStart, ContainerUpdate
+// Supported by: ContainerStart, ContainerUpdate, ContainerUpdateResources
 func WithCPUs(cpus float64) Option {
 	return func(p *options) {
 		p.CPU = cpus
 	}
 }
 
+// WithCPUShares provides the relative CPU weight of the container.
+// Supported by: ContainerStart
+func WithCPUShares(shares int64) Option {
+	return func(p *options) {
+		p.CPUShares = shares
+	}
+}
+
+// WithCPUQuota provides the CPU time, in microseconds, the container may use per CFS period. It
+// cannot be combined with WithCPUs.
+// Supported by: ContainerStart
+func WithCPUQuota(quota int64) Option {
+	return func(p *options) {
+		p.CPUQuota = quota
+	}
+}
+
+// WithCPUPeriod provides the length, in microseconds, of the CFS period of the container. It
+// cannot be combined with WithCPUs.
+// Supported by: ContainerStart
+func WithCPUPeriod(period int64) Option {
+	return func(p *options) {
+		p.CPUPeriod = period
+	}
+}
+
 // WithSoftLimit provides the soft memory limit (in bytes) for the container.
-// Supported by: ContainerStart, ContainerUpdate
+// Supported by: ContainerStart, ContainerUpdate, ContainerUpdateResources
 func WithSoftLimit(mem int64) Option {
 	return func(p *options) {
 		p.SoftMemory = mem
@@ -330,13 +1052,22 @@ func WithSoftLimit(mem int64) Option {
 }
 
 // WithHardLimit provides the hard memory limit (in bytes) for the container.
-// Supported by: ContainerStart, ContainerUpdate
+// Supported by: ContainerStart, ContainerUpdate, ContainerUpdateResources
 func WithHardLimit(mem int64) Option {
 	return func(p *options) {
 		p.HardMemory = mem
 	}
 }
 
+// WithMemorySwap provides the combined memory and swap limit (in bytes) for the container. It
+// must be at least the hard memory limit, or -1 for unlimited swap.
+// Supported by: ContainerStart
+func WithMemorySwap(swap int64) Option {
+	return func(p *options) {
+		p.MemorySwap = swap
+	}
+}
+
 // WithDevices sets the devices to attach to a container.
 // Supported by: ContainerStart
 func WithDevices(devices []*cpb.Device) Option {
@@ -345,6 +1076,453 @@ func WithDevices(devices []*cpb.Device) Option {
 	}
 }
 
+// WithReadOnlyRootFS sets whether the root filesystem of the container is mounted read-only.
+// Supported by: ContainerStart
+func WithReadOnlyRootFS(readOnly bool) Option {
+	return func(p *options) {
+		p.ReadOnlyRootFS = readOnly
+	}
+}
+
+// WithBindMounts sets the host paths to bind-mount into a container, alongside any volumes.
+// Supported by: ContainerStart
+func WithBindMounts(mounts []BindMount) Option {
+	return func(p *options) {
+		p.BindMounts = mounts
+	}
+}
+
+// WithSecrets mounts secrets into a container as read-only files, readable by their owner
+// only. The files are kept in memory on the host rather than passed as environment variables.
+// Supported by: ContainerStart
+func WithSecrets(secrets []Secret) Option {
+	return func(p *options) {
+		p.Secrets = secrets
+	}
+}
+
+// WithTmpfs sets the tmpfs mounts to attach to a container. The map is keyed by the path in the
+// container and the value contains the tmpfs mount options.
+// Supported by: ContainerStart
+func WithTmpfs(tmpfs map[string]string) Option {
+	return func(p *options) {
+		p.Tmpfs = tmpfs
+	}
+}
+
+// WithWaitHealthy waits, for up to timeout, for the started container to report healthy before
+// returning. The container must have a health check, either from WithHealthCheck or its image.
+// Supported by: ContainerStart
+func WithWaitHealthy(timeout time.Duration) Option {
+	return func(p *options) {
+		p.WaitHealthy = timeout
+	}
+}
+
+// WithHealthCheck sets the health check to apply to the container. An empty test command
+// disables any health check defined by the image.
+// Supported by: ContainerStart
+func WithHealthCheck(test string, interval, timeout time.Duration, retries int, startPeriod time.Duration) Option {
+	return func(p *options) {
+		p.HealthCheck = &HealthCheck{
+			Test:        test,
+			Interval:    interval,
+			Timeout:     timeout,
+			Retries:     retries,
+			StartPeriod: startPeriod,
+		}
+	}
+}
+
+// WithPidsLimit sets the maximum number of processes the container may run. A value of 0 or -1
+// means unlimited.
+// Supported by: ContainerStart
+func WithPidsLimit(limit int64) Option {
+	return func(p *options) {
+		p.PidsLimit = limit
+	}
+}
+
+// WithUlimits sets the resource limits to apply to the container.
+// Supported by: ContainerStart
+func WithUlimits(ulimits []*Ulimit) Option {
+	return func(p *options) {
+		p.Ulimits = ulimits
+	}
+}
+
+// WithBlkioWeight sets the relative block IO weight of the container, between 10 and 1000.
+// Supported by: ContainerStart
+func WithBlkioWeight(weight uint16) Option {
+	return func(p *options) {
+		p.BlkioWeight = weight
+	}
+}
+
+// WithBlkioDeviceLimits sets the block IO rate limits to apply to the container.
+// Supported by: ContainerStart
+func WithBlkioDeviceLimits(limits []*BlkioDeviceLimit) Option {
+	return func(p *options) {
+		p.BlkioDeviceLimits = limits
+	}
+}
+
+// WithCpusetCpus sets the CPUs the container is allowed to run on. The value is a comma-separated
+// list of CPUs or CPU ranges (e.g. "0-2,4").
+// Supported by: ContainerStart
+func WithCpusetCpus(cpus string) Option {
+	return func(p *options) {
+		p.CpusetCpus = cpus
+	}
+}
+
+// WithCpusetMems sets the memory nodes the container is allowed to use. The value is a
+// comma-separated list of nodes or node ranges (e.g. "0,1").
+// Supported by: ContainerStart
+func WithCpusetMems(mems string) Option {
+	return func(p *options) {
+		p.CpusetMems = mems
+	}
+}
+
+// WithGPUs requests GPUs for the container. The count is either a positive number of GPUs or
+// "all". Capabilities optionally restrict the driver capabilities (e.g. "compute", "utility").
+// Supported by: ContainerStart
+func WithGPUs(count string, capabilities []string) Option {
+	return func(p *options) {
+		p.GPUs = count
+		p.GPUCapabilities = capabilities
+	}
+}
+
+// WithShmSize sets the size, in bytes, of /dev/shm in the container. A size of zero keeps the
+// runtime default.
+// Supported by: ContainerStart
+func WithShmSize(size int64) Option {
+	return func(p *options) {
+		p.ShmSize = size
+	}
+}
+
+// WithOOMScoreAdj adjusts the likelihood of the container being OOM killed. Lower values make it
+// less likely; the score must be between -1000 and 1000.
+// Supported by: ContainerStart
+func WithOOMScoreAdj(score int) Option {
+	return func(p *options) {
+		p.OOMScoreAdj = score
+	}
+}
+
+// WithOOMKillDisable disables the OOM killer for the container. A hard memory limit must also be
+// set, otherwise the container could exhaust the memory of the host.
+// Supported by: ContainerStart
+func WithOOMKillDisable(disable bool) Option {
+	return func(p *options) {
+		p.OOMKillDisable = disable
+	}
+}
+
+// WithSecurityOpts sets the security options to apply to the container, e.g.
+// "seccomp=/path/profile.json", "apparmor=my-profile" or "no-new-privileges". A seccomp profile
+// given by path is read from the host of the server.
+// Supported by: ContainerStart
+func WithSecurityOpts(opts []string) Option {
+	return func(p *options) {
+		p.SecurityOpts = opts
+	}
+}
+
+// WithPidMode sets the PID namespace of the container. "host" shares the PID namespace of the
+// host, and "container:<name>" joins that of another running container, e.g. to debug it.
+// Supported by: ContainerStart
+func WithPidMode(mode string) Option {
+	return func(p *options) {
+		p.PidMode = mode
+	}
+}
+
+// WithIpcMode sets the IPC namespace of the container. "host" shares the IPC namespace of the
+// host, and "container:<name>" joins that of another running container, which must itself be
+// shareable.
+// Supported by: ContainerStart
+func WithIpcMode(mode string) Option {
+	return func(p *options) {
+		p.IpcMode = mode
+	}
+}
+
+// WithUsernsMode sets the user namespace mode of the container. "host" shares the user namespace
+// of the host, disabling user namespace remapping for the container, while "private" runs it in
+// the remapped user namespace configured on the runtime (userns-remap).
+// Supported by: ContainerStart
+func WithUsernsMode(mode string) Option {
+	return func(p *options) {
+		p.UsernsMode = mode
+	}
+}
+
+// WithGroupAdd adds the container process to supplementary groups, in addition to the group set
+// by WithRunAs. Groups are given by name (e.g. "dialout") or numeric GID (e.g. "20").
+// Supported by: ContainerStart
+func WithGroupAdd(groups []string) Option {
+	return func(p *options) {
+		p.GroupAdd = groups
+	}
+}
+
+// WithSysctls sets the namespaced kernel parameters to set in the container.
+// Supported by: ContainerStart
+func WithSysctls(sysctls map[string]string) Option {
+	return func(p *options) {
+		p.Sysctls = sysctls
+	}
+}
+
+// WithLogConfig sets the logging driver of the container, one of json-file, journald or none,
+// along with its options. The json-file driver supports rotation with max-size (e.g. "10m") and
+// max-file.
+// Supported by: ContainerStart
+func WithLogConfig(driver string, opts map[string]string) Option {
+	return func(p *options) {
+		p.LogDriver = driver
+		p.LogOptions = opts
+	}
+}
+
+// WithWorkingDir sets the working directory for the container command. The path must be
+// absolute.
+// Supported by: ContainerStart, ContainerExec
+func WithWorkingDir(dir string) Option {
+	return func(p *options) {
+		p.WorkingDir = dir
+	}
+}
+
+// WithPrivileged runs the container in privileged mode, giving it access to every device of the
+// host. The manager must have been configured to allow privileged containers.
+// Supported by: ContainerStart
+func WithPrivileged(privileged bool) Option {
+	return func(p *options) {
+		p.Privileged = privileged
+	}
+}
+
+// WithAcknowledgeDockerSocket acknowledges that a bind mount of the docker socket grants the
+// container control of the host. Such mounts also need to be allowed by the server.
+// Supported by: ContainerStart
+func WithAcknowledgeDockerSocket() Option {
+	return func(p *options) {
+		p.AcknowledgeDockerSocket = true
+	}
+}
+
+// WithRuntime runs the container with the named OCI runtime, e.g. "runsc" for gVisor or
+// "kata-runtime" for Kata Containers, instead of the daemon default. The runtime must be one the
+// daemon is configured with.
+// Supported by: ContainerStart
+func WithRuntime(runtime string) Option {
+	return func(p *options) {
+		p.Runtime = runtime
+	}
+}
+
+// WithHostname sets the hostname of the container. It must be a valid RFC 1123 label.
+// Supported by: ContainerStart
+func WithHostname(hostname string) Option {
+	return func(p *options) {
+		p.Hostname = hostname
+	}
+}
+
+// WithDomainname sets the domain name of the container. Each of its labels must be a valid RFC
+// 1123 label.
+// Supported by: ContainerStart
+func WithDomainname(domainname string) Option {
+	return func(p *options) {
+		p.Domainname = domainname
+	}
+}
+
+// WithEntrypoint overrides the image entrypoint. The entrypoint is tokenized in the same way as
+// the container command, and an empty string clears the image entrypoint.
+// Supported by: ContainerStart
+func WithEntrypoint(entrypoint string) Option {
+	return func(p *options) {
+		p.Entrypoint = &entrypoint
+	}
+}
+
+// WithTTY controls whether a TTY is allocated for the container; one is allocated by default.
+// The output of a container with a TTY is not multiplexed, so its logs are returned as a single
+// raw stream and cannot be split into stdout and stderr.
+// Supported by: ContainerStart
+func WithTTY(tty bool) Option {
+	return func(p *options) {
+		p.TTY = &tty
+	}
+}
+
+// WithStdinOpen keeps stdin open and attached for the container.
+// Supported by: ContainerStart
+func WithStdinOpen(open bool) Option {
+	return func(p *options) {
+		p.StdinOpen = open
+	}
+}
+
+// WithInit controls whether an init process is run as PID 1 of the container, forwarding signals
+// and reaping zombie processes. If unset, the runtime default applies.
+// Supported by: ContainerStart
+func WithInit(init bool) Option {
+	return func(p *options) {
+		p.Init = &init
+	}
+}
+
+// WithExpectedDigest requires the image to have the provided sha256 digest (e.g.
+// "sha256:<hex>") before a container is started from it.
+// Supported by: ContainerStart
+func WithExpectedDigest(digest string) Option {
+	return func(p *options) {
+		p.ExpectedDigest = digest
+	}
+}
+
+// WithPullPolicy sets whether the image is pulled before the container is started. Any
+// credentials set with WithRegistryAuth are used for the pull.
+// Supported by: ContainerStart
+func WithPullPolicy(policy PullPolicy) Option {
+	return func(p *options) {
+		p.PullPolicy = policy
+	}
+}
+
+// WithPlatform selects the variant of a multi-architecture image to pull and run, e.g.
+// WithPlatform("linux", "arm", "v7"). The variant may be empty.
+// Supported by: ContainerStart, ImagePull
+func WithPlatform(os, arch, variant string) Option {
+	return func(p *options) {
+		p.Platform = &Platform{OS: os, Architecture: arch, Variant: variant}
+	}
+}
+
+// WithDryRun validates the start, including the image and port checks, without creating the
+// container. The configuration the container would be created with is stored in plan, as the
+// JSON form of the runtime's Config, HostConfig and NetworkingConfig. The image is never pulled.
+// Supported by: ContainerStart
+func WithDryRun(plan *structpb.Struct) Option {
+	return func(p *options) {
+		p.DryRun = plan
+	}
+}
+
+// WithIdempotentStart makes the start a no-op if a container started identically, also with
+// this option, is already running under the instance name; the name of the existing container
+// is returned. If the running container differs, the start fails with AlreadyExists as usual,
+// with the mismatch reported in the error details.
+// Supported by: ContainerStart
+func WithIdempotentStart() Option {
+	return func(p *options) {
+		p.IdempotentStart = true
+	}
+}
+
+// WithBuildArgs sets the build-time variables, the ARG instructions of the Dockerfile, of an
+// image build.
+// Supported by: ImageBuild
+func WithBuildArgs(args map[string]string) Option {
+	return func(p *options) {
+		p.BuildArgs = args
+	}
+}
+
+// WithBuildOutput writes the output of the steps of an image build to w as they run.
+// Supported by: ImageBuild
+func WithBuildOutput(w io.Writer) Option {
+	return func(p *options) {
+		p.BuildOutput = w
+	}
+}
+
+// WithAssignedAddresses stores the IP addresses assigned to the started container in addrs, keyed
+// by network name, with the IPv4 address of a network before its IPv6 one. Networks without an
+// address, such as the host network, are left out. addrs must not be nil.
+// Supported by: ContainerStart
+func WithAssignedAddresses(addrs map[string][]string) Option {
+	return func(p *options) {
+		p.Addresses = addrs
+	}
+}
+
+// WithPrivilegedPortThreshold rejects host ports below port, unless privileged ports are
+// explicitly allowed with WithAllowPrivilegedPorts.
+// Supported by: ContainerStart
+func WithPrivilegedPortThreshold(port uint32) Option {
+	return func(p *options) {
+		p.PrivilegedPortThreshold = port
+	}
+}
+
+// WithAllowPrivilegedPorts allows host ports below the privileged port threshold to be bound.
+// Supported by: ContainerStart
+func WithAllowPrivilegedPorts() Option {
+	return func(p *options) {
+		p.AllowPrivilegedPorts = true
+	}
+}
+
+// WithGrantPluginPrivileges grants a plugin the privileges it requests, such as access to host
+// devices or networking. Without it, plugins requesting privileges are not installed.
+// Supported by: PluginInstall
+func WithGrantPluginPrivileges() Option {
+	return func(p *options) {
+		p.GrantPluginPrivileges = true
+	}
+}
+
+// WithPerImageUsage includes the disk usage of every image in a disk usage report.
+// Supported by: DiskUsage
+func WithPerImageUsage() Option {
+	return func(p *options) {
+		p.PerImageUsage = true
+	}
+}
+
+// WithConcurrency bounds the number of containers that are inspected, or stopped, in parallel.
+// Supported by: ContainerList, StopAll
+func WithConcurrency(n int) Option {
+	return func(p *options) {
+		p.Concurrency = n
+	}
+}
+
+// WithStopGracePeriod sets the time, in seconds, to wait for the container to stop gracefully
+// before it is killed.
+// Supported by: ContainerStop
+func WithStopGracePeriod(seconds int) Option {
+	return func(p *options) {
+		p.StopGracePeriod = seconds
+	}
+}
+
+// WithStopSignal sets the signal to send to the container to stop it.
+// Supported by: ContainerRestart, ContainerStop
+func WithStopSignal(signal string) Option {
+	return func(p *options) {
+		p.StopSignal = signal
+	}
+}
+
+// WithStopTimeout sets the default time, in seconds, to wait for the container to stop before it
+// is killed. Unlike WithStopGracePeriod it is recorded on the container at creation, so it also
+// applies to stops the runtime initiates itself, such as on reboot.
+// Supported by: ContainerStart
+func WithStopTimeout(seconds int) Option {
+	return func(p *options) {
+		p.StopTimeout = &seconds
+	}
+}
+
 // ParseCPUs takes a float returns an integer value of nano cpus
 func ParseCPUs(value float64) (int64, error) {
 	cpu := new(big.Rat).SetFloat64(value)
@@ -15,6 +15,7 @@
 package options
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	cpb "github.com/openconfig/gnoi/containerz"
 	tpb "github.com/openconfig/gnoi/types"
 	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type fakeStream struct{}
@@ -84,6 +86,17 @@ func TestWithPorts(t *testing.T) {
 	}
 }
 
+func TestWithPortMappings(t *testing.T) {
+	p := &options{}
+
+	in := []Port{{Internal: 514, External: 514, Protocol: "udp"}}
+	WithPortMappings(in)(p)
+
+	if diff := cmp.Diff(p.Ports, in); diff != "" {
+		t.Errorf("WithPortMappings(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
 func TestWithEnv(t *testing.T) {
 	p := &options{}
 
@@ -95,6 +108,16 @@ func TestWithEnv(t *testing.T) {
 	}
 }
 
+func TestWithEnvFile(t *testing.T) {
+	p := &options{}
+
+	WithEnvFile("/etc/my-app.env")(p)
+
+	if p.EnvFile != "/etc/my-app.env" {
+		t.Errorf("WithEnvFile(/etc/my-app.env) did not set the env file")
+	}
+}
+
 func TestForce(t *testing.T) {
 	p := &options{}
 
@@ -105,6 +128,16 @@ func TestForce(t *testing.T) {
 	}
 }
 
+func TestWithRemoveVolumes(t *testing.T) {
+	p := &options{}
+
+	WithRemoveVolumes()(p)
+
+	if !p.RemoveVolumes {
+		t.Errorf("WithRemoveVolumes() did not set the remove volumes flag")
+	}
+}
+
 func TestFollow(t *testing.T) {
 	p := &options{}
 
@@ -145,6 +178,36 @@ func TestWithSinceUntil(t *testing.T) {
 	}
 }
 
+func TestWithTail(t *testing.T) {
+	p := &options{}
+
+	WithTail(10)(p)
+
+	if p.Tail != 10 {
+		t.Errorf("WithTail(10) did not set the tail field")
+	}
+}
+
+func TestWithoutStdout(t *testing.T) {
+	p := &options{}
+
+	WithoutStdout()(p)
+
+	if !p.NoStdout {
+		t.Errorf("WithoutStdout() did not set the no stdout flag")
+	}
+}
+
+func TestWithoutStderr(t *testing.T) {
+	p := &options{}
+
+	WithoutStderr()(p)
+
+	if !p.NoStderr {
+		t.Errorf("WithoutStderr() did not set the no stderr flag")
+	}
+}
+
 func TestWithFilter(t *testing.T) {
 	p := &options{}
 
@@ -185,6 +248,57 @@ func TestWithNetwork(t *testing.T) {
 	}
 }
 
+func TestWithNetworks(t *testing.T) {
+	p := &options{}
+
+	WithNetworks([]string{"mgmt", "data"})(p)
+
+	if len(p.Networks) != 2 {
+		t.Errorf("WithNetworks([mgmt data]) did not set the networks field")
+	}
+}
+
+func TestWithStaticIPs(t *testing.T) {
+	p := &options{}
+
+	in := map[string][]string{"data": {"10.0.0.2"}}
+	WithStaticIPs(in)(p)
+
+	if diff := cmp.Diff(p.StaticIPs, in); diff != "" {
+		t.Errorf("WithStaticIPs(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithDNS(t *testing.T) {
+	p := &options{}
+
+	WithDNS([]string{"8.8.8.8"})(p)
+
+	if len(p.DNS) != 1 {
+		t.Errorf("WithDNS([8.8.8.8]) did not set the dns field")
+	}
+}
+
+func TestWithDNSSearch(t *testing.T) {
+	p := &options{}
+
+	WithDNSSearch([]string{"example.com"})(p)
+
+	if len(p.DNSSearch) != 1 {
+		t.Errorf("WithDNSSearch([example.com]) did not set the dns search field")
+	}
+}
+
+func TestWithExtraHosts(t *testing.T) {
+	p := &options{}
+
+	WithExtraHosts([]string{"my-host:10.0.0.1"})(p)
+
+	if len(p.ExtraHosts) != 1 {
+		t.Errorf("WithExtraHosts([my-host:10.0.0.1]) did not set the extra hosts field")
+	}
+}
+
 func TestWithLabels(t *testing.T) {
 	p := &options{}
 
@@ -205,6 +319,36 @@ func TestWithCPUs(t *testing.T) {
 	}
 }
 
+func TestWithCPUShares(t *testing.T) {
+	p := &options{}
+
+	WithCPUShares(512)(p)
+
+	if p.CPUShares != 512 {
+		t.Errorf("WithCPUShares(512) did not set the cpu shares field")
+	}
+}
+
+func TestWithCPUQuota(t *testing.T) {
+	p := &options{}
+
+	WithCPUQuota(50000)(p)
+
+	if p.CPUQuota != 50000 {
+		t.Errorf("WithCPUQuota(50000) did not set the cpu quota field")
+	}
+}
+
+func TestWithCPUPeriod(t *testing.T) {
+	p := &options{}
+
+	WithCPUPeriod(100000)(p)
+
+	if p.CPUPeriod != 100000 {
+		t.Errorf("WithCPUPeriod(100000) did not set the cpu period field")
+	}
+}
+
 func TestWithSoftLimit(t *testing.T) {
 	p := &options{}
 
@@ -225,6 +369,16 @@ func TestWithHardLimit(t *testing.T) {
 	}
 }
 
+func TestWithMemorySwap(t *testing.T) {
+	p := &options{}
+
+	WithMemorySwap(200)(p)
+
+	if p.MemorySwap != 200 {
+		t.Errorf("WithMemorySwap(200) did not set the memory swap field")
+	}
+}
+
 func TestWithDevices(t *testing.T) {
 	p := &options{}
 
@@ -235,6 +389,517 @@ func TestWithDevices(t *testing.T) {
 	}
 }
 
+func TestWithReadOnlyRootFS(t *testing.T) {
+	p := &options{}
+
+	WithReadOnlyRootFS(true)(p)
+
+	if !p.ReadOnlyRootFS {
+		t.Errorf("WithReadOnlyRootFS(true) did not set the read only rootfs field")
+	}
+}
+
+func TestWithTmpfs(t *testing.T) {
+	p := &options{}
+
+	in := map[string]string{"/run": "rw,size=64m"}
+	WithTmpfs(in)(p)
+
+	if diff := cmp.Diff(p.Tmpfs, in); diff != "" {
+		t.Errorf("WithTmpfs(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithBindMounts(t *testing.T) {
+	p := &options{}
+
+	in := []BindMount{{Source: "/var/log", Target: "/logs", ReadOnly: true, Propagation: "rslave", Consistency: "cached"}}
+	WithBindMounts(in)(p)
+
+	if diff := cmp.Diff(p.BindMounts, in); diff != "" {
+		t.Errorf("WithBindMounts(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithSecrets(t *testing.T) {
+	p := &options{}
+
+	in := []Secret{{Name: "token", Data: []byte("s3cr3t"), Target: "/etc/app/token"}}
+	WithSecrets(in)(p)
+
+	if diff := cmp.Diff(p.Secrets, in); diff != "" {
+		t.Errorf("WithSecrets(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithHealthCheck(t *testing.T) {
+	p := &options{}
+
+	WithHealthCheck("true", time.Second, 2*time.Second, 3, 4*time.Second)(p)
+
+	want := &HealthCheck{
+		Test:        "true",
+		Interval:    time.Second,
+		Timeout:     2 * time.Second,
+		Retries:     3,
+		StartPeriod: 4 * time.Second,
+	}
+	if diff := cmp.Diff(p.HealthCheck, want); diff != "" {
+		t.Errorf("WithHealthCheck(...) returned diff (-got, +want):\n%s", diff)
+	}
+}
+
+func TestWithPidsLimit(t *testing.T) {
+	p := &options{}
+
+	WithPidsLimit(100)(p)
+
+	if p.PidsLimit != 100 {
+		t.Errorf("WithPidsLimit(100) did not set the pids limit field")
+	}
+}
+
+func TestWithUlimits(t *testing.T) {
+	p := &options{}
+
+	WithUlimits([]*Ulimit{{}})(p)
+
+	if len(p.Ulimits) != 1 {
+		t.Errorf("WithUlimits([]*Ulimit{&Ulimit{}}) did not set the ulimits field")
+	}
+}
+
+func TestWithBlkioWeight(t *testing.T) {
+	p := &options{}
+
+	WithBlkioWeight(500)(p)
+
+	if p.BlkioWeight != 500 {
+		t.Errorf("WithBlkioWeight(500) did not set the blkio weight field")
+	}
+}
+
+func TestWithBlkioDeviceLimits(t *testing.T) {
+	p := &options{}
+
+	WithBlkioDeviceLimits([]*BlkioDeviceLimit{{}})(p)
+
+	if len(p.BlkioDeviceLimits) != 1 {
+		t.Errorf("WithBlkioDeviceLimits([]*BlkioDeviceLimit{&BlkioDeviceLimit{}}) did not set the blkio device limits field")
+	}
+}
+
+func TestWithCpusetCpus(t *testing.T) {
+	p := &options{}
+
+	WithCpusetCpus("0-2,4")(p)
+
+	if p.CpusetCpus != "0-2,4" {
+		t.Errorf("WithCpusetCpus(0-2,4) did not set the cpuset cpus field")
+	}
+}
+
+func TestWithCpusetMems(t *testing.T) {
+	p := &options{}
+
+	WithCpusetMems("0")(p)
+
+	if p.CpusetMems != "0" {
+		t.Errorf("WithCpusetMems(0) did not set the cpuset mems field")
+	}
+}
+
+func TestWithGPUs(t *testing.T) {
+	p := &options{}
+
+	WithGPUs("all", []string{"compute"})(p)
+
+	if p.GPUs != "all" || len(p.GPUCapabilities) != 1 {
+		t.Errorf("WithGPUs(all, [compute]) did not set the gpu fields")
+	}
+}
+
+func TestWithShmSize(t *testing.T) {
+	p := &options{}
+
+	WithShmSize(1024)(p)
+
+	if p.ShmSize != 1024 {
+		t.Errorf("WithShmSize(1024) did not set the shm size field")
+	}
+}
+
+func TestWithOOMScoreAdj(t *testing.T) {
+	p := &options{}
+
+	WithOOMScoreAdj(-500)(p)
+
+	if p.OOMScoreAdj != -500 {
+		t.Errorf("WithOOMScoreAdj(-500) did not set the oom score adj field")
+	}
+}
+
+func TestWithOOMKillDisable(t *testing.T) {
+	p := &options{}
+
+	WithOOMKillDisable(true)(p)
+
+	if !p.OOMKillDisable {
+		t.Errorf("WithOOMKillDisable(true) did not set the oom kill disable field")
+	}
+}
+
+func TestWithSecurityOpts(t *testing.T) {
+	p := &options{}
+
+	WithSecurityOpts([]string{"no-new-privileges"})(p)
+
+	if len(p.SecurityOpts) != 1 {
+		t.Errorf("WithSecurityOpts([no-new-privileges]) did not set the security opts field")
+	}
+}
+
+func TestWithPidMode(t *testing.T) {
+	p := &options{}
+
+	WithPidMode("container:my-container")(p)
+
+	if p.PidMode != "container:my-container" {
+		t.Errorf("WithPidMode(container:my-container) did not set the pid mode field")
+	}
+}
+
+func TestWithIpcMode(t *testing.T) {
+	p := &options{}
+
+	WithIpcMode("host")(p)
+
+	if p.IpcMode != "host" {
+		t.Errorf("WithIpcMode(host) did not set the ipc mode field")
+	}
+}
+
+func TestWithUsernsMode(t *testing.T) {
+	p := &options{}
+
+	WithUsernsMode("host")(p)
+
+	if p.UsernsMode != "host" {
+		t.Errorf("WithUsernsMode(host) did not set the userns mode field")
+	}
+}
+
+func TestWithGroupAdd(t *testing.T) {
+	p := &options{}
+
+	in := []string{"dialout", "20"}
+	WithGroupAdd(in)(p)
+
+	if diff := cmp.Diff(p.GroupAdd, in); diff != "" {
+		t.Errorf("WithGroupAdd(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithSysctls(t *testing.T) {
+	p := &options{}
+
+	in := map[string]string{"net.core.somaxconn": "1024"}
+	WithSysctls(in)(p)
+
+	if diff := cmp.Diff(p.Sysctls, in); diff != "" {
+		t.Errorf("WithSysctls(%v) returned diff (-got, +want):\n%s", in, diff)
+	}
+}
+
+func TestWithWorkingDir(t *testing.T) {
+	p := &options{}
+
+	WithWorkingDir("/app")(p)
+
+	if p.WorkingDir != "/app" {
+		t.Errorf("WithWorkingDir(/app) did not set the working dir field")
+	}
+}
+
+func TestWithPrivileged(t *testing.T) {
+	p := &options{}
+
+	WithPrivileged(true)(p)
+
+	if !p.Privileged {
+		t.Errorf("WithPrivileged(true) did not set the privileged field")
+	}
+}
+
+func TestWithHostname(t *testing.T) {
+	p := &options{}
+
+	WithHostname("router-1")(p)
+
+	if p.Hostname != "router-1" {
+		t.Errorf("WithHostname(router-1) did not set the hostname field")
+	}
+}
+
+func TestWithDomainname(t *testing.T) {
+	p := &options{}
+
+	WithDomainname("example.net")(p)
+
+	if p.Domainname != "example.net" {
+		t.Errorf("WithDomainname(example.net) did not set the domainname field")
+	}
+}
+
+func TestWithEntrypoint(t *testing.T) {
+	p := &options{}
+
+	WithEntrypoint("")(p)
+
+	if p.Entrypoint == nil || *p.Entrypoint != "" {
+		t.Errorf("WithEntrypoint(\"\") did not set the entrypoint field")
+	}
+}
+
+func TestWithTTY(t *testing.T) {
+	p := &options{}
+
+	WithTTY(false)(p)
+
+	if p.TTY == nil || *p.TTY {
+		t.Errorf("WithTTY(false) did not set the tty field")
+	}
+}
+
+func TestWithInit(t *testing.T) {
+	p := &options{}
+
+	WithInit(true)(p)
+
+	if p.Init == nil || !*p.Init {
+		t.Errorf("WithInit(true) did not set the init field")
+	}
+}
+
+func TestWithStdinOpen(t *testing.T) {
+	p := &options{}
+
+	WithStdinOpen(true)(p)
+
+	if !p.StdinOpen {
+		t.Errorf("WithStdinOpen(true) did not set the stdin open field")
+	}
+}
+
+func TestWithExpectedDigest(t *testing.T) {
+	p := &options{}
+
+	WithExpectedDigest("sha256:abcd")(p)
+
+	if p.ExpectedDigest != "sha256:abcd" {
+		t.Errorf("WithExpectedDigest(sha256:abcd) did not set the expected digest field")
+	}
+}
+
+func TestWithAssignedAddresses(t *testing.T) {
+	p := &options{}
+
+	addrs := map[string][]string{}
+	WithAssignedAddresses(addrs)(p)
+	p.Addresses["bridge"] = []string{"172.17.0.2"}
+
+	if len(addrs) != 1 {
+		t.Errorf("WithAssignedAddresses(addrs) did not set the addresses field to addrs")
+	}
+}
+
+func TestWithPrivilegedPortThreshold(t *testing.T) {
+	p := &options{}
+
+	WithPrivilegedPortThreshold(1024)(p)
+
+	if p.PrivilegedPortThreshold != 1024 {
+		t.Errorf("WithPrivilegedPortThreshold(1024) did not set the privileged port threshold field")
+	}
+}
+
+func TestWithAllowPrivilegedPorts(t *testing.T) {
+	p := &options{}
+
+	WithAllowPrivilegedPorts()(p)
+
+	if !p.AllowPrivilegedPorts {
+		t.Errorf("WithAllowPrivilegedPorts() did not set the allow privileged ports field")
+	}
+}
+
+func TestWithConcurrency(t *testing.T) {
+	p := &options{}
+
+	WithConcurrency(4)(p)
+
+	if p.Concurrency != 4 {
+		t.Errorf("WithConcurrency(4) did not set the concurrency field")
+	}
+}
+
+func TestWithGrantPluginPrivileges(t *testing.T) {
+	p := &options{}
+
+	WithGrantPluginPrivileges()(p)
+
+	if !p.GrantPluginPrivileges {
+		t.Errorf("WithGrantPluginPrivileges() did not set the grant plugin privileges field")
+	}
+}
+
+func TestWithPerImageUsage(t *testing.T) {
+	p := &options{}
+
+	WithPerImageUsage()(p)
+
+	if !p.PerImageUsage {
+		t.Errorf("WithPerImageUsage() did not set the per image usage field")
+	}
+}
+
+func TestWithLogConfig(t *testing.T) {
+	p := &options{}
+	opts := map[string]string{"max-size": "10m", "max-file": "3"}
+
+	WithLogConfig("json-file", opts)(p)
+
+	if p.LogDriver != "json-file" {
+		t.Errorf("WithLogConfig(json-file) did not set the log driver field")
+	}
+	if diff := cmp.Diff(opts, p.LogOptions); diff != "" {
+		t.Errorf("WithLogConfig() did not set the log options field, diff(-want, +got):\n%s", diff)
+	}
+}
+
+func TestWithDryRun(t *testing.T) {
+	p := &options{}
+	plan := &structpb.Struct{}
+
+	WithDryRun(plan)(p)
+
+	if p.DryRun != plan {
+		t.Errorf("WithDryRun(plan) did not set the dry run field")
+	}
+}
+
+func TestWithPlatform(t *testing.T) {
+	p := &options{}
+
+	WithPlatform("linux", "arm", "v7")(p)
+
+	want := &Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	if diff := cmp.Diff(p.Platform, want); diff != "" {
+		t.Errorf("WithPlatform(linux, arm, v7) returned diff (-got, +want):\n%s", diff)
+	}
+}
+
+func TestWithPullPolicy(t *testing.T) {
+	p := &options{}
+
+	WithPullPolicy(PullAlways)(p)
+
+	if p.PullPolicy != PullAlways {
+		t.Errorf("WithPullPolicy(PullAlways) did not set the pull policy field")
+	}
+}
+
+func TestWithWaitHealthy(t *testing.T) {
+	p := &options{}
+
+	WithWaitHealthy(time.Minute)(p)
+
+	if p.WaitHealthy != time.Minute {
+		t.Errorf("WithWaitHealthy(1m) did not set the wait healthy field")
+	}
+}
+
+func TestWithStopGracePeriod(t *testing.T) {
+	p := &options{}
+
+	WithStopGracePeriod(30)(p)
+
+	if p.StopGracePeriod != 30 {
+		t.Errorf("WithStopGracePeriod(30) did not set the stop grace period field")
+	}
+}
+
+func TestWithStopSignal(t *testing.T) {
+	p := &options{}
+
+	WithStopSignal("SIGINT")(p)
+
+	if p.StopSignal != "SIGINT" {
+		t.Errorf("WithStopSignal(SIGINT) did not set the stop signal field")
+	}
+}
+
+func TestWithStopTimeout(t *testing.T) {
+	p := &options{}
+
+	WithStopTimeout(30)(p)
+
+	if p.StopTimeout == nil || *p.StopTimeout != 30 {
+		t.Errorf("WithStopTimeout(30) did not set the stop timeout field")
+	}
+}
+
+func TestWithIdempotentStart(t *testing.T) {
+	p := &options{}
+
+	WithIdempotentStart()(p)
+
+	if !p.IdempotentStart {
+		t.Errorf("WithIdempotentStart() did not set the idempotent start field")
+	}
+}
+
+func TestWithAcknowledgeDockerSocket(t *testing.T) {
+	p := &options{}
+
+	WithAcknowledgeDockerSocket()(p)
+
+	if !p.AcknowledgeDockerSocket {
+		t.Errorf("WithAcknowledgeDockerSocket() did not set the acknowledge docker socket field")
+	}
+}
+
+func TestWithBuildArgs(t *testing.T) {
+	p := &options{}
+
+	WithBuildArgs(map[string]string{"VERSION": "1.2"})(p)
+
+	if diff := cmp.Diff(map[string]string{"VERSION": "1.2"}, p.BuildArgs); diff != "" {
+		t.Errorf("WithBuildArgs(VERSION=1.2) returned diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWithBuildOutput(t *testing.T) {
+	p := &options{}
+	buf := &bytes.Buffer{}
+
+	WithBuildOutput(buf)(p)
+
+	if p.BuildOutput != buf {
+		t.Errorf("WithBuildOutput(buf) did not set the build output field")
+	}
+}
+
+func TestWithRuntime(t *testing.T) {
+	p := &options{}
+
+	WithRuntime("runsc")(p)
+
+	if p.Runtime != "runsc" {
+		t.Errorf("WithRuntime(runsc) did not set the runtime field")
+	}
+}
+
 func TestApplyOptions(t *testing.T) {
 	tests := []struct {
 		inOpts []Option
@@ -0,0 +1,103 @@
+// Package podman implements a container manager for podman orchestration. Podman is driven
+// through its docker-compatible API, so the docker manager does the bulk of the work and this
+// package only accounts for the differences between the two runtimes.
+package podman
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/openconfig/containerz/containers/docker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	options "github.com/openconfig/containerz/containers"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// rootfulSocket is the podman API socket used when running as root.
+const rootfulSocket = "/run/podman/podman.sock"
+
+// Socket returns the address of the podman API socket for the current user. Rootless podman
+// serves its API from the user's runtime directory.
+func Socket() string {
+	if os.Geteuid() != 0 {
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			return "unix://" + filepath.Join(dir, "podman", "podman.sock")
+		}
+	}
+	return "unix://" + rootfulSocket
+}
+
+// Manager is a podman container orchestration manager.
+type Manager struct {
+	*docker.Manager
+}
+
+var _ options.ContainerManager = (*Manager)(nil)
+
+// New builds a new podman manager given a client connected to the podman API socket.
+func New(cli docker.Client, opts ...docker.Option) *Manager {
+	return &Manager{
+		Manager: docker.New(&client{Client: cli}, opts...),
+	}
+}
+
+// ContainerStart starts a container in the same way as the docker manager. Options that podman
+// does not honour are rejected rather than silently ignored.
+func (m *Manager) ContainerStart(ctx context.Context, imageName, tag, cmd string, opts ...options.Option) (string, error) {
+	if err := checkOptions(opts...); err != nil {
+		return "", err
+	}
+	return m.Manager.ContainerStart(ctx, imageName, tag, cmd, opts...)
+}
+
+// ContainerUpdate updates a container in the same way as the docker manager. Options that podman
+// does not honour are rejected before the container is replaced.
+func (m *Manager) ContainerUpdate(ctx context.Context, instance, imageName, tag, cmd string, async bool, opts ...options.Option) (string, error) {
+	if err := checkOptions(opts...); err != nil {
+		return "", err
+	}
+	return m.Manager.ContainerUpdate(ctx, instance, imageName, tag, cmd, async, opts...)
+}
+
+// checkOptions returns an Unimplemented error if opts request a setting podman does not honour.
+func checkOptions(opts ...options.Option) error {
+	if optionz := options.ApplyOptions(opts...); optionz.GPUs != "" {
+		return errGPURequests
+	}
+	return nil
+}
+
+// errGPURequests is returned for containers requesting GPUs, which podman ignores.
+var errGPURequests = status.Error(codes.Unimplemented, "GPU requests are not supported by podman; use CDI devices instead")
+
+// client adapts the requests sent to podman's docker-compatible API.
+type client struct {
+	docker.Client
+}
+
+// ContainerCreate translates the host config into the form podman expects before creating the
+// container.
+func (c *client) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	if hostConfig != nil {
+		// Every path creating a container goes through here, whatever options it was built from.
+		if len(hostConfig.DeviceRequests) > 0 {
+			return container.CreateResponse{}, errGPURequests
+		}
+		switch hostConfig.RestartPolicy.Name {
+		case container.RestartPolicyUnlessStopped:
+			// Containers recreated from a docker-created config may carry unless-stopped, which
+			// podman treats as always.
+			hostConfig.RestartPolicy.Name = container.RestartPolicyAlways
+		case container.RestartPolicyDisabled, container.RestartPolicyAlways, container.RestartPolicyOnFailure, "":
+		default:
+			return container.CreateResponse{}, status.Errorf(codes.Unimplemented, "restart policy %q is not supported by podman", hostConfig.RestartPolicy.Name)
+		}
+	}
+
+	return c.Client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+}
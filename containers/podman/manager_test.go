@@ -0,0 +1,199 @@
+package podman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/openconfig/containerz/containers/docker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	options "github.com/openconfig/containerz/containers"
+	cpb "github.com/openconfig/gnoi/containerz"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// fakeStartingPodman implements the podman API calls made when starting a container. Calls
+// outside of these panic through the nil embedded client.
+type fakeStartingPodman struct {
+	docker.Client
+	summaries []image.Summary
+
+	Cmd    []string
+	Policy container.RestartPolicy
+}
+
+func (f *fakeStartingPodman) ClientVersion() string {
+	return "1.41"
+}
+
+func (f *fakeStartingPodman) ImageList(context.Context, image.ListOptions) ([]image.Summary, error) {
+	return f.summaries, nil
+}
+
+func (f *fakeStartingPodman) ContainerList(context.Context, container.ListOptions) ([]types.Container, error) {
+	return nil, nil
+}
+
+func (f *fakeStartingPodman) ContainerCreate(_ context.Context, config *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+	f.Cmd = config.Cmd
+	f.Policy = hostConfig.RestartPolicy
+	return container.CreateResponse{ID: containerName}, nil
+}
+
+func (f *fakeStartingPodman) ContainerStart(context.Context, string, container.StartOptions) error {
+	return nil
+}
+
+func TestContainerStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		inOpts    []options.Option
+		wantState *fakeStartingPodman
+		wantErr   error
+	}{
+		{
+			name: "simple",
+			wantState: &fakeStartingPodman{
+				Cmd: []string{"my-cmd"},
+			},
+		},
+		{
+			name: "restart-policy",
+			inOpts: []options.Option{
+				options.WithRestartPolicy(&cpb.StartContainerRequest_Restart{
+					Policy: cpb.StartContainerRequest_Restart_ALWAYS,
+				}),
+			},
+			wantState: &fakeStartingPodman{
+				Cmd:    []string{"my-cmd"},
+				Policy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+			},
+		},
+		{
+			name:    "gpus",
+			inOpts:  []options.Option{options.WithGPUs("all", nil)},
+			wantErr: status.Error(codes.Unimplemented, "GPU requests are not supported by podman; use CDI devices instead"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsp := &fakeStartingPodman{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			mgr := New(fsp)
+
+			if _, err := mgr.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", tc.inOpts...); err != nil {
+				if tc.wantErr != nil {
+					if diff := cmp.Diff(tc.wantErr, err, cmpopts.EquateErrors()); diff != "" {
+						t.Errorf("ContainerStart(%+v) returned unexpected error(-want, got):\n %s", tc.inOpts, diff)
+					}
+					return
+				}
+				t.Errorf("ContainerStart(%+v) returned error: %v", tc.inOpts, err)
+			}
+			if tc.wantErr != nil {
+				t.Fatalf("ContainerStart(%+v) did not return an error, want %v", tc.inOpts, tc.wantErr)
+			}
+
+			if diff := cmp.Diff(tc.wantState, fsp, cmpopts.IgnoreUnexported(fakeStartingPodman{}), cmpopts.IgnoreFields(fakeStartingPodman{}, "Client")); diff != "" {
+				t.Errorf("ContainerStart(%+v) returned diff(-want, +got):\n%s", tc.inOpts, diff)
+			}
+		})
+	}
+}
+
+func TestUnsupportedGPUs(t *testing.T) {
+	gpus := options.WithGPUs("all", nil)
+	wantErr := status.Error(codes.Unimplemented, "GPU requests are not supported by podman; use CDI devices instead")
+
+	tests := []struct {
+		name string
+		call func(*Manager) error
+	}{
+		{
+			name: "update",
+			call: func(mgr *Manager) error {
+				_, err := mgr.ContainerUpdate(context.Background(), "my-instance", "my-image", "my-tag", "my-cmd", false, gpus)
+				return err
+			},
+		},
+		{
+			// Paths bypassing the options check are caught when creating the container.
+			name: "docker-start",
+			call: func(mgr *Manager) error {
+				_, err := mgr.Manager.ContainerStart(context.Background(), "my-image", "my-tag", "my-cmd", gpus)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsp := &fakeStartingPodman{
+				summaries: []image.Summary{{RepoTags: []string{"my-image:my-tag"}}},
+			}
+			if diff := cmp.Diff(wantErr, tc.call(New(fsp)), cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("%s with GPUs returned unexpected error(-want, got):\n %s", tc.name, diff)
+			}
+			if fsp.Cmd != nil {
+				t.Errorf("%s with GPUs created a container running %v, want none", tc.name, fsp.Cmd)
+			}
+		})
+	}
+}
+
+func TestContainerCreateRestartPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		inPolicy   container.RestartPolicyMode
+		wantPolicy container.RestartPolicyMode
+		wantErr    bool
+	}{
+		{
+			name:       "unless-stopped",
+			inPolicy:   container.RestartPolicyUnlessStopped,
+			wantPolicy: container.RestartPolicyAlways,
+		},
+		{
+			name:       "on-failure",
+			inPolicy:   container.RestartPolicyOnFailure,
+			wantPolicy: container.RestartPolicyOnFailure,
+		},
+		{
+			name:     "unknown",
+			inPolicy: "sometimes",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fsp := &fakeStartingPodman{}
+			cli := &client{Client: fsp}
+
+			hostConfig := &container.HostConfig{RestartPolicy: container.RestartPolicy{Name: tc.inPolicy}}
+			_, err := cli.ContainerCreate(context.Background(), &container.Config{}, hostConfig, nil, nil, "my-instance")
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ContainerCreate(%q) returned error %v, want error: %t", tc.inPolicy, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if status.Code(err) != codes.Unimplemented {
+					t.Errorf("ContainerCreate(%q) returned error %v, want code %v", tc.inPolicy, err, codes.Unimplemented)
+				}
+				return
+			}
+
+			if fsp.Policy.Name != tc.wantPolicy {
+				t.Errorf("ContainerCreate(%q) sent restart policy %q, want %q", tc.inPolicy, fsp.Policy.Name, tc.wantPolicy)
+			}
+		})
+	}
+}
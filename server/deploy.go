@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"github.com/openconfig/containerz/chunker"
 	"github.com/openconfig/containerz/containers"
+	commonpb "github.com/openconfig/gnoi/common"
 	cpb "github.com/openconfig/gnoi/containerz"
 )
 
@@ -55,22 +56,7 @@ func (s *Server) Deploy(srv cpb.Containerz_DeployServer) error {
 		return status.Error(codes.Unavailable, "must send send a TransferImage message first")
 	case *cpb.DeployRequest_ImageTransfer:
 		if req.ImageTransfer.GetRemoteDownload() != nil {
-			opts := []options.Option{
-				options.WithStream(srv),
-				options.WithRegistryAuth(req.ImageTransfer.GetRemoteDownload().GetCredentials()),
-			}
-			if err := s.mgr.ImagePull(srv.Context(), req.ImageTransfer.GetName(), req.ImageTransfer.GetTag(), opts...); err != nil {
-				return err
-			}
-
-			return srv.Send(&cpb.DeployResponse{
-				Response: &cpb.DeployResponse_ImageTransferSuccess{
-					ImageTransferSuccess: &cpb.ImageTransferSuccess{
-						Name: req.ImageTransfer.GetName(),
-						Tag:  req.ImageTransfer.GetTag(),
-					},
-				},
-			})
+			return s.handleRemoteDownload(srv.Context(), srv, req.ImageTransfer)
 		}
 
 		return s.handleImageTransfer(srv.Context(), srv, req.ImageTransfer)
@@ -80,6 +66,50 @@ func (s *Server) Deploy(srv cpb.Containerz_DeployServer) error {
 
 }
 
+// handleRemoteDownload pulls the image of transfer from a registry rather than receiving it over
+// the stream, reporting the pull's progress to the client. The path of the remote download, if
+// set, is the full reference of the image to pull, which is then tagged as the name and tag of
+// the transfer. Otherwise the name and tag themselves are pulled.
+func (s *Server) handleRemoteDownload(ctx context.Context, srv cpb.Containerz_DeployServer, transfer *cpb.ImageTransfer) error {
+	remote := transfer.GetRemoteDownload()
+	// Registries are reached over HTTP(S), which clients may set as the protocol of the download.
+	switch remote.GetProtocol() {
+	case commonpb.RemoteDownload_UNKNOWN, commonpb.RemoteDownload_HTTP, commonpb.RemoteDownload_HTTPS:
+	default:
+		return status.Errorf(codes.Unimplemented, "downloading images over %s is not supported, the path must be a registry reference", remote.GetProtocol())
+	}
+
+	opts := []options.Option{
+		options.WithStream(srv),
+		options.WithRegistryAuth(remote.GetCredentials()),
+	}
+	name, ref, tag := transfer.GetName(), transfer.GetName(), transfer.GetTag()
+	if path := remote.GetPath(); path != "" {
+		ref, tag = path, ""
+		switch {
+		case name == "":
+			name = path
+		case transfer.GetTag() == "":
+			return status.Error(codes.InvalidArgument, "a tag must be supplied with the name of an image pulled by reference")
+		default:
+			opts = append(opts, options.WithTarget(name, transfer.GetTag()))
+		}
+	}
+
+	if err := s.mgr.ImagePull(ctx, ref, tag, opts...); err != nil {
+		return err
+	}
+
+	return srv.Send(&cpb.DeployResponse{
+		Response: &cpb.DeployResponse_ImageTransferSuccess{
+			ImageTransferSuccess: &cpb.ImageTransferSuccess{
+				Name: name,
+				Tag:  transfer.GetTag(),
+			},
+		},
+	})
+}
+
 func (s *Server) handleImageTransfer(ctx context.Context, srv cpb.Containerz_DeployServer, transfer *cpb.ImageTransfer) error {
 	if err := checkDiskSpace(s.tmpLocation, transfer.GetImageSize()); err != nil {
 		return err
@@ -20,6 +20,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -37,6 +38,8 @@ import (
 type fakeContainerManager struct {
 	Image         string
 	Tag           string
+	TargetName    string
+	TargetTag     string
 	Contents      string
 	Cmd           string
 	Instance      string
@@ -45,6 +48,7 @@ type fakeContainerManager struct {
 	Ports         map[uint32]uint32
 	Envs          map[string]string
 	Force         bool
+	Restart       bool
 	Follow        bool
 	All           bool
 	Async         bool
@@ -63,19 +67,49 @@ type fakeContainerManager struct {
 	HardMemory    int64
 	SoftMemory    int64
 
+	PrivilegedPortThreshold uint32
+	PullPolicy              options.PullPolicy
+
 	listVols         []*cpb.ListVolumeResponse
 	listCntMsgs      []*cpb.ListContainerResponse
 	listImgMsgs      []*cpb.ListImageResponse
 	listPluginMsgs   *cpb.ListPluginsResponse
 	createVolumeName string
 	msgs             []string
+	pullProgress     []uint64
 
 	removeError error
+	pullError   error
+}
+
+func (f *fakeContainerManager) Start(context.Context) error {
+	return nil
+}
+
+func (f *fakeContainerManager) Stop(context.Context) error {
+	return nil
 }
 
 func (f *fakeContainerManager) ImagePull(ctx context.Context, image string, tag string, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
 	f.Image = image
 	f.Tag = tag
+	f.TargetName = optionz.TargetName
+	f.TargetTag = optionz.TargetTag
+	if f.pullError != nil {
+		return f.pullError
+	}
+	for _, n := range f.pullProgress {
+		if err := optionz.StreamClient.Send(&cpb.DeployResponse{
+			Response: &cpb.DeployResponse_ImageTransferProgress{
+				ImageTransferProgress: &cpb.ImageTransferProgress{
+					BytesReceived: n,
+				},
+			},
+		}); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -120,6 +154,8 @@ func (f *fakeContainerManager) ContainerStart(_ context.Context, image string, t
 	f.CPU = optionz.CPU
 	f.HardMemory = optionz.HardMemory
 	f.SoftMemory = optionz.SoftMemory
+	f.PrivilegedPortThreshold = optionz.PrivilegedPortThreshold
+	f.PullPolicy = optionz.PullPolicy
 	return "", nil
 }
 
@@ -130,6 +166,14 @@ func (f *fakeContainerManager) ContainerStop(_ context.Context, instance string,
 	return nil
 }
 
+func (f *fakeContainerManager) ContainerRestart(_ context.Context, instance string, _ time.Duration, opts ...options.Option) error {
+	optionz := options.ApplyOptions(opts...)
+	f.Instance = instance
+	f.Force = optionz.Force
+	f.Restart = true
+	return nil
+}
+
 func (f *fakeContainerManager) ContainerList(ctx context.Context, all bool, limit int32, srv options.ListContainerStreamer, opts ...options.Option) error {
 	f.All = all
 	f.Limit = limit
@@ -196,8 +240,9 @@ func (f *fakeContainerManager) PluginList(ctx context.Context, instance string)
 	return f.listPluginMsgs, nil
 }
 
-func (f *fakeContainerManager) PluginRemove(ctx context.Context, instance string) error {
+func (f *fakeContainerManager) PluginRemove(ctx context.Context, instance string, opts ...options.Option) error {
 	f.Instance = instance
+	f.Force = options.ApplyOptions(opts...).Force
 	for _, plugin := range f.listPluginMsgs.GetPlugins() {
 		if plugin.GetInstanceName() == instance {
 			return nil
@@ -251,12 +296,14 @@ func (f *fakeContainerManager) VolumeRemove(ctx context.Context, name string, op
 func TestDeploy(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
-		name          string
-		inOpts        []Option
-		inReqs        []*cpb.DeployRequest
-		wantResponses []*cpb.DeployResponse
-		wantState     *fakeContainerManager
-		wantErr       error
+		name           string
+		inOpts         []Option
+		inReqs         []*cpb.DeployRequest
+		inPullProgress []uint64
+		inPullErr      error
+		wantResponses  []*cpb.DeployResponse
+		wantState      *fakeContainerManager
+		wantErr        error
 	}{
 		{
 			name:    "invalid-protocol",
@@ -291,6 +338,116 @@ func TestDeploy(t *testing.T) {
 				Tag:  "some-tag",
 			}),
 		},
+		{
+			name:   "remote-download-by-reference",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				Name: "some-image",
+				Tag:  "some-tag",
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path: "registry.example.com/team/some-image:v1",
+				},
+			}),
+			inPullProgress: []uint64{100, 250},
+			wantState: &fakeContainerManager{
+				Image:      "registry.example.com/team/some-image:v1",
+				TargetName: "some-image",
+				TargetTag:  "some-tag",
+			},
+			wantResponses: buildResponses(t, &cpb.ImageTransferProgress{
+				BytesReceived: 100,
+			}, &cpb.ImageTransferProgress{
+				BytesReceived: 250,
+			}, &cpb.ImageTransferSuccess{
+				Name: "some-image",
+				Tag:  "some-tag",
+			}),
+		},
+		{
+			name:   "remote-download-by-reference-only",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path: "registry.example.com/team/some-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+				},
+			}),
+			wantState: &fakeContainerManager{
+				Image: "registry.example.com/team/some-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			},
+			wantResponses: buildResponses(t, &cpb.ImageTransferSuccess{
+				Name: "registry.example.com/team/some-image@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			}),
+		},
+		{
+			name:   "remote-download-by-reference-without-tag",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				Name: "some-image",
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path: "registry.example.com/team/some-image:v1",
+				},
+			}),
+			wantErr: status.Error(codes.InvalidArgument, "a tag must be supplied with the name of an image pulled by reference"),
+		},
+		{
+			name:   "remote-download-over-https",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				Name: "some-image",
+				Tag:  "some-tag",
+				RemoteDownload: &commonpb.RemoteDownload{
+					Protocol: commonpb.RemoteDownload_HTTPS,
+				},
+			}),
+			wantState: &fakeContainerManager{
+				Image: "some-image",
+				Tag:   "some-tag",
+			},
+			wantResponses: buildResponses(t, &cpb.ImageTransferSuccess{
+				Name: "some-image",
+				Tag:  "some-tag",
+			}),
+		},
+		{
+			name:   "remote-download-by-reference-over-http",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path:     "registry.example.com/team/some-image:v1",
+					Protocol: commonpb.RemoteDownload_HTTP,
+				},
+			}),
+			wantState: &fakeContainerManager{
+				Image: "registry.example.com/team/some-image:v1",
+			},
+			wantResponses: buildResponses(t, &cpb.ImageTransferSuccess{
+				Name: "registry.example.com/team/some-image:v1",
+			}),
+		},
+		{
+			name:   "remote-download-over-sftp",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				Name: "some-image",
+				Tag:  "some-tag",
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path:     "images.example.com:/images/some-image.tar",
+					Protocol: commonpb.RemoteDownload_SFTP,
+				},
+			}),
+			wantErr: status.Error(codes.Unimplemented, "downloading images over SFTP is not supported, the path must be a registry reference"),
+		},
+		{
+			name:   "remote-download-not-found",
+			inOpts: []Option{WithAddr("localhost:0")},
+			inReqs: buildRequests(t, &cpb.ImageTransfer{
+				RemoteDownload: &commonpb.RemoteDownload{
+					Path: "registry.example.com/team/no-such-image:v1",
+				},
+			}),
+			inPullErr: status.Error(codes.NotFound, "image registry.example.com/team/no-such-image:v1 not found in registry"),
+			wantErr:   status.Error(codes.NotFound, "image registry.example.com/team/no-such-image:v1 not found in registry"),
+		},
 		{
 			name:   "too-much-data-sent",
 			inOpts: []Option{WithAddr("localhost:0"), WithChunkSize(8)},
@@ -385,7 +542,10 @@ func TestDeploy(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			pluginLocation = t.TempDir()
-			fake := &fakeContainerManager{}
+			fake := &fakeContainerManager{
+				pullProgress: tc.inPullProgress,
+				pullError:    tc.inPullErr,
+			}
 			cli, s := startServerAndReturnClient(ctx, t, fake, tc.inOpts)
 			defer s.Halt(ctx)
 
@@ -424,6 +584,10 @@ func TestDeploy(t *testing.T) {
 				}
 
 				msgIndex++
+				// Progress of a remote download is reported without any further requests.
+				if msgIndex >= len(tc.inReqs) {
+					continue
+				}
 				if err := dCli.Send(tc.inReqs[msgIndex]); err != nil {
 					t.Errorf("Send(%v) returned error: %v", tc.inReqs[msgIndex], err)
 				}
@@ -17,6 +17,8 @@ package server
 import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/alts"
+
+	"github.com/openconfig/containerz/containers"
 )
 
 // Option represents an server option.
@@ -43,6 +45,22 @@ func WithChunkSize(chunkSize int) Option {
 	}
 }
 
+// WithPrivilegedPortThreshold rejects containers binding host ports below port. A threshold of 0
+// disables the check.
+func WithPrivilegedPortThreshold(port uint32) Option {
+	return func(s *Server) {
+		s.privilegedPortThreshold = port
+	}
+}
+
+// WithImagePullPolicy sets whether images are pulled before containers are started. If unset,
+// containers are only started from images already present.
+func WithImagePullPolicy(policy options.PullPolicy) Option {
+	return func(s *Server) {
+		s.imagePullPolicy = policy
+	}
+}
+
 // UseALTS sets up the grpc server to use ALTS authentication.
 // See https://cloud.google.com/docs/security/encryption-in-transit/application-layer-transport-security
 // for more information.
@@ -17,6 +17,7 @@ package server
 import (
 	"testing"
 
+	"github.com/openconfig/containerz/containers"
 	"google.golang.org/grpc"
 )
 
@@ -50,6 +51,26 @@ func TestWithChunkSize(t *testing.T) {
 	}
 }
 
+func TestWithPrivilegedPortThreshold(t *testing.T) {
+	s := &Server{}
+
+	WithPrivilegedPortThreshold(1024)(s)
+
+	if s.privilegedPortThreshold != 1024 {
+		t.Errorf("WithPrivilegedPortThreshold(1024) returned %d", s.privilegedPortThreshold)
+	}
+}
+
+func TestWithImagePullPolicy(t *testing.T) {
+	s := &Server{}
+
+	WithImagePullPolicy(options.PullIfNotPresent)(s)
+
+	if s.imagePullPolicy != options.PullIfNotPresent {
+		t.Errorf("WithImagePullPolicy(IfNotPresent) returned %q", s.imagePullPolicy)
+	}
+}
+
 func TestWithGrpcServer(t *testing.T) {
 	s := &Server{}
 
@@ -32,6 +32,7 @@ func TestRemovePlugin(t *testing.T) {
 			},
 			wantState: &fakeContainerManager{
 				Instance: "test",
+				Force:    true,
 			},
 		},
 		{
@@ -48,6 +49,7 @@ func TestRemovePlugin(t *testing.T) {
 			},
 			wantState: &fakeContainerManager{
 				Instance: "test",
+				Force:    true,
 			},
 			wantErr: true,
 		},
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"time"
 
 	"github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
@@ -31,15 +32,7 @@ import (
 )
 
 type containerManager interface {
-
-	// ContainerList list the containers on the target.
-	//
-	// It takes:
-	// all (bool): return all containers regardless of state
-	// limit (int32): return limit number of results.
-	//
-	// It returns an error indicating the result of the operation.
-	ContainerList(context.Context, bool, int32, options.ListContainerStreamer, ...options.Option) error
+	options.ContainerManager
 
 	// ImagePull pulls a container from a registry to this instance of containerz.
 	//
@@ -62,34 +55,14 @@ type containerManager interface {
 	// - tag (string): the container image tag of the container that was pushed
 	ImagePush(context.Context, *os.File, ...options.Option) (string, string, error)
 
-	// ContainerRemove removes an container provided that it is not running.
-	//
-	// It takes:
-	// - container (string): the container name to remove.
-	//
-	// It returns an error indicating if the remove operation succeeded.
-	ContainerRemove(context.Context, string, ...options.Option) error
-
-	// ContainerStart starts a container based on the supplied image and tag.
+	// ContainerRestart restarts a container while preserving its configuration.
 	//
 	// It takes:
-	// - image (string): the image to use
-	// - tag (string): the tag to use
-	// - cmd (string): a command to run.
-	//
-	// It returns an error indicating if the start operation succeeded along with the ID of the
-	// started container.
-	ContainerStart(context.Context, string, string, string, ...options.Option) (string, error)
-
-	// ContainerStop stops a container. If the Force option is passed it will forcefully stop
-	// (kill) the container. A stop timeout can be provided via the context otherwise the
-	// system default will be used.
-	//
-	// It takes:
-	// - instance (string): the instance name of the running container.
+	// - instance (string): the instance name of the container.
+	// - timeout (time.Duration): the time to wait for the container to stop before killing it.
 	//
 	// It returns an error indicating whether the result was successful
-	ContainerStop(context.Context, string, ...options.Option) error
+	ContainerRestart(context.Context, string, time.Duration, ...options.Option) error
 
 	// ContainerUpdates updates an existing container.
 	//
@@ -141,13 +114,14 @@ type containerManager interface {
 	// plugins.
 	PluginList(context.Context, string) (*cpb.ListPluginsResponse, error)
 
-	// PluginRemove removes a plugin from the target.
+	// PluginRemove removes a plugin from the target provided it is not enabled, unless the Force
+	// option is passed.
 	//
 	// It takes:
 	// - instance (string): the instance name of the plugin to remove.
 	//
 	// It returns an error indicating whether the operation was successful or not.
-	PluginRemove(context.Context, string) error
+	PluginRemove(context.Context, string, ...options.Option) error
 
 	// PluginStart starts a plugin on the target.
 	//
@@ -205,6 +179,9 @@ type Server struct {
 	tmpLocation string
 
 	chunkSize int
+
+	privilegedPortThreshold uint32
+	imagePullPolicy         options.PullPolicy
 }
 
 // New constructs a new containerz server
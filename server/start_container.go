@@ -27,7 +27,7 @@ import (
 // should provide one. If the instance name already exists, the target should
 // return an error.
 func (s *Server) StartContainer(ctx context.Context, request *cpb.StartContainerRequest) (*cpb.StartContainerResponse, error) {
-	opts := optionsFromStartContainerRequest(request)
+	opts := s.startOptions(request)
 	resp, err := s.mgr.ContainerStart(ctx, request.GetImageName(), request.GetTag(), request.GetCmd(), opts...)
 	if err != nil {
 		return nil, err
@@ -42,6 +42,19 @@ func (s *Server) StartContainer(ctx context.Context, request *cpb.StartContainer
 	}, nil
 }
 
+// startOptions returns the options to start the container requested with, including the server's
+// port policy.
+func (s *Server) startOptions(request *cpb.StartContainerRequest) []options.Option {
+	opts := optionsFromStartContainerRequest(request)
+	if s.privilegedPortThreshold != 0 {
+		opts = append(opts, options.WithPrivilegedPortThreshold(s.privilegedPortThreshold))
+	}
+	if s.imagePullPolicy != "" {
+		opts = append(opts, options.WithPullPolicy(s.imagePullPolicy))
+	}
+	return opts
+}
+
 func optionsFromStartContainerRequest(request *cpb.StartContainerRequest) []options.Option {
 	var opts []options.Option
 	if len(request.GetPorts()) != 0 {
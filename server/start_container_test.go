@@ -22,6 +22,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"google.golang.org/protobuf/testing/protocmp"
 
+	"github.com/openconfig/containerz/containers"
 	cpb "github.com/openconfig/gnoi/containerz"
 )
 
@@ -80,6 +81,46 @@ func TestContainerStart(t *testing.T) {
 				Ports: map[uint32]uint32{1: 2, 3: 4},
 			},
 		},
+		{
+			name: "privileged-port-threshold",
+			inReq: &cpb.StartContainerRequest{
+				ImageName: "some-image",
+				Tag:       "some-tag",
+				Cmd:       "some-cmd",
+			},
+			inOpts: []Option{WithPrivilegedPortThreshold(1024)},
+			wantResp: &cpb.StartContainerResponse{
+				Response: &cpb.StartContainerResponse_StartOk{
+					StartOk: &cpb.StartOK{},
+				},
+			},
+			wantState: &fakeContainerManager{
+				Image:                   "some-image",
+				Tag:                     "some-tag",
+				Cmd:                     "some-cmd",
+				PrivilegedPortThreshold: 1024,
+			},
+		},
+		{
+			name: "image-pull-policy",
+			inReq: &cpb.StartContainerRequest{
+				ImageName: "some-image",
+				Tag:       "some-tag",
+				Cmd:       "some-cmd",
+			},
+			inOpts: []Option{WithImagePullPolicy(options.PullAlways)},
+			wantResp: &cpb.StartContainerResponse{
+				Response: &cpb.StartContainerResponse_StartOk{
+					StartOk: &cpb.StartOK{},
+				},
+			},
+			wantState: &fakeContainerManager{
+				Image:      "some-image",
+				Tag:        "some-tag",
+				Cmd:        "some-cmd",
+				PullPolicy: options.PullAlways,
+			},
+		},
 		{
 			name: "env+port+instance",
 			inReq: &cpb.StartContainerRequest{
@@ -23,7 +23,8 @@ import (
 
 // StopContainer stops a container. If the container does not exist or is not running
 // this operation returns an error. This operation can, optionally, force
-// (i.e. kill) a container.
+// (i.e. kill) a container. If restart is requested the container is started again
+// immediately after being stopped.
 func (s *Server) StopContainer(ctx context.Context, request *cpb.StopContainerRequest) (*cpb.StopContainerResponse, error) {
 	// TODO (alshabib): Consider adding a timeout to the request or use a containerz default.s
 	opts := []options.Option{}
@@ -32,6 +33,13 @@ func (s *Server) StopContainer(ctx context.Context, request *cpb.StopContainerRe
 		opts = append(opts, options.Force())
 	}
 
+	if request.GetRestart() {
+		if err := s.mgr.ContainerRestart(ctx, request.GetInstanceName(), 0, opts...); err != nil {
+			return nil, err
+		}
+		return &cpb.StopContainerResponse{}, nil
+	}
+
 	if err := s.mgr.ContainerStop(ctx, request.GetInstanceName(), opts...); err != nil {
 		return nil, err
 	}
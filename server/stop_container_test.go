@@ -55,6 +55,18 @@ func TestStop(t *testing.T) {
 				Force:    true,
 			},
 		},
+		{
+			name: "restart",
+			inReq: &cpb.StopContainerRequest{
+				InstanceName: "some-name",
+				Restart:      true,
+			},
+			wantResp: &cpb.StopContainerResponse{},
+			wantState: &fakeContainerManager{
+				Instance: "some-name",
+				Restart:  true,
+			},
+		},
 	}
 
 	for _, tc := range tests {